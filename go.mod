@@ -2,11 +2,17 @@ module github.com/salmonumbrella/notte-cli
 
 go 1.25.5
 
-require github.com/oapi-codegen/runtime v1.1.2
+require (
+	github.com/99designs/keyring v1.2.2
+	github.com/muesli/termenv v0.16.0
+	github.com/oapi-codegen/runtime v1.1.2
+	github.com/spf13/cobra v1.8.0
+	golang.org/x/term v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/99designs/go-keychain v0.0.0-20191008050251-8e49817e8af4 // indirect
-	github.com/99designs/keyring v1.2.2 // indirect
 	github.com/apapsch/go-jsonmerge/v2 v2.0.0 // indirect
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
 	github.com/danieljoos/wincred v1.1.2 // indirect
@@ -18,10 +24,7 @@ require (
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mtibben/percent v0.2.1 // indirect
-	github.com/muesli/termenv v0.16.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
-	github.com/spf13/cobra v1.8.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/term v0.3.0 // indirect
 )
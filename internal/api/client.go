@@ -179,6 +179,12 @@ func (c *NotteClient) Client() *ClientWithResponses {
 	return c.client
 }
 
+// APIKey returns the API key the client authenticates with, for requests
+// made outside the generated client
+func (c *NotteClient) APIKey() string {
+	return c.apiKey
+}
+
 // Context helper for commands
 func DefaultContext() context.Context {
 	return context.Background()
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/salmonumbrella/notte-cli/internal/config"
@@ -25,13 +26,20 @@ var ErrNoAPIKey = errors.New("no API key found. Run 'notte auth login' or set NO
 // GetAPIKey resolves API key from env → keyring → config
 // configPath can be empty to use default
 func GetAPIKey(configPath string) (string, Source, error) {
+	return GetAPIKeyForContext(configPath, "")
+}
+
+// GetAPIKeyForContext resolves the API key for a named context from
+// env → the context's keyring entry → the context's key in config.
+// An empty context name uses the top-level keyring entry and config key.
+func GetAPIKeyForContext(configPath, contextName string) (string, Source, error) {
 	// 1. Check environment variable
 	if key := os.Getenv(EnvAPIKey); key != "" {
 		return key, SourceEnv, nil
 	}
 
 	// 2. Check keyring
-	if key, err := GetKeyringAPIKey(); err == nil && key != "" {
+	if key, err := GetKeyringAPIKeyFor(contextName); err == nil && key != "" {
 		return key, SourceKeyring, nil
 	}
 
@@ -43,9 +51,17 @@ func GetAPIKey(configPath string) (string, Source, error) {
 	} else {
 		cfg, err = config.Load()
 	}
-	if err == nil && cfg.APIKey != "" {
-		return cfg.APIKey, SourceConfig, nil
+	if err == nil {
+		if contextName == "" && cfg.APIKey != "" {
+			return cfg.APIKey, SourceConfig, nil
+		}
+		if ctx := cfg.Contexts[contextName]; contextName != "" && ctx != nil && ctx.APIKey != "" {
+			return ctx.APIKey, SourceConfig, nil
+		}
 	}
 
+	if contextName != "" {
+		return "", "", fmt.Errorf("no API key found for context %q. Run 'notte auth login --context %s' or set NOTTE_API_KEY", contextName, contextName)
+	}
 	return "", "", ErrNoAPIKey
 }
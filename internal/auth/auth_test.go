@@ -146,3 +146,34 @@ func TestGetAPIKey_Priority(t *testing.T) {
 		t.Errorf("config should be fallback: got %q from %q", key, source)
 	}
 }
+
+func TestGetAPIKeyForContext(t *testing.T) {
+	env, cleanup := setupTestAuth(t)
+	defer cleanup()
+
+	_ = env.MockStore.Set("api_key", "default_key")
+	_ = env.MockStore.Set("api_key:staging", "staging_key")
+
+	cfgPath := filepath.Join(env.TempDir, "config.json")
+	_ = os.WriteFile(cfgPath, []byte(`{"api_key": "config_key", "contexts": {"prod": {"api_key": "prod_config_key"}, "dev": {}}}`), 0o600)
+
+	key, source, _ := GetAPIKeyForContext(cfgPath, "staging")
+	if key != "staging_key" || source != SourceKeyring {
+		t.Errorf("expected per-context keyring entry: got %q from %q", key, source)
+	}
+
+	key, source, _ = GetAPIKeyForContext(cfgPath, "prod")
+	if key != "prod_config_key" || source != SourceConfig {
+		t.Errorf("expected context key from config: got %q from %q", key, source)
+	}
+
+	// A context never falls back to the top-level key
+	if _, _, err := GetAPIKeyForContext(cfgPath, "dev"); err == nil {
+		t.Error("expected error for context without a key")
+	}
+
+	key, _, _ = GetAPIKeyForContext(cfgPath, "")
+	if key != "default_key" {
+		t.Errorf("expected default keyring entry without a context, got %q", key)
+	}
+}
@@ -53,17 +53,41 @@ func deleteFromSystemKeyring(key string) error {
 	return ring.Remove(key)
 }
 
+// KeyringKeyFor returns the keyring entry name for a context. The empty
+// context uses the original "api_key" entry.
+func KeyringKeyFor(contextName string) string {
+	if contextName == "" {
+		return KeyringKey
+	}
+	return KeyringKey + ":" + contextName
+}
+
 // GetKeyringAPIKey retrieves API key from OS keychain
 func GetKeyringAPIKey() (string, error) {
-	return defaultKeyring.Get(KeyringKey)
+	return GetKeyringAPIKeyFor("")
 }
 
 // SetKeyringAPIKey stores API key in OS keychain
 func SetKeyringAPIKey(apiKey string) error {
-	return defaultKeyring.Set(KeyringKey, apiKey)
+	return SetKeyringAPIKeyFor("", apiKey)
 }
 
 // DeleteKeyringAPIKey removes API key from OS keychain
 func DeleteKeyringAPIKey() error {
-	return defaultKeyring.Delete(KeyringKey)
+	return DeleteKeyringAPIKeyFor("")
+}
+
+// GetKeyringAPIKeyFor retrieves a context's API key from OS keychain
+func GetKeyringAPIKeyFor(contextName string) (string, error) {
+	return defaultKeyring.Get(KeyringKeyFor(contextName))
+}
+
+// SetKeyringAPIKeyFor stores a context's API key in OS keychain
+func SetKeyringAPIKeyFor(contextName, apiKey string) error {
+	return defaultKeyring.Set(KeyringKeyFor(contextName), apiKey)
+}
+
+// DeleteKeyringAPIKeyFor removes a context's API key from OS keychain
+func DeleteKeyringAPIKeyFor(contextName string) error {
+	return defaultKeyring.Delete(KeyringKeyFor(contextName))
 }
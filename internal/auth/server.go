@@ -11,14 +11,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"os/exec"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
 	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/ui"
 )
 
 // SetupResult contains the result of a browser-based setup
@@ -36,6 +35,7 @@ type SetupServer struct {
 	csrfToken     string
 	oauthState    string
 	baseURL       string
+	contextName   string
 }
 
 // NewSetupServer creates a new setup server
@@ -58,6 +58,12 @@ func NewSetupServer() (*SetupServer, error) {
 	}, nil
 }
 
+// SetContextName makes the server store the received API key under the
+// keyring entry for the named context
+func (s *SetupServer) SetContextName(name string) {
+	s.contextName = name
+}
+
 // Start starts the setup server and opens the browser
 func (s *SetupServer) Start(ctx context.Context) (*SetupResult, error) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -88,7 +94,7 @@ func (s *SetupServer) Start(ctx context.Context) (*SetupResult, error) {
 	}()
 
 	go func() {
-		_ = openBrowser(s.baseURL)
+		_ = ui.Open(s.baseURL)
 	}()
 
 	select {
@@ -293,7 +299,7 @@ func (s *SetupServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save to keyring
-	if err := SetKeyringAPIKey(req.APIKey); err != nil {
+	if err := SetKeyringAPIKeyFor(s.contextName, req.APIKey); err != nil {
 		writeJSON(w, http.StatusOK, map[string]any{
 			"success": false,
 			"error":   fmt.Sprintf("Failed to save credentials: %v", err),
@@ -418,7 +424,7 @@ func (s *SetupServer) handleCallback(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Save to keyring
-		if err := SetKeyringAPIKey(req.Token); err != nil {
+		if err := SetKeyringAPIKeyFor(s.contextName, req.Token); err != nil {
 			writeJSON(w, http.StatusInternalServerError, map[string]any{
 				"success": false,
 				"error":   fmt.Sprintf("Failed to save credentials: %v", err),
@@ -447,20 +453,3 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(data)
 }
-
-func openBrowser(url string) error {
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = exec.Command("open", url)
-	case "linux":
-		cmd = exec.Command("xdg-open", url)
-	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
-	default:
-		return fmt.Errorf("unsupported platform")
-	}
-
-	return cmd.Start()
-}
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+	"github.com/salmonumbrella/notte-cli/internal/replay"
 )
 
 var (
@@ -15,10 +23,16 @@ var (
 	agentsStartPersona        string
 	agentsStartMaxSteps       int
 	agentsStartReasoningModel string
+	agentsStartURL            string
+	agentsStartUseVision      bool
+	agentsStartNotifier       string
+	agentsStartFromOffset     string
 )
 
 var agentID string
 
+var agentsReplayTrim bool
+
 var agentsCmd = &cobra.Command{
 	Use:   "agents",
 	Short: "Manage AI agents",
@@ -28,13 +42,29 @@ var agentsCmd = &cobra.Command{
 var agentsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List running agents",
-	RunE:  runAgentsList,
+	Long: `List agents.
+
+--browser looks up each agent's session, since agents do not report a
+browser type themselves.`,
+	Example: `  notte agents list --active --current-token
+  notte agents list --status closed --since 3d --all
+  notte agents list --browser chrome --limit 20`,
+	RunE: runAgentsList,
 }
 
 var agentsStartCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start a new agent task",
-	RunE:  runAgentsStart,
+	Long: `Start an agent on a task.
+
+--session runs the agent on an existing session, by name or ID; "current"
+picks the current session. With --from-offset, the agent also gathers what
+happened in the session from that step on, so a session explored by hand
+can be handed over to an agent. "latest" uses the session's current offset.`,
+	Example: `  notte agents start --task "Find the cheapest flight" --url https://example.com
+  notte agents start --task "Finish the checkout" --session current --from-offset latest
+  notte agents start --task "..." --notifier @notifier.json --use-vision=false`,
+	RunE: runAgentsStart,
 }
 
 var agentsStatusCmd = &cobra.Command{
@@ -52,13 +82,34 @@ var agentsStopCmd = &cobra.Command{
 var agentsWorkflowCodeCmd = &cobra.Command{
 	Use:   "workflow-code",
 	Short: "Export agent steps as code",
-	RunE:  runAgentWorkflowCode,
+	Long: `Export the agent's steps as code.
+
+Without --target, the API's response is printed as is. --target picks the
+code to generate: python-sdk (the API's Python script), notte-actions (an
+action file for ` + "`notte run`" + `), playwright-ts or go. The last three are
+translated locally from the agent's steps; see ` + "`notte sessions workflow-code --help`" + `.`,
+	Example: `  notte agents workflow-code --id <agent-id> --target notte-actions --out flow.yaml
+  notte agents workflow-code --id <agent-id> --target go --out main.go`,
+	RunE: runAgentWorkflowCode,
 }
 
 var agentsReplayCmd = &cobra.Command{
 	Use:   "replay",
 	Short: "Get replay data for the agent",
-	RunE:  runAgentReplay,
+	Long: `Get the agent's replay.
+
+With --out, the replay is streamed to a file, with progress on stderr.
+--open opens the saved replay in the default application, saving it to a
+temporary file when --out is not set.
+
+An agent's replay covers its whole session. --trim keeps only the frames
+between the agent's replay start and stop offsets, for agents that shared a
+session with other work. The offsets are taken to be frame indices in the
+replay, start inclusive and stop exclusive; a warning is printed when the stop
+offset is past the last frame. Trimming needs an animated WebP replay.`,
+	Example: `  notte agents replay --id <agent-id> --out replay.webp
+  notte agents replay --id <agent-id> --trim --open`,
+	RunE: runAgentReplay,
 }
 
 func init() {
@@ -70,13 +121,22 @@ func init() {
 	agentsCmd.AddCommand(agentsWorkflowCodeCmd)
 	agentsCmd.AddCommand(agentsReplayCmd)
 
+	// List flags
+	addListFlags(agentsListCmd)
+	addListFilterFlags(agentsListCmd, agentStatuses)
+
 	// Start command flags
 	agentsStartCmd.Flags().StringVar(&agentsStartTask, "task", "", "Task for the agent (required)")
-	agentsStartCmd.Flags().StringVar(&agentsStartSession, "session", "", "Session ID to use")
+	agentsStartCmd.Flags().StringVar(&agentsStartSession, "session", "", "Session ID or name to use (\"current\" for the current session)")
 	agentsStartCmd.Flags().StringVar(&agentsStartVault, "vault", "", "Vault ID for credentials")
 	agentsStartCmd.Flags().StringVar(&agentsStartPersona, "persona", "", "Persona ID to use")
 	agentsStartCmd.Flags().IntVar(&agentsStartMaxSteps, "max-steps", 30, "Maximum steps")
 	agentsStartCmd.Flags().StringVar(&agentsStartReasoningModel, "reasoning-model", "", "Reasoning model to use")
+	agentsStartCmd.Flags().StringVar(&agentSchema, "schema", "", "JSON Schema for the answer (JSON, @file, or - for stdin)")
+	agentsStartCmd.Flags().StringVar(&agentsStartURL, "url", "", "URL the agent starts on")
+	agentsStartCmd.Flags().BoolVar(&agentsStartUseVision, "use-vision", true, "Let the agent use screenshots (not all reasoning models support vision)")
+	agentsStartCmd.Flags().StringVar(&agentsStartNotifier, "notifier", "", "Notifier config (JSON, @file, or - for stdin)")
+	agentsStartCmd.Flags().StringVar(&agentsStartFromOffset, "from-offset", "", "Session step the agent gathers context from, or \"latest\" (requires --session)")
 	_ = agentsStartCmd.MarkFlagRequired("task")
 
 	// Status command flags
@@ -90,45 +150,141 @@ func init() {
 	// Workflow-code command flags
 	agentsWorkflowCodeCmd.Flags().StringVar(&agentID, "id", "", "Agent ID (required)")
 	_ = agentsWorkflowCodeCmd.MarkFlagRequired("id")
+	addWorkflowCodeFlags(agentsWorkflowCodeCmd)
 
 	// Replay command flags
 	agentsReplayCmd.Flags().StringVar(&agentID, "id", "", "Agent ID (required)")
 	_ = agentsReplayCmd.MarkFlagRequired("id")
+	addReplayFlags(agentsReplayCmd)
+	agentsReplayCmd.Flags().BoolVar(&agentsReplayTrim, "trim", false, "Keep only the frames between the agent's replay offsets, read as frame indices (requires --out or --open)")
+}
+
+// agentTable lists the columns available to `agents list`
+var agentTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "agent_id"},
+		{Name: "status", Header: "STATUS", Path: "status"},
+		{Name: "session", Header: "SESSION", Path: "session_id"},
+		{Name: "created_at", Header: "CREATED", Path: "created_at"},
+		{Name: "closed_at", Header: "CLOSED", Path: "closed_at"},
+		{Name: "saved", Header: "SAVED", Path: "saved"},
+		{Name: "credit_usage", Header: "CREDITS", Path: "credit_usage"},
+	},
+	Defaults: []string{"id", "status", "session", "created_at"},
 }
 
+// agentStatuses are the statuses accepted by `agents list --status`
+var agentStatuses = []string{"active", "closed"}
+
 func runAgentsList(cmd *cobra.Command, args []string) error {
+	filter, err := newListFilter(agentStatuses, time.Now())
+	if err != nil {
+		return err
+	}
+
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
+	fetch := func(ctx context.Context, page pageRequest) ([]api.AgentResponse, bool, error) {
+		params := &api.ListAgentsParams{Page: page.Page, PageSize: page.PageSize}
+		if filter.onlyActive() {
+			active := true
+			params.OnlyActive = &active
+		}
+		if listCurrentToken {
+			params.OnlyCurrentToken = &listCurrentToken
+		}
+		resp, err := client.Client().ListAgentsWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
 
-	params := &api.ListAgentsParams{}
-	resp, err := client.Client().ListAgentsWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
+
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
 	}
 
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+	// Agents carry no browser type, so --browser resolves it from each
+	// agent's session, once per session
+	browsers := map[string]string{}
+	sessionBrowser := func(id string) (string, error) {
+		if b, ok := browsers[id]; ok {
+			return b, nil
+		}
+		ctx, cancel := GetContextWithTimeout(cmd.Context())
+		defer cancel()
+
+		resp, err := client.Client().SessionStatusWithResponse(ctx, id, &api.SessionStatusParams{})
+		if err != nil {
+			return "", fmt.Errorf("API request failed: %w", err)
+		}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return "", fmt.Errorf("session %s: %w", id, err)
+		}
+		browser := ""
+		if resp.JSON200 != nil && resp.JSON200.BrowserType != nil {
+			browser = string(*resp.JSON200.BrowserType)
+		}
+		browsers[id] = browser
+		return browser, nil
+	}
+
+	var keep itemFilter[api.AgentResponse]
+	if filter.clientSide() {
+		keep = func(a api.AgentResponse) (bool, error) {
+			if !filter.match(string(a.Status), a.CreatedAt) {
+				return false, nil
+			}
+			if len(filter.browsers) == 0 {
+				return true, nil
+			}
+			browser, err := sessionBrowser(a.SessionId)
+			if err != nil {
+				return false, err
+			}
+			return filter.matchBrowser(browser), nil
+		}
+	}
+
+	return runFilteredList(cmd, agentTable, "No running agents.", fetch, keep)
+}
+
+func runAgentsStart(cmd *cobra.Command, args []string) error {
+	schema, err := loadSchema(cmd, agentSchema)
+	if err != nil {
 		return err
 	}
 
-	var items []api.AgentResponse
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
+	session, err := resolveAgentSession(agentsStartSession)
+	if err != nil {
+		return err
 	}
-	if printed, err := PrintListOrEmpty(items, "No running agents."); err != nil {
+	offset, latest, err := parseSessionOffset(agentsStartFromOffset)
+	if err != nil {
 		return err
-	} else if printed {
-		return nil
+	}
+	if agentsStartFromOffset != "" && session == "" {
+		return errors.New("--from-offset requires --session")
 	}
 
-	return GetFormatter().Print(items)
-}
+	var notifier map[string]any
+	if agentsStartNotifier != "" {
+		data, err := readJSONInput(cmd, agentsStartNotifier, "notifier")
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &notifier); err != nil {
+			return fmt.Errorf("invalid notifier: expected a JSON object: %w", err)
+		}
+	}
 
-func runAgentsStart(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
@@ -137,12 +293,39 @@ func runAgentsStart(cmd *cobra.Command, args []string) error {
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
 	defer cancel()
 
+	if latest {
+		resp, err := client.Client().SessionOffsetWithResponse(ctx, session, &api.SessionOffsetParams{})
+		if err != nil {
+			return fmt.Errorf("API request failed: %w", err)
+		}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return err
+		}
+		if resp.JSON200 == nil {
+			return errors.New("empty response from API")
+		}
+		offset = resp.JSON200.Offset
+	}
+
 	body := api.AgentStartJSONRequestBody{
 		Task:      agentsStartTask,
-		SessionId: agentsStartSession,
+		SessionId: session,
 		MaxSteps:  &agentsStartMaxSteps,
 	}
 
+	if agentsStartFromOffset != "" {
+		body.SessionOffset = &offset
+	}
+	if agentsStartURL != "" {
+		body.Url = &agentsStartURL
+	}
+	if cmd.Flags().Changed("use-vision") {
+		body.UseVision = &agentsStartUseVision
+	}
+	if notifier != nil {
+		body.NotifierConfig = &notifier
+	}
+
 	if agentsStartVault != "" {
 		body.VaultId = &agentsStartVault
 	}
@@ -156,6 +339,9 @@ func runAgentsStart(cmd *cobra.Command, args []string) error {
 		}
 		body.ReasoningModel = reasoningModel
 	}
+	if schema != nil {
+		body.ResponseFormat = schema.Raw()
+	}
 
 	params := &api.AgentStartParams{}
 	resp, err := client.Client().AgentStartWithResponse(ctx, params, body)
@@ -167,9 +353,48 @@ func runAgentsStart(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if agentsStartWait && resp.JSON200 != nil {
+		// Keep stdout for the answer; progress goes to stderr
+		_, _ = fmt.Fprintf(os.Stderr, "Started agent %s, waiting for it to finish...\n", resp.JSON200.AgentId)
+		status, err := waitForAgent(cmd.Context(), client, resp.JSON200.AgentId)
+		if err != nil {
+			return err
+		}
+		return printAgentOutcome(status, schema)
+	}
+
 	return GetFormatter().Print(resp.JSON200)
 }
 
+// resolveAgentSession resolves `agents start --session`: a session name or
+// ID, or "current" for the current session
+func resolveAgentSession(ref string) (string, error) {
+	if ref != "current" {
+		return resolveSessionRef(ref), nil
+	}
+	id := getCurrentSessionID()
+	if id == "" {
+		return "", errors.New("no current session: start one with `notte sessions start` or pass a session ID")
+	}
+	return id, nil
+}
+
+// parseSessionOffset parses --from-offset: a step number, or "latest" for the
+// session's current offset, which has to be looked up
+func parseSessionOffset(value string) (offset int, latest bool, err error) {
+	switch value {
+	case "":
+		return 0, false, nil
+	case "latest":
+		return 0, true, nil
+	}
+	offset, err = strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, false, fmt.Errorf("invalid --from-offset: expected a step number or latest, got %q", value)
+	}
+	return offset, false, nil
+}
+
 func runAgentStatus(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
@@ -233,25 +458,56 @@ func runAgentWorkflowCode(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if workflowCodeRequested() {
+		return runWorkflowCodeTarget(cmd, workflowSource{
+			Kind: "agent",
+			ID:   agentID,
+			Script: func(ctx context.Context) (*api.AgentFunctionCodeResponse, error) {
+				return fetchAgentScript(ctx, client)
+			},
+			Steps: func(ctx context.Context) ([]map[string]any, error) {
+				status, err := fetchAgentStatus(ctx, client, agentID)
+				if err != nil || status.Steps == nil {
+					return nil, err
+				}
+				return *status.Steps, nil
+			},
+		})
+	}
+
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
 	defer cancel()
 
+	script, err := fetchAgentScript(ctx, client)
+	if err != nil {
+		return err
+	}
+	return GetFormatter().Print(script)
+}
+
+// fetchAgentScript fetches the agent's code as a standalone workflow
+func fetchAgentScript(ctx context.Context, client *api.NotteClient) (*api.AgentFunctionCodeResponse, error) {
 	params := &api.GetScriptParams{
-		AsWorkflow: true, // Return as standalone workflow
+		AsWorkflow: true,
 	}
 	resp, err := client.Client().GetScriptWithResponse(ctx, agentID, params)
 	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
-
 	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
+		return nil, err
 	}
-
-	return GetFormatter().Print(resp.JSON200)
+	if resp.JSON200 == nil {
+		return nil, errors.New("empty response from API")
+	}
+	return resp.JSON200, nil
 }
 
 func runAgentReplay(cmd *cobra.Command, args []string) error {
+	if agentsReplayTrim && !savingReplay() {
+		return fmt.Errorf("--trim requires --out or --open")
+	}
+
 	client, err := GetClient()
 	if err != nil {
 		return err
@@ -261,6 +517,9 @@ func runAgentReplay(cmd *cobra.Command, args []string) error {
 	defer cancel()
 
 	params := &api.AgentReplayParams{}
+	if savingReplay() {
+		return saveAgentReplay(ctx, client, params)
+	}
 	resp, err := client.Client().AgentReplayWithResponse(ctx, agentID, params)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
@@ -277,3 +536,47 @@ func runAgentReplay(cmd *cobra.Command, args []string) error {
 	}
 	return GetFormatter().Print(result)
 }
+
+// saveAgentReplay streams the agent's replay to disk, trimmed to the agent's
+// replay offsets with --trim
+func saveAgentReplay(ctx context.Context, client *api.NotteClient, params *api.AgentReplayParams) error {
+	fields := map[string]any{"agent_id": agentID}
+	var trim func([]byte) ([]byte, error)
+	if agentsReplayTrim {
+		status, err := client.Client().AgentStatusWithResponse(ctx, agentID, &api.AgentStatusParams{})
+		if err != nil {
+			return fmt.Errorf("API request failed: %w", err)
+		}
+		if err := HandleAPIResponse(status.HTTPResponse); err != nil {
+			return err
+		}
+		if status.JSON200 == nil {
+			return errors.New("empty response from API")
+		}
+		start, stop := status.JSON200.ReplayStartOffset, status.JSON200.ReplayStopOffset
+		trim = func(data []byte) ([]byte, error) {
+			if total, err := replay.Frames(data); err == nil && stop > total {
+				_, _ = fmt.Fprintf(os.Stderr, "Warning: replay stop offset %d is past the replay's %d frame(s); keeping frames %d to the end\n", stop, total, start)
+			}
+			return replay.Trim(data, start, stop)
+		}
+		fields["start_offset"] = start
+		fields["stop_offset"] = stop
+	}
+
+	resp, err := client.Client().AgentReplay(ctx, agentID, params)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := HandleAPIResponse(resp); err != nil {
+		return err
+	}
+
+	path, size, err := downloadReplay(resp, replayOut, agentID, trim)
+	if err != nil {
+		return err
+	}
+	return finishReplay(path, size, fields)
+}
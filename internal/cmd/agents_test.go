@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
@@ -327,3 +328,182 @@ func TestRunAgentStopCancelled(t *testing.T) {
 		t.Errorf("expected cancel message, got %q", stdout)
 	}
 }
+
+func TestRunAgentsList_BrowserFilter(t *testing.T) {
+	server := setupAgentTest(t)
+	resetListFilterFlags(t)
+	server.AddResponse("/agents", 200, `{"items": [
+		{"agent_id": "agent_1", "session_id": "sess_ff", "status": "active", "created_at": "2024-01-01T00:00:00Z"},
+		{"agent_id": "agent_2", "session_id": "sess_cr", "status": "active", "created_at": "2024-01-01T00:00:00Z"},
+		{"agent_id": "agent_3", "session_id": "sess_ff", "status": "closed", "created_at": "2024-01-01T00:00:00Z"}
+	]}`)
+	server.AddResponse("/sessions/sess_ff", 200, `{"session_id": "sess_ff", "status": "active", "browser_type": "firefox"}`)
+	server.AddResponse("/sessions/sess_cr", 200, `{"session_id": "sess_cr", "status": "active", "browser_type": "chromium"}`)
+
+	origFormat := outputFormat
+	t.Cleanup(func() { outputFormat = origFormat })
+	outputFormat = "json"
+
+	listBrowser = []string{"firefox"}
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runAgentsList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "agent_1") || strings.Contains(stdout, "agent_2") || !strings.Contains(stdout, "agent_3") {
+		t.Errorf("expected agents on firefox sessions, got %q", stdout)
+	}
+	if n := len(server.Requests("/sessions/sess_ff")); n != 1 {
+		t.Errorf("expected session lookups to be cached, got %d requests", n)
+	}
+}
+
+// setupAgentsStartTest resets the `agents start` flags for a test
+func setupAgentsStartTest(t *testing.T) *testutil.MockServer {
+	t.Helper()
+	server := setupAgentTest(t)
+	setupSessionFileTest(t)
+	server.AddResponse("/agents/start", 200, `{"agent_id":"agent_1","session_id":"sess_1","status":"RUNNING","created_at":"2020-01-01T00:00:00Z"}`)
+
+	origTask, origSession, origMaxSteps := agentsStartTask, agentsStartSession, agentsStartMaxSteps
+	origURL, origVision, origNotifier, origOffset := agentsStartURL, agentsStartUseVision, agentsStartNotifier, agentsStartFromOffset
+	origSessionID, origFormat := sessionID, outputFormat
+	t.Cleanup(func() {
+		agentsStartTask, agentsStartSession, agentsStartMaxSteps = origTask, origSession, origMaxSteps
+		agentsStartURL, agentsStartUseVision, agentsStartNotifier, agentsStartFromOffset = origURL, origVision, origNotifier, origOffset
+		sessionID, outputFormat = origSessionID, origFormat
+	})
+
+	agentsStartTask = "finish the checkout"
+	agentsStartSession = ""
+	agentsStartMaxSteps = 30
+	agentsStartURL = ""
+	agentsStartUseVision = true
+	agentsStartNotifier = ""
+	agentsStartFromOffset = ""
+	sessionID = ""
+	outputFormat = "json"
+	return server
+}
+
+func agentStartBody(t *testing.T, server *testutil.MockServer) map[string]any {
+	t.Helper()
+	reqs := server.Requests("/agents/start")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 start request, got %d", len(reqs))
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(reqs[0].Body), &body); err != nil {
+		t.Fatalf("invalid request body: %v", err)
+	}
+	return body
+}
+
+func TestRunAgentsStart_AllFields(t *testing.T) {
+	server := setupAgentsStartTest(t)
+	agentsStartSession = "sess_9"
+	agentsStartFromOffset = "4"
+	agentsStartURL = "https://example.com"
+	agentsStartNotifier = `{"type":"email","receiver_email":"me@example.com"}`
+
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolVar(&agentsStartUseVision, "use-vision", true, "")
+	_ = cmd.Flags().Set("use-vision", "false")
+	cmd.SetContext(context.Background())
+
+	_, _ = testutil.CaptureOutput(func() {
+		if err := runAgentsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	body := agentStartBody(t, server)
+	if body["session_id"] != "sess_9" || body["session_offset"] != float64(4) {
+		t.Errorf("expected session and offset, got %v", body)
+	}
+	if body["url"] != "https://example.com" || body["use_vision"] != false {
+		t.Errorf("expected url and use_vision, got %v", body)
+	}
+	notifier, _ := body["notifier_config"].(map[string]any)
+	if notifier["type"] != "email" {
+		t.Errorf("expected notifier config, got %v", body["notifier_config"])
+	}
+}
+
+func TestRunAgentsStart_OmitsUnsetFields(t *testing.T) {
+	server := setupAgentsStartTest(t)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	_, _ = testutil.CaptureOutput(func() {
+		if err := runAgentsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	body := agentStartBody(t, server)
+	if _, ok := body["use_vision"]; ok {
+		t.Errorf("expected use_vision to be left to the API, got %v", body)
+	}
+	for _, key := range []string{"session_offset", "url", "notifier_config"} {
+		if body[key] != nil {
+			t.Errorf("expected no %s, got %v", key, body[key])
+		}
+	}
+}
+
+func TestRunAgentsStart_CurrentSessionLatestOffset(t *testing.T) {
+	server := setupAgentsStartTest(t)
+	t.Setenv("NOTTE_SESSION_ID", "sess_manual")
+	server.AddResponse("/sessions/sess_manual/offset", 200, `{"offset":7}`)
+	agentsStartSession = "current"
+	agentsStartFromOffset = "latest"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	_, _ = testutil.CaptureOutput(func() {
+		if err := runAgentsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	body := agentStartBody(t, server)
+	if body["session_id"] != "sess_manual" || body["session_offset"] != float64(7) {
+		t.Errorf("expected the current session at offset 7, got %v", body)
+	}
+}
+
+func TestRunAgentsStart_OffsetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		session string
+		offset  string
+		want    string
+	}{
+		{"no session", "", "latest", "--from-offset requires --session"},
+		{"not a number", "sess_1", "first", "invalid --from-offset"},
+		{"negative", "sess_1", "-1", "invalid --from-offset"},
+		{"no current session", "current", "", "no current session"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := setupAgentsStartTest(t)
+			t.Setenv("NOTTE_SESSION_ID", "")
+			agentsStartSession = tt.session
+			agentsStartFromOffset = tt.offset
+
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			err := runAgentsStart(cmd, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("expected %q error, got %v", tt.want, err)
+			}
+			if len(server.Requests("/agents/start")) != 0 {
+				t.Error("expected no start request")
+			}
+		})
+	}
+}
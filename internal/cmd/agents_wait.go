@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
+	"github.com/salmonumbrella/notte-cli/internal/validate"
+)
+
+// Exit codes reported by `agents wait` and `agents start --wait`. Any other
+// error exits with 1.
+const (
+	exitAgentFailed  = 2
+	exitAgentTimeout = 3
+)
+
+var (
+	agentsStartWait  bool
+	agentWaitTimeout int
+	agentSchema      string
+)
+
+// Polling backoff for agent status; variables so tests can shorten them
+var (
+	agentPollInitial = time.Second
+	agentPollMax     = 10 * time.Second
+)
+
+// agentStepPreviewLen caps the length of streamed step lines unless verbose
+const agentStepPreviewLen = 200
+
+var agentsWaitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for an agent to finish",
+	Long: `Poll an agent until it finishes, streaming new steps to stderr.
+
+The agent's answer is written to stdout (the full status with -o json).
+With --schema, the answer is validated against a JSON Schema.
+Exit codes: 0 on success, 2 if the agent failed, 3 on --wait-timeout,
+4 if the answer does not match --schema.`,
+	Example: `  notte agents wait --id agent_123
+  notte agents wait --id agent_123 --wait-timeout 300 > answer.txt`,
+	RunE: runAgentWait,
+}
+
+func init() {
+	agentsCmd.AddCommand(agentsWaitCmd)
+
+	agentsWaitCmd.Flags().StringVar(&agentID, "id", "", "Agent ID (required)")
+	agentsWaitCmd.Flags().IntVar(&agentWaitTimeout, "wait-timeout", 900, "Maximum seconds to wait (0 for no limit)")
+	agentsWaitCmd.Flags().StringVar(&agentSchema, "schema", "", "JSON Schema to validate the answer against (JSON, @file, or - for stdin)")
+	_ = agentsWaitCmd.MarkFlagRequired("id")
+
+	agentsStartCmd.Flags().BoolVar(&agentsStartWait, "wait", false, "Wait for the agent to finish and print its answer")
+	agentsStartCmd.Flags().IntVar(&agentWaitTimeout, "wait-timeout", 900, "Maximum seconds to wait with --wait (0 for no limit)")
+}
+
+func runAgentWait(cmd *cobra.Command, args []string) error {
+	schema, err := loadSchema(cmd, agentSchema)
+	if err != nil {
+		return err
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	status, err := waitForAgent(cmd.Context(), client, agentID)
+	if err != nil {
+		return err
+	}
+	return printAgentOutcome(status, schema)
+}
+
+// waitForAgent polls an agent with backoff until it finishes, streaming
+// newly appended steps to stderr
+func waitForAgent(ctx context.Context, client *api.NotteClient, id string) (*api.LegacyAgentStatusResponse, error) {
+	waitCtx := ctx
+	if agentWaitTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, time.Duration(agentWaitTimeout)*time.Second)
+		defer cancel()
+	}
+
+	timedOut := func() error {
+		return &notteErrors.ExitError{
+			Code: exitAgentTimeout,
+			Err:  fmt.Errorf("timed out after %ds waiting for agent %s (it is still running)", agentWaitTimeout, id),
+		}
+	}
+
+	interval := agentPollInitial
+	seen := 0
+	for {
+		status, err := fetchAgentStatus(waitCtx, client, id)
+		if err != nil {
+			if ctx.Err() == nil && errors.Is(waitCtx.Err(), context.DeadlineExceeded) {
+				return nil, timedOut()
+			}
+			return nil, err
+		}
+
+		seen = printAgentSteps(status, seen)
+		if agentFinished(status) {
+			return status, nil
+		}
+
+		select {
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, timedOut()
+		case <-time.After(interval):
+		}
+
+		interval = min(interval*3/2, agentPollMax)
+	}
+}
+
+// fetchAgentStatus performs a single status request
+func fetchAgentStatus(ctx context.Context, client *api.NotteClient, id string) (*api.LegacyAgentStatusResponse, error) {
+	ctx, cancel := GetContextWithTimeout(ctx)
+	defer cancel()
+
+	params := &api.AgentStatusParams{}
+	resp, err := client.Client().AgentStatusWithResponse(ctx, id, params)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, errors.New("empty response from API")
+	}
+	return resp.JSON200, nil
+}
+
+// agentFinished reports whether an agent has reached a terminal state
+func agentFinished(status *api.LegacyAgentStatusResponse) bool {
+	return status.Success != nil || strings.EqualFold(string(status.Status), string(api.AgentStatusClosed))
+}
+
+// printAgentSteps writes steps after the first seen to stderr and returns
+// the new number of seen steps
+func printAgentSteps(status *api.LegacyAgentStatusResponse, seen int) int {
+	if status.Steps == nil {
+		return seen
+	}
+	steps := *status.Steps
+	for i := seen; i < len(steps); i++ {
+		_, _ = fmt.Fprintf(os.Stderr, "[step %d] %s\n", i+1, describeAgentStep(steps[i]))
+	}
+	return max(seen, len(steps))
+}
+
+// describeAgentStep renders a step as a single line of compact JSON
+func describeAgentStep(step map[string]interface{}) string {
+	data, err := json.Marshal(step)
+	if err != nil {
+		return fmt.Sprintf("%v", step)
+	}
+	line := string(data)
+	if !IsVerbose() && len(line) > agentStepPreviewLen {
+		line = line[:agentStepPreviewLen] + "..."
+	}
+	return line
+}
+
+// printAgentOutcome prints the agent's answer and maps failure, or an answer
+// that does not match schema, to an exit code
+func printAgentOutcome(status *api.LegacyAgentStatusResponse, schema *validate.Schema) error {
+	if IsStructuredOutput() {
+		if err := GetFormatter().Print(status); err != nil {
+			return err
+		}
+	} else if status.Answer != nil {
+		_, _ = fmt.Fprintln(os.Stdout, *status.Answer)
+	}
+
+	if status.Success == nil || !*status.Success {
+		return &notteErrors.ExitError{
+			Code: exitAgentFailed,
+			Err:  fmt.Errorf("agent %s did not complete its task successfully", status.AgentId),
+		}
+	}
+
+	answer := ""
+	if status.Answer != nil {
+		answer = *status.Answer
+	}
+	return checkJSONStringSchema(schema, answer, "agent answer")
+}
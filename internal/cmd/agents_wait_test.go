@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setupAgentWaitTest(t *testing.T, timeout int) *testutil.MockServer {
+	t.Helper()
+	server := setupAgentTest(t)
+
+	origInitial, origMax, origTimeout := agentPollInitial, agentPollMax, agentWaitTimeout
+	agentPollInitial = time.Millisecond
+	agentPollMax = 5 * time.Millisecond
+	agentWaitTimeout = timeout
+	t.Cleanup(func() {
+		agentPollInitial, agentPollMax, agentWaitTimeout = origInitial, origMax, origTimeout
+	})
+
+	origFormat := outputFormat
+	outputFormat = "text"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	return server
+}
+
+func agentStatusWithSteps(status, steps, extra string) string {
+	return `{"agent_id":"` + agentIDTest + `","session_id":"sess_1","status":"` + status + `","created_at":"2020-01-01T00:00:00Z","replay_start_offset":0,"replay_stop_offset":0,"task":"t","steps":` + steps + extra + `}`
+}
+
+func TestRunAgentWait_Success(t *testing.T) {
+	server := setupAgentWaitTest(t, 10)
+	server.AddResponseSequence("/agents/"+agentIDTest, 200,
+		agentStatusWithSteps("active", `[{"n":1}]`, ``),
+		agentStatusWithSteps("active", `[{"n":1},{"n":2}]`, ``),
+		agentStatusWithSteps("closed", `[{"n":1},{"n":2},{"n":3}]`, `,"success":true,"answer":"42"`),
+	)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, stderr := testutil.CaptureOutput(func() {
+		if err := runAgentWait(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if strings.TrimSpace(stdout) != "42" {
+		t.Errorf("expected answer on stdout, got %q", stdout)
+	}
+	for _, want := range []string{`[step 1] {"n":1}`, `[step 2] {"n":2}`, `[step 3] {"n":3}`} {
+		if strings.Count(stderr, want) != 1 {
+			t.Errorf("expected %q exactly once on stderr, got %q", want, stderr)
+		}
+	}
+	if n := len(server.Requests("/agents/" + agentIDTest)); n != 3 {
+		t.Errorf("expected 3 status polls, got %d", n)
+	}
+}
+
+func TestRunAgentWait_AgentFailed(t *testing.T) {
+	server := setupAgentWaitTest(t, 10)
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusWithSteps("closed", `[]`, `,"success":false,"answer":"could not log in"`))
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var err error
+	stdout, _ := testutil.CaptureOutput(func() {
+		err = runAgentWait(cmd, nil)
+	})
+
+	if got := notteErrors.ExitCode(err); got != exitAgentFailed {
+		t.Errorf("expected exit code %d, got %d (%v)", exitAgentFailed, got, err)
+	}
+	if !strings.Contains(stdout, "could not log in") {
+		t.Errorf("expected answer on stdout, got %q", stdout)
+	}
+}
+
+func TestRunAgentWait_Timeout(t *testing.T) {
+	server := setupAgentWaitTest(t, 1)
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusWithSteps("active", `[]`, ``))
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var err error
+	testutil.CaptureOutput(func() {
+		err = runAgentWait(cmd, nil)
+	})
+
+	if got := notteErrors.ExitCode(err); got != exitAgentTimeout {
+		t.Errorf("expected exit code %d, got %d (%v)", exitAgentTimeout, got, err)
+	}
+}
+
+func TestRunAgentsStart_Wait(t *testing.T) {
+	server := setupAgentWaitTest(t, 10)
+	server.AddResponse("/agents/start", 200, agentStatusJSON())
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusWithSteps("closed", `[]`, `,"success":true,"answer":"done"`))
+
+	origTask, origWait := agentsStartTask, agentsStartWait
+	agentsStartTask = "do it"
+	agentsStartWait = true
+	t.Cleanup(func() {
+		agentsStartTask, agentsStartWait = origTask, origWait
+	})
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, stderr := testutil.CaptureOutput(func() {
+		if err := runAgentsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if strings.TrimSpace(stdout) != "done" {
+		t.Errorf("expected only the answer on stdout, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "Started agent "+agentIDTest) {
+		t.Errorf("expected start notice on stderr, got %q", stderr)
+	}
+}
+
+func TestRunAgentWait_Schema(t *testing.T) {
+	server := setupAgentWaitTest(t, 10)
+
+	origSchema := agentSchema
+	agentSchema = `{"type":"object","required":["count"],"properties":{"count":{"type":"integer"}}}`
+	t.Cleanup(func() { agentSchema = origSchema })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusWithSteps("closed", `[]`, `,"success":true,"answer":"{\"count\":3}"`))
+	testutil.CaptureOutput(func() {
+		if err := runAgentWait(cmd, nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusWithSteps("closed", `[]`, `,"success":true,"answer":"{\"count\":\"three\"}"`))
+	var err error
+	testutil.CaptureOutput(func() {
+		err = runAgentWait(cmd, nil)
+	})
+	if notteErrors.ExitCode(err) != exitSchemaMismatch || !strings.Contains(err.Error(), "$.count: expected integer, got string") {
+		t.Errorf("expected field-level schema mismatch, got %v", err)
+	}
+
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusWithSteps("closed", `[]`, `,"success":true,"answer":"three"`))
+	testutil.CaptureOutput(func() {
+		err = runAgentWait(cmd, nil)
+	})
+	if notteErrors.ExitCode(err) != exitSchemaMismatch || !strings.Contains(err.Error(), "not valid JSON") {
+		t.Errorf("expected non-JSON answer to fail schema check, got %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/auth"
+	"github.com/salmonumbrella/notte-cli/internal/config"
 )
 
 var authCmd = &cobra.Command{
@@ -25,7 +26,11 @@ You can authenticate in two ways:
   1. Sign in with Notte Console (recommended) - automatically fetches your API key
   2. Enter API key manually - paste your key from console.notte.cc/apikeys
 
-The API key will be stored securely in your system keychain.`,
+The API key will be stored securely in your system keychain. With --context,
+the key is stored for that context (created if needed) and used whenever the
+context is active.`,
+	Example: `  notte auth login
+  notte auth login --context staging`,
 	RunE: runAuthLogin,
 }
 
@@ -49,12 +54,19 @@ func init() {
 }
 
 func runAuthLogin(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	name := cfg.ResolveContextName(contextName)
+
 	PrintInfo("Opening browser for authentication...")
 
 	server, err := auth.NewSetupServer()
 	if err != nil {
 		return fmt.Errorf("failed to initialize auth server: %w", err)
 	}
+	server.SetContextName(name)
 
 	ctx, cancel := context.WithTimeout(cmd.Context(), 5*time.Minute)
 	defer cancel()
@@ -68,24 +80,55 @@ func runAuthLogin(cmd *cobra.Command, args []string) error {
 		return result.Error
 	}
 
-	return PrintResult("API key stored successfully in keychain.", map[string]any{
+	if name == "" {
+		return PrintResult("API key stored successfully in keychain.", map[string]any{
+			"authenticated": true,
+			"source":        "keychain",
+		})
+	}
+
+	if _, ok := cfg.Contexts[name]; !ok {
+		cfg.EnsureContext(name)
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save context %q: %w", name, err)
+		}
+	}
+	return PrintResult(fmt.Sprintf("API key stored successfully in keychain for context %q.", name), map[string]any{
 		"authenticated": true,
 		"source":        "keychain",
+		"context":       name,
 	})
 }
 
 func runAuthLogout(cmd *cobra.Command, args []string) error {
-	if err := auth.DeleteKeyringAPIKey(); err != nil {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	name := cfg.ResolveContextName(contextName)
+
+	if err := auth.DeleteKeyringAPIKeyFor(name); err != nil {
 		return fmt.Errorf("failed to remove API key: %w", err)
 	}
 
-	return PrintResult("API key removed from keychain.", map[string]any{
+	if name == "" {
+		return PrintResult("API key removed from keychain.", map[string]any{
+			"authenticated": false,
+		})
+	}
+	return PrintResult(fmt.Sprintf("API key for context %q removed from keychain.", name), map[string]any{
 		"authenticated": false,
+		"context":       name,
 	})
 }
 
 func runAuthStatus(cmd *cobra.Command, args []string) error {
-	key, source, err := auth.GetAPIKey("")
+	_, name, _, err := activeContext()
+	if err != nil {
+		return err
+	}
+
+	key, source, err := auth.GetAPIKeyForContext("", name)
 	if err != nil {
 		return fmt.Errorf("not authenticated: %w", err)
 	}
@@ -104,6 +147,9 @@ func runAuthStatus(cmd *cobra.Command, args []string) error {
 		"Source":        string(source),
 		"API Key":       masked,
 	}
+	if name != "" {
+		data["Context"] = name
+	}
 
 	return formatter.Print(data)
 }
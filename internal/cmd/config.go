@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/auth"
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+	"github.com/salmonumbrella/notte-cli/internal/validate"
+)
+
+var (
+	configContextAPIURL       string
+	configContextOutputFormat string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI configuration",
+	Long: `Manage CLI configuration and named contexts.
+
+Use 'notte config set' to store defaults for 'notte sessions start', either
+at the top level or per context.
+
+A context holds settings for one account or environment: its API URL,
+default output format and default session options. Each context has its
+own API key in the system keychain (see 'notte auth login --context').
+
+The active context is chosen by --context, then NOTTE_CONTEXT, then the
+context set with 'notte config use-context'.`,
+}
+
+var configGetContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List configured contexts",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigGetContexts,
+}
+
+var configCurrentContextCmd = &cobra.Command{
+	Use:   "current-context",
+	Short: "Show the active context",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigCurrentContext,
+}
+
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context <name>",
+	Short: "Set the current context",
+	Long:  "Set the context used by default. Pass an empty name (\"\") to go back to the top-level settings.",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUseContext,
+}
+
+var configSetContextCmd = &cobra.Command{
+	Use:   "set-context <name>",
+	Short: "Create or update a context",
+	Example: `  notte config set-context staging --api-url https://staging.api.notte.cc
+  notte config set-context prod --output-format json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigSetContext,
+}
+
+var configDeleteContextCmd = &cobra.Command{
+	Use:   "delete-context <name>",
+	Short: "Delete a context and its stored API key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigDeleteContext,
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetContextsCmd)
+	configCmd.AddCommand(configCurrentContextCmd)
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configSetContextCmd)
+	configCmd.AddCommand(configDeleteContextCmd)
+
+	configSetContextCmd.Flags().StringVar(&configContextAPIURL, "api-url", "", "API URL for this context")
+	configSetContextCmd.Flags().StringVar(&configContextOutputFormat, "output-format", "", "Default output format for this context (text, json, yaml, csv, ndjson, ...)")
+}
+
+func runConfigGetContexts(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	active := cfg.ResolveContextName(contextName)
+
+	rows := make([]map[string]any, 0, len(cfg.Contexts))
+	for _, name := range cfg.ContextNames() {
+		ctx := cfg.Contexts[name]
+		current := ""
+		if name == active {
+			current = "*"
+		}
+		rows = append(rows, map[string]any{
+			"current":       current,
+			"name":          name,
+			"api_url":       cfg.APIURLFor(name),
+			"output_format": ctx.OutputFormat,
+		})
+	}
+
+	if printed, err := PrintListOrEmpty(rows, "No contexts configured. Create one with 'notte config set-context <name>'."); err != nil {
+		return err
+	} else if printed {
+		return nil
+	}
+
+	if IsStructuredOutput() {
+		return GetFormatter().Print(rows)
+	}
+	tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+	tableRows := make([]map[string]any, len(rows))
+	for i, r := range rows {
+		tableRows[i] = map[string]any{
+			"CURRENT": r["current"],
+			"NAME":    r["name"],
+			"API URL": r["api_url"],
+			"OUTPUT":  r["output_format"],
+		}
+	}
+	return tf.PrintTable([]string{"CURRENT", "NAME", "API URL", "OUTPUT"}, tableRows)
+}
+
+func runConfigCurrentContext(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	name := cfg.ResolveContextName(contextName)
+	if name == "" {
+		return PrintResult("No context selected; using top-level settings.", map[string]any{"context": ""})
+	}
+	if _, err := cfg.GetContext(name); err != nil {
+		return err
+	}
+	return PrintResult(name, map[string]any{"context": name})
+}
+
+func runConfigUseContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.GetContext(name); err != nil {
+		return err
+	}
+
+	cfg.CurrentContext = name
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if name == "" {
+		return PrintResult("Cleared current context.", map[string]any{"context": ""})
+	}
+	return PrintResult(fmt.Sprintf("Switched to context %q.", name), map[string]any{"context": name})
+}
+
+func runConfigSetContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if name == "" {
+		return errors.New("context name cannot be empty")
+	}
+	if configContextAPIURL != "" {
+		if err := validate.URL(configContextAPIURL); err != nil {
+			return err
+		}
+	}
+	if configContextOutputFormat != "" {
+		if err := validate.OutputFormat(configContextOutputFormat); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+
+	_, existed := cfg.Contexts[name]
+	ctx := cfg.EnsureContext(name)
+	if cmd.Flags().Changed("api-url") {
+		ctx.APIURL = configContextAPIURL
+	}
+	if cmd.Flags().Changed("output-format") {
+		ctx.OutputFormat = configContextOutputFormat
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	verb := "Created"
+	if existed {
+		verb = "Updated"
+	}
+	return PrintResult(fmt.Sprintf("%s context %q.", verb, name), map[string]any{
+		"context":       name,
+		"api_url":       cfg.APIURLFor(name),
+		"output_format": ctx.OutputFormat,
+	})
+}
+
+func runConfigDeleteContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if _, err := cfg.GetContext(name); err != nil {
+		return err
+	}
+
+	confirmed, err := ConfirmAction("context", name)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return PrintResult("Cancelled.", map[string]any{"cancelled": true})
+	}
+
+	delete(cfg.Contexts, name)
+	if cfg.CurrentContext == name {
+		cfg.CurrentContext = ""
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	// The key may never have been stored; a missing entry is not an error
+	_ = auth.DeleteKeyringAPIKeyFor(name)
+
+	return PrintResult(fmt.Sprintf("Deleted context %q.", name), map[string]any{
+		"context": name,
+		"deleted": true,
+	})
+}
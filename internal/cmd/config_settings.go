@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+	"github.com/salmonumbrella/notte-cli/internal/validate"
+)
+
+// sessionsKeyPrefix namespaces session defaults in `notte config` keys
+const sessionsKeyPrefix = "sessions."
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a configuration value.
+
+Session defaults are applied by 'notte sessions start' to any option not given
+on the command line. Keys:
+
+  ` + strings.Join(sessionSettingKeys(), "\n  ") + `
+
+Values are written to the active context (--context, NOTTE_CONTEXT or the
+current context), or to the top-level config when no context is active. The
+scope written to is printed. Defaults in a context take precedence over
+top-level defaults.`,
+	Example: `  notte config set sessions.browser chrome
+  notte config set sessions.viewport_width 1920
+  notte config set sessions.solve_captchas true --context prod`,
+	Args: cobra.ExactArgs(2),
+	RunE: runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show the value in effect for a configuration key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configuration values in effect",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configListCmd)
+}
+
+// configSetting is one resolved key in `config get` / `config list` output
+type configSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+func sessionSettingKeys() []string {
+	keys := make([]string, len(config.SessionDefaultKeys))
+	for i, k := range config.SessionDefaultKeys {
+		keys[i] = sessionsKeyPrefix + k
+	}
+	return keys
+}
+
+// sessionDefaultField strips the sessions. prefix from a config key
+func sessionDefaultField(key string) (string, error) {
+	field, ok := strings.CutPrefix(key, sessionsKeyPrefix)
+	if !ok {
+		return "", fmt.Errorf("unknown config key %q (valid: %s)", key, strings.Join(sessionSettingKeys(), ", "))
+	}
+	return field, nil
+}
+
+// sessionDefaultsScope loads the config and returns the defaults that `config
+// set` and `config unset` write to: those of the active context, the same one
+// `config get` reads, or the top level when no context is active
+func sessionDefaultsScope() (*config.Config, *config.SessionDefaults, string, error) {
+	cfg, name, ctx, err := activeContext()
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if ctx == nil {
+		if cfg.SessionDefaults == nil {
+			cfg.SessionDefaults = &config.SessionDefaults{}
+		}
+		return cfg, cfg.SessionDefaults, "", nil
+	}
+
+	if ctx.SessionDefaults == nil {
+		ctx.SessionDefaults = &config.SessionDefaults{}
+	}
+	return cfg, ctx.SessionDefaults, name, nil
+}
+
+// resolveSetting looks up a session default, preferring the active context
+func resolveSetting(cfg *config.Config, name, field string) (configSetting, bool, error) {
+	setting := configSetting{Key: sessionsKeyPrefix + field}
+
+	if ctx := cfg.Contexts[name]; ctx != nil {
+		value, ok, err := ctx.SessionDefaults.Get(field)
+		if err != nil || ok {
+			setting.Value, setting.Source = value, "context "+name
+			return setting, ok, err
+		}
+	}
+
+	value, ok, err := cfg.SessionDefaults.Get(field)
+	setting.Value, setting.Source = value, "config"
+	return setting, ok, err
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, value := args[0], args[1]
+	field, err := sessionDefaultField(key)
+	if err != nil {
+		return err
+	}
+
+	switch field {
+	case "cdp_url":
+		if err := validate.URL(value); err != nil {
+			return err
+		}
+	case "browser":
+		if !slices.Contains(browserTypes, value) {
+			return fmt.Errorf("invalid browser: expected %s, got %q", strings.Join(browserTypes, "|"), value)
+		}
+	}
+
+	cfg, defaults, scope, err := sessionDefaultsScope()
+	if err != nil {
+		return err
+	}
+	if err := defaults.Set(field, value); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	msg := fmt.Sprintf("Set %s = %s", key, value)
+	if scope != "" {
+		msg += fmt.Sprintf(" in context %q", scope)
+	}
+	return PrintResult(msg, map[string]any{
+		"key":     key,
+		"value":   value,
+		"context": scope,
+	})
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	field, err := sessionDefaultField(key)
+	if err != nil {
+		return err
+	}
+
+	cfg, defaults, scope, err := sessionDefaultsScope()
+	if err != nil {
+		return err
+	}
+	if err := defaults.Set(field, ""); err != nil {
+		return err
+	}
+
+	// Drop empty blocks so the file stays tidy
+	if cfg.SessionDefaults.IsEmpty() {
+		cfg.SessionDefaults = nil
+	}
+	if ctx := cfg.Contexts[scope]; ctx != nil && ctx.SessionDefaults.IsEmpty() {
+		ctx.SessionDefaults = nil
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	msg := fmt.Sprintf("Unset %s", key)
+	if scope != "" {
+		msg += fmt.Sprintf(" in context %q", scope)
+	}
+	return PrintResult(msg, map[string]any{
+		"key":     key,
+		"context": scope,
+	})
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	key := args[0]
+	field, err := sessionDefaultField(key)
+	if err != nil {
+		return err
+	}
+
+	cfg, name, _, err := activeContext()
+	if err != nil {
+		return err
+	}
+	setting, ok, err := resolveSetting(cfg, name, field)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s is not set", key)
+	}
+
+	return PrintResult(setting.Value, map[string]any{
+		"key":    setting.Key,
+		"value":  setting.Value,
+		"source": setting.Source,
+	})
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	cfg, name, _, err := activeContext()
+	if err != nil {
+		return err
+	}
+
+	var settings []configSetting
+	for _, field := range config.SessionDefaultKeys {
+		setting, ok, err := resolveSetting(cfg, name, field)
+		if err != nil {
+			return err
+		}
+		if ok {
+			settings = append(settings, setting)
+		}
+	}
+
+	if printed, err := PrintListOrEmpty(settings, "No configuration values set. Set one with 'notte config set <key> <value>'."); err != nil {
+		return err
+	} else if printed {
+		return nil
+	}
+
+	if IsStructuredOutput() {
+		return GetFormatter().Print(settings)
+	}
+	tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+	rows := make([]map[string]any, len(settings))
+	for i, s := range settings {
+		rows[i] = map[string]any{"KEY": s.Key, "VALUE": s.Value, "SOURCE": s.Source}
+	}
+	return tf.PrintTable([]string{"KEY", "VALUE", "SOURCE"}, rows)
+}
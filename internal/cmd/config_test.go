@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/auth"
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setupContextTest(t *testing.T, content string) *testutil.TestEnv {
+	t.Helper()
+	env := testutil.SetupTestEnv(t)
+
+	tmpDir := t.TempDir()
+	config.SetTestConfigDir(tmpDir)
+	t.Cleanup(func() { config.SetTestConfigDir("") })
+	if content != "" {
+		path, err := config.DefaultConfigPath()
+		if err != nil {
+			t.Fatalf("failed to resolve config path: %v", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("failed to create config dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	auth.SetKeyring(env.MockStore)
+	t.Cleanup(auth.ResetKeyring)
+
+	origContext, origFormat := contextName, outputFormat
+	t.Cleanup(func() { contextName, outputFormat = origContext, origFormat })
+	contextName = ""
+	outputFormat = "text"
+
+	return env
+}
+
+func TestConfigSetAndUseContext(t *testing.T) {
+	setupContextTest(t, "")
+
+	origURL, origFormat := configContextAPIURL, configContextOutputFormat
+	t.Cleanup(func() { configContextAPIURL, configContextOutputFormat = origURL, origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&configContextAPIURL, "api-url", "", "")
+	cmd.Flags().StringVar(&configContextOutputFormat, "output-format", "", "")
+	_ = cmd.Flags().Set("api-url", "https://staging.example.com")
+
+	testutil.CaptureOutput(func() {
+		if err := runConfigSetContext(cmd, []string{"staging"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := runConfigUseContext(cmd, []string{"staging"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.CurrentContext != "staging" || cfg.APIURLFor("staging") != "https://staging.example.com" {
+		t.Errorf("unexpected config after set/use: %+v", cfg)
+	}
+
+	var useErr error
+	testutil.CaptureOutput(func() {
+		useErr = runConfigUseContext(cmd, []string{"missing"})
+	})
+	if useErr == nil || !strings.Contains(useErr.Error(), `context "missing" not found`) {
+		t.Errorf("expected not-found error, got %v", useErr)
+	}
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runConfigGetContexts(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "staging") || !strings.Contains(stdout, "*") {
+		t.Errorf("expected current context marked in listing, got %q", stdout)
+	}
+}
+
+func TestGetClient_UsesContext(t *testing.T) {
+	prod := testutil.NewMockServer()
+	t.Cleanup(func() { prod.Close() })
+	dev := testutil.NewMockServer()
+	t.Cleanup(func() { dev.Close() })
+	prod.AddResponse("/sessions", 200, `{"items":[],"page":1,"page_size":10,"has_next":false,"has_previous":false}`)
+	dev.AddResponse("/sessions", 200, `{"items":[],"page":1,"page_size":10,"has_next":false,"has_previous":false}`)
+
+	env := setupContextTest(t, `{
+		"current_context": "prod",
+		"contexts": {
+			"prod": {"api_url": "`+prod.URL()+`", "output_format": "json"},
+			"dev": {"api_url": "`+dev.URL()+`"}
+		}
+	}`)
+	_ = env.MockStore.Set("api_key:prod", "prod-key")
+	_ = env.MockStore.Set("api_key:dev", "dev-key")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	applyContextOutputFormat(cmd)
+	if outputFormat != "json" {
+		t.Errorf("expected context output format, got %q", outputFormat)
+	}
+
+	testutil.CaptureOutput(func() {
+		if err := runSessionsList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	reqs := prod.Requests("/sessions")
+	if len(reqs) != 1 || reqs[0].Headers.Get("Authorization") != "Bearer prod-key" {
+		t.Errorf("expected request to current context with its key, got %v", reqs)
+	}
+
+	contextName = "dev"
+	testutil.CaptureOutput(func() {
+		if err := runSessionsList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	reqs = dev.Requests("/sessions")
+	if len(reqs) != 1 || reqs[0].Headers.Get("Authorization") != "Bearer dev-key" {
+		t.Errorf("expected --context to override current context, got %v", reqs)
+	}
+
+	contextName = "nope"
+	if _, err := GetClient(); err == nil {
+		t.Error("expected error for unknown context")
+	}
+}
+
+func TestRunSessionsStart_ContextDefaults(t *testing.T) {
+	env := setupContextTest(t, `{
+		"current_context": "work",
+		"contexts": {"work": {"session_defaults": {"browser": "chrome", "viewport_width": 1920, "solve_captchas": true}}}
+	}`)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	t.Cleanup(func() { server.Close() })
+	env.SetEnv("NOTTE_API_URL", server.URL())
+	server.AddResponse("/sessions/start", 200, sessionJSON())
+
+	origBrowser, origVW, origSolve := sessionsStartBrowser, sessionsStartViewportW, sessionsStartSolveCaptchas
+	t.Cleanup(func() {
+		sessionsStartBrowser, sessionsStartViewportW, sessionsStartSolveCaptchas = origBrowser, origVW, origSolve
+	})
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.Flags().StringVar(&sessionsStartBrowser, "browser", "chromium", "")
+	cmd.Flags().IntVar(&sessionsStartViewportW, "viewport-width", 0, "")
+	cmd.Flags().BoolVar(&sessionsStartSolveCaptchas, "solve-captchas", false, "")
+	_ = cmd.Flags().Set("browser", "firefox")
+
+	testutil.CaptureOutput(func() {
+		if err := runSessionsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	reqs := server.Requests("/sessions/start")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	body := reqs[0].Body
+	for _, want := range []string{`"browser_type":"firefox"`, `"viewport_width":1920`, `"solve_captchas":true`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in request body, got %s", want, body)
+		}
+	}
+}
+
+func TestConfigSetGetList(t *testing.T) {
+	setupContextTest(t, `{"contexts": {"prod": {}}}`)
+
+	cmd := &cobra.Command{}
+	testutil.CaptureOutput(func() {
+		if err := runConfigSet(cmd, []string{"sessions.viewport_width", "1280"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := runConfigSet(cmd, []string{"sessions.browser", "chrome"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		contextName = "prod"
+		if err := runConfigSet(cmd, []string{"sessions.viewport_width", "1920"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.SessionDefaults == nil || cfg.SessionDefaults.ViewportWidth != 1280 || cfg.SessionDefaults.Browser != "chrome" {
+		t.Errorf("unexpected top-level defaults: %+v", cfg.SessionDefaults)
+	}
+	if d := cfg.Contexts["prod"].SessionDefaults; d == nil || d.ViewportWidth != 1920 {
+		t.Errorf("unexpected context defaults: %+v", d)
+	}
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runConfigGet(cmd, []string{"sessions.viewport_width"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if strings.TrimSpace(stdout) != "1920" {
+		t.Errorf("expected context value, got %q", stdout)
+	}
+
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runConfigList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "context prod") || !strings.Contains(stdout, "chrome") {
+		t.Errorf("expected merged listing with sources, got %q", stdout)
+	}
+
+	for _, args := range [][]string{{"viewport_width", "1"}, {"sessions.viewport_width", "wide"}, {"sessions.nope", "x"}} {
+		if err := runConfigSet(cmd, args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+
+	testutil.CaptureOutput(func() {
+		if err := runConfigUnset(cmd, []string{"sessions.viewport_width"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	cfg, _ = config.Load()
+	if cfg.Contexts["prod"].SessionDefaults != nil {
+		t.Errorf("expected empty context defaults to be removed, got %+v", cfg.Contexts["prod"].SessionDefaults)
+	}
+}
+
+func TestConfigSet_ActiveContext(t *testing.T) {
+	setupContextTest(t, `{"current_context": "prod", "contexts": {"prod": {}, "dev": {}}}`)
+
+	cmd := &cobra.Command{}
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runConfigSet(cmd, []string{"sessions.viewport_width", "1920"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, `in context "prod"`) {
+		t.Errorf("expected the scope in output, got %q", stdout)
+	}
+
+	t.Setenv(config.EnvContext, "dev")
+	testutil.CaptureOutput(func() {
+		if err := runConfigSet(cmd, []string{"sessions.viewport_width", "800"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+	if cfg.SessionDefaults != nil {
+		t.Errorf("expected no top-level defaults, got %+v", cfg.SessionDefaults)
+	}
+	if d := cfg.Contexts["prod"].SessionDefaults; d == nil || d.ViewportWidth != 1920 {
+		t.Errorf("unexpected prod defaults: %+v", d)
+	}
+	if d := cfg.Contexts["dev"].SessionDefaults; d == nil || d.ViewportWidth != 800 {
+		t.Errorf("unexpected dev defaults: %+v", d)
+	}
+
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runConfigGet(cmd, []string{"sessions.viewport_width"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if strings.TrimSpace(stdout) != "800" {
+		t.Errorf("expected get to read the value just set, got %q", stdout)
+	}
+}
+
+func TestConfigSet_InvalidBrowser(t *testing.T) {
+	setupContextTest(t, "")
+
+	err := runConfigSet(&cobra.Command{}, []string{"sessions.browser", "safari"})
+	if err == nil || !strings.Contains(err.Error(), "invalid browser") {
+		t.Fatalf("expected invalid browser error, got %v", err)
+	}
+}
+
+func TestRunSessionsStart_TopLevelDefaults(t *testing.T) {
+	env := setupContextTest(t, `{
+		"session_defaults": {"browser": "chrome", "viewport_width": 1280, "proxies": true},
+		"current_context": "work",
+		"contexts": {"work": {"session_defaults": {"viewport_width": 1920}}}
+	}`)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	t.Cleanup(func() { server.Close() })
+	env.SetEnv("NOTTE_API_URL", server.URL())
+	server.AddResponse("/sessions/start", 200, sessionJSON())
+
+	origBrowser, origVW, origProxies := sessionsStartBrowser, sessionsStartViewportW, sessionsStartProxies
+	t.Cleanup(func() {
+		sessionsStartBrowser, sessionsStartViewportW, sessionsStartProxies = origBrowser, origVW, origProxies
+	})
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	cmd.Flags().StringVar(&sessionsStartBrowser, "browser", "chromium", "")
+	cmd.Flags().IntVar(&sessionsStartViewportW, "viewport-width", 0, "")
+	cmd.Flags().BoolVar(&sessionsStartProxies, "proxies", false, "")
+	_ = cmd.Flags().Set("proxies", "false")
+
+	testutil.CaptureOutput(func() {
+		if err := runSessionsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	reqs := server.Requests("/sessions/start")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	body := reqs[0].Body
+	for _, want := range []string{`"browser_type":"chrome"`, `"viewport_width":1920`, `"proxies":false`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %s in request body, got %s", want, body)
+		}
+	}
+}
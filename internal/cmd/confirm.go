@@ -23,7 +23,21 @@ func ConfirmAction(resource, id string) (bool, error) {
 
 // ConfirmActionWithIO is the testable version of ConfirmAction.
 func ConfirmActionWithIO(in io.Reader, out io.Writer, resource, id string) (bool, error) {
-	if _, err := fmt.Fprintf(out, "Delete %s %s? This cannot be undone. [y/N]: ", resource, id); err != nil {
+	return ConfirmPromptWithIO(in, out, fmt.Sprintf("Delete %s %s? This cannot be undone.", resource, id))
+}
+
+// ConfirmPrompt asks the user a yes/no question for an action that is not a
+// deletion. Returns true if confirmed, false otherwise.
+func ConfirmPrompt(prompt string) (bool, error) {
+	if skipConfirmation {
+		return true, nil
+	}
+	return ConfirmPromptWithIO(os.Stdin, os.Stderr, prompt)
+}
+
+// ConfirmPromptWithIO is the testable version of ConfirmPrompt.
+func ConfirmPromptWithIO(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	if _, err := fmt.Fprintf(out, "%s [y/N]: ", prompt); err != nil {
 		return false, fmt.Errorf("failed to write prompt: %w", err)
 	}
 
@@ -61,3 +61,27 @@ func TestConfirmActionWithIO_Errors(t *testing.T) {
 		t.Fatal("expected read error")
 	}
 }
+
+func TestConfirmActionWithIO_Prompt(t *testing.T) {
+	var out bytes.Buffer
+	if _, err := ConfirmActionWithIO(strings.NewReader("n\n"), &out, "vault", "vault_123"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := out.String(); got != "Delete vault vault_123? This cannot be undone. [y/N]: " {
+		t.Errorf("unexpected prompt: %q", got)
+	}
+}
+
+func TestConfirmPromptWithIO(t *testing.T) {
+	var out bytes.Buffer
+	ok, err := ConfirmPromptWithIO(strings.NewReader("yes\n"), &out, "Stop 3 session(s)?")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected yes to confirm")
+	}
+	if got := out.String(); got != "Stop 3 session(s)? [y/N]: " {
+		t.Errorf("unexpected prompt: %q", got)
+	}
+}
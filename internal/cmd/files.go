@@ -59,10 +59,10 @@ func init() {
 	// List command flags
 	filesListCmd.Flags().BoolVar(&filesListUploadsFlag, "uploads", true, "List uploaded files")
 	filesListCmd.Flags().BoolVar(&filesListDownloadsFlag, "downloads", false, "List downloaded files from a session")
-	filesListCmd.Flags().StringVar(&filesDownloadSession, "session", "", "Session ID (required for --downloads)")
+	filesListCmd.Flags().StringVar(&filesDownloadSession, "session", "", "Session ID or name (required for --downloads)")
 
 	// Download command flags
-	filesDownloadCmd.Flags().StringVar(&filesDownloadSession, "session", "", "Session ID (required)")
+	filesDownloadCmd.Flags().StringVar(&filesDownloadSession, "session", "", "Session ID or name (required)")
 	filesDownloadCmd.Flags().StringVarP(&filesDownloadOutput, "output", "o", "", "Output file path (defaults to current directory)")
 	_ = filesDownloadCmd.MarkFlagRequired("session")
 }
@@ -85,7 +85,7 @@ func runFilesList(cmd *cobra.Command, args []string) error {
 		defer cancel()
 
 		params := &api.FileListDownloadsParams{}
-		resp, err := client.Client().FileListDownloadsWithResponse(ctx, filesDownloadSession, params)
+		resp, err := client.Client().FileListDownloadsWithResponse(ctx, resolveSessionRef(filesDownloadSession), params)
 		if err != nil {
 			return fmt.Errorf("API request failed: %w", err)
 		}
@@ -198,7 +198,7 @@ func runFilesUpload(cmd *cobra.Command, args []string) error {
 
 	formatter := GetFormatter()
 	if resp.JSON200 != nil && resp.JSON200.Success {
-		if IsJSONOutput() {
+		if IsStructuredOutput() {
 			return formatter.Print(resp.JSON200)
 		}
 		return PrintResult(fmt.Sprintf("File uploaded successfully: %s", filename), map[string]any{
@@ -228,7 +228,7 @@ func runFilesDownload(cmd *cobra.Command, args []string) error {
 	params := &api.FileDownloadParams{}
 	resp, err := client.Client().FileDownloadWithResponse(
 		ctx,
-		filesDownloadSession,
+		resolveSessionRef(filesDownloadSession),
 		filename,
 		params,
 	)
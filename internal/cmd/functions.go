@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
 var (
@@ -148,6 +150,9 @@ func init() {
 	functionsCmd.AddCommand(functionsScheduleCmd)
 	functionsCmd.AddCommand(functionsUnscheduleCmd)
 
+	// List output flags
+	addListFlags(functionsListCmd)
+
 	// Create command flags
 	functionsCreateCmd.Flags().StringVar(&functionsCreateFile, "file", "", "Path to function file (required)")
 	_ = functionsCreateCmd.MarkFlagRequired("file")
@@ -176,6 +181,7 @@ func init() {
 	// Runs command flags
 	functionsRunsCmd.Flags().StringVar(&functionID, "id", "", "Function ID (required)")
 	_ = functionsRunsCmd.MarkFlagRequired("id")
+	addListFlags(functionsRunsCmd)
 
 	// Fork command flags
 	functionsForkCmd.Flags().StringVar(&functionID, "id", "", "Function ID (required)")
@@ -211,38 +217,42 @@ func init() {
 	_ = functionsUnscheduleCmd.MarkFlagRequired("id")
 }
 
+// functionTable lists the columns available to `functions list`
+var functionTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "function_id"},
+		{Name: "name", Header: "NAME", Path: "name"},
+		{Name: "status", Header: "STATUS", Path: "status"},
+		{Name: "version", Header: "VERSION", Path: "latest_version"},
+		{Name: "created_at", Header: "CREATED", Path: "created_at"},
+		{Name: "updated_at", Header: "UPDATED", Path: "updated_at"},
+		{Name: "description", Header: "DESCRIPTION", Path: "description"},
+	},
+	Defaults: []string{"id", "name", "status", "version", "created_at"},
+}
+
 func runFunctionsList(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.ListFunctionsParams{}
-	resp, err := client.Client().ListFunctionsWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
-
-	formatter := GetFormatter()
+	return runPagedList(cmd, functionTable, "No functions found.", func(ctx context.Context, page pageRequest) ([]api.GetFunctionResponse, bool, error) {
+		params := &api.ListFunctionsParams{Page: page.Page, PageSize: page.PageSize}
+		resp, err := client.Client().ListFunctionsWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
 
-	var items []api.GetFunctionResponse
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
-	}
-	if printed, err := PrintListOrEmpty(items, "No functions found."); err != nil {
-		return err
-	} else if printed {
-		return nil
-	}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
 
-	return formatter.Print(items)
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
+	})
 }
 
 func runFunctionsCreate(cmd *cobra.Command, args []string) error {
@@ -439,36 +449,41 @@ func runFunctionRun(cmd *cobra.Command, args []string) error {
 	return GetFormatter().Print(resp.JSON200)
 }
 
+// functionRunTable lists the columns available to `functions runs`
+var functionRunTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "function_run_id"},
+		{Name: "status", Header: "STATUS", Path: "status"},
+		{Name: "session", Header: "SESSION", Path: "session_id"},
+		{Name: "created_at", Header: "CREATED", Path: "created_at"},
+		{Name: "updated_at", Header: "UPDATED", Path: "updated_at"},
+		{Name: "local", Header: "LOCAL", Path: "local"},
+	},
+	Defaults: []string{"id", "status", "session", "created_at"},
+}
+
 func runFunctionRuns(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.ListFunctionRunsByFunctionIdParams{}
-	resp, err := client.Client().ListFunctionRunsByFunctionIdWithResponse(ctx, functionID, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
+	return runPagedList(cmd, functionRunTable, "No function runs found.", func(ctx context.Context, page pageRequest) ([]api.GetFunctionRunResponse, bool, error) {
+		params := &api.ListFunctionRunsByFunctionIdParams{Page: page.Page, PageSize: page.PageSize}
+		resp, err := client.Client().ListFunctionRunsByFunctionIdWithResponse(ctx, functionID, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
 
-	var items []api.GetFunctionRunResponse
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
-	}
-	if printed, err := PrintListOrEmpty(items, "No function runs found."); err != nil {
-		return err
-	} else if printed {
-		return nil
-	}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
 
-	return GetFormatter().Print(items)
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
+	})
 }
 
 func runFunctionFork(cmd *cobra.Command, args []string) error {
@@ -43,6 +43,12 @@ func readJSONInput(cmd *cobra.Command, value string, flagName string) ([]byte, e
 	return []byte(input), nil
 }
 
+// readsStdin reports whether an input flag value reads from stdin
+func readsStdin(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "-" || value == "@-"
+}
+
 func readFromStdin(cmd *cobra.Command, flagName string) ([]byte, error) {
 	in := cmd.InOrStdin()
 	if !stdinHasData(in) {
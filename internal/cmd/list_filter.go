@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	listActive       bool
+	listCurrentToken bool
+	listStatus       []string
+	listBrowser      []string
+	listSince        string
+	listUntil        string
+)
+
+// browserTypes are the browser types reported by the API
+var browserTypes = []string{"chromium", "chrome", "chrome-nightly", "chrome-turbo", "firefox"}
+
+// addListFilterFlags registers the filter flags shared by `sessions list`
+// and `agents list`
+func addListFilterFlags(cmd *cobra.Command, statuses []string) {
+	cmd.Flags().BoolVar(&listActive, "active", false, "Only list active items")
+	cmd.Flags().BoolVar(&listCurrentToken, "current-token", false, "Only list items created with the current API key")
+	cmd.Flags().StringSliceVar(&listStatus, "status", nil, "Only list items with these statuses ("+strings.Join(statuses, "|")+")")
+	cmd.Flags().StringSliceVar(&listBrowser, "browser", nil, "Only list items using these browser types ("+strings.Join(browserTypes, "|")+")")
+	cmd.Flags().StringVar(&listSince, "since", "", "Only list items created after this time (duration like 2h or 3d, date or RFC 3339 timestamp)")
+	cmd.Flags().StringVar(&listUntil, "until", "", "Only list items created before this time (duration like 2h or 3d, date or RFC 3339 timestamp)")
+}
+
+// listFilter holds the client-side part of the filter flags. The API only
+// filters on active state and API key; everything else is checked here.
+type listFilter struct {
+	statuses []string
+	browsers []string
+	since    time.Time
+	until    time.Time
+}
+
+// newListFilter validates the filter flags against the statuses a
+// resource can have. Relative times are resolved against now.
+func newListFilter(statuses []string, now time.Time) (*listFilter, error) {
+	f := &listFilter{}
+
+	for _, s := range listStatus {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if !slices.Contains(statuses, s) {
+			return nil, fmt.Errorf("invalid status %q: expected %s", s, strings.Join(statuses, "|"))
+		}
+		f.statuses = append(f.statuses, s)
+	}
+	if listActive && len(f.statuses) > 0 && !slices.Contains(f.statuses, "active") {
+		return nil, fmt.Errorf("--active cannot be combined with --status %s: it only lists active items", strings.Join(f.statuses, ","))
+	}
+	for _, b := range listBrowser {
+		b = strings.ToLower(strings.TrimSpace(b))
+		if !slices.Contains(browserTypes, b) {
+			return nil, fmt.Errorf("invalid browser %q: expected %s", b, strings.Join(browserTypes, "|"))
+		}
+		f.browsers = append(f.browsers, b)
+	}
+
+	var err error
+	if listSince != "" {
+		if f.since, err = parseTimeFlag(listSince, now); err != nil {
+			return nil, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if listUntil != "" {
+		if f.until, err = parseTimeFlag(listUntil, now); err != nil {
+			return nil, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+	if !f.since.IsZero() && !f.until.IsZero() && f.until.Before(f.since) {
+		return nil, fmt.Errorf("--until (%s) is before --since (%s)", f.until.Format(time.RFC3339), f.since.Format(time.RFC3339))
+	}
+	return f, nil
+}
+
+// onlyActive reports whether the API's active filter should be requested,
+// either with --active or with --status active alone
+func (f *listFilter) onlyActive() bool {
+	return listActive || (len(f.statuses) == 1 && f.statuses[0] == "active")
+}
+
+// clientSide reports whether the filter drops items the API cannot filter
+// out itself, so that every page has to be fetched to find all matches
+func (f *listFilter) clientSide() bool {
+	return len(f.browsers) > 0 || !f.since.IsZero() || !f.until.IsZero() ||
+		(len(f.statuses) > 0 && !(len(f.statuses) == 1 && f.statuses[0] == "active"))
+}
+
+// match checks an item's status and creation time
+func (f *listFilter) match(status string, createdAt time.Time) bool {
+	if len(f.statuses) > 0 && !slices.Contains(f.statuses, strings.ToLower(status)) {
+		return false
+	}
+	if !f.since.IsZero() && createdAt.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && createdAt.After(f.until) {
+		return false
+	}
+	return true
+}
+
+// matchBrowser checks an item's browser type
+func (f *listFilter) matchBrowser(browser string) bool {
+	return len(f.browsers) == 0 || slices.Contains(f.browsers, strings.ToLower(browser))
+}
+
+// parseTimeFlag parses a --since/--until value: a duration before now
+// (see parseDurationFlag), a date, or an RFC 3339 timestamp
+func parseTimeFlag(s string, now time.Time) (time.Time, error) {
+	if d, err := parseDurationFlag(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a duration (2h, 3d), date (2006-01-02) or RFC 3339 timestamp", s)
+}
+
+// parseDurationFlag parses a non-negative duration in Go syntax, plus a
+// "d" suffix for days
+func parseDurationFlag(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.ParseFloat(days, 64); err == nil && n >= 0 {
+			return time.Duration(n * float64(24*time.Hour)), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%q is not a duration (e.g. 10m, 2h, 3d)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetListFilterFlags(t *testing.T) {
+	t.Helper()
+	origActive, origToken, origStatus, origBrowser := listActive, listCurrentToken, listStatus, listBrowser
+	origSince, origUntil := listSince, listUntil
+	t.Cleanup(func() {
+		listActive, listCurrentToken, listStatus, listBrowser = origActive, origToken, origStatus, origBrowser
+		listSince, listUntil = origSince, origUntil
+	})
+	listActive, listCurrentToken, listStatus, listBrowser = false, false, nil, nil
+	listSince, listUntil = "", ""
+}
+
+func TestParseTimeFlag(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2h", now.Add(-2 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"3d", now.Add(-72 * time.Hour)},
+		{"2024-01-10T08:00:00Z", time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)},
+		{"2024-01-10", time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeFlag(tt.in, now)
+		if err != nil {
+			t.Errorf("parseTimeFlag(%q): unexpected error: %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimeFlag(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"yesterday", "-2h", "d", "2024-13-01"} {
+		if _, err := parseTimeFlag(in, now); err == nil {
+			t.Errorf("parseTimeFlag(%q): expected error", in)
+		}
+	}
+}
+
+func TestNewListFilter(t *testing.T) {
+	resetListFilterFlags(t)
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	listStatus = []string{"ACTIVE", "closed"}
+	listBrowser = []string{"firefox"}
+	listSince = "24h"
+	f, err := newListFilter(sessionStatuses, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.onlyActive() {
+		t.Error("expected two statuses not to request only active items")
+	}
+	if !f.match("active", now.Add(-time.Hour)) || f.match("error", now.Add(-time.Hour)) || f.match("closed", now.Add(-48*time.Hour)) {
+		t.Error("unexpected status/time matches")
+	}
+	if !f.matchBrowser("Firefox") || f.matchBrowser("chromium") {
+		t.Error("unexpected browser matches")
+	}
+
+	listStatus, listBrowser, listSince = []string{"active"}, nil, ""
+	if f, err = newListFilter(agentStatuses, now); err != nil || !f.onlyActive() {
+		t.Errorf("expected --status active to request only active items, got %v", err)
+	}
+
+	listStatus = []string{"timed_out"}
+	if _, err := newListFilter(agentStatuses, now); err == nil || !strings.Contains(err.Error(), "invalid status") {
+		t.Errorf("expected invalid status error, got %v", err)
+	}
+
+	listStatus, listBrowser = nil, []string{"webkit"}
+	if _, err := newListFilter(sessionStatuses, now); err == nil || !strings.Contains(err.Error(), "invalid browser") {
+		t.Errorf("expected invalid browser error, got %v", err)
+	}
+
+	listBrowser, listSince, listUntil = nil, "1h", "2h"
+	if _, err := newListFilter(sessionStatuses, now); err == nil || !strings.Contains(err.Error(), "before --since") {
+		t.Errorf("expected inverted range error, got %v", err)
+	}
+}
+
+func TestNewListFilter_ActiveWithStatus(t *testing.T) {
+	resetListFilterFlags(t)
+	now := time.Now()
+
+	listActive = true
+	listStatus = []string{"closed", "error"}
+	if _, err := newListFilter(sessionStatuses, now); err == nil || !strings.Contains(err.Error(), "--active cannot be combined with --status closed,error") {
+		t.Errorf("expected --active conflict error, got %v", err)
+	}
+
+	listStatus = []string{"active", "closed"}
+	if _, err := newListFilter(sessionStatuses, now); err != nil {
+		t.Errorf("expected --status including active to be allowed, got %v", err)
+	}
+}
+
+func TestListFilter_ClientSide(t *testing.T) {
+	tests := []struct {
+		name string
+		f    listFilter
+		want bool
+	}{
+		{"none", listFilter{}, false},
+		{"active only", listFilter{statuses: []string{"active"}}, false},
+		{"closed", listFilter{statuses: []string{"closed"}}, true},
+		{"browser", listFilter{browsers: []string{"firefox"}}, true},
+		{"since", listFilter{since: time.Now()}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.f.clientSide(); got != tt.want {
+			t.Errorf("%s: clientSide() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
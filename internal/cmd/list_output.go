@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+var (
+	listColumns []string
+	listSortBy  string
+)
+
+// addListFlags registers the flags shared by list commands
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&listColumns, "columns", nil, "Comma-separated columns to show in text output (names or JSON fields)")
+	cmd.Flags().StringVar(&listSortBy, "sort-by", "", "Sort by column or JSON field (prefix with - for descending)")
+	addPaginationFlags(cmd)
+}
+
+// printList prints the items of a list command: an aligned table in text
+// mode, or the items as-is through the formatter in structured modes.
+// --sort-by applies to both.
+func printList(items any, spec output.TableSpec, emptyMsg string) error {
+	if printed, err := PrintListOrEmpty(items, emptyMsg); err != nil {
+		return err
+	} else if printed {
+		return nil
+	}
+
+	if listSortBy != "" {
+		path := listSortBy
+		desc := path[0] == '-'
+		if desc {
+			path = path[1:]
+		}
+		path = spec.Column(path).Path
+		if desc {
+			path = "-" + path
+		}
+
+		sorted, err := output.SortSlice(items, path)
+		if err != nil {
+			return err
+		}
+		items = sorted
+	}
+
+	if IsStructuredOutput() {
+		return GetFormatter().Print(items)
+	}
+
+	records, err := output.ToRecords(items)
+	if err != nil {
+		return err
+	}
+	columns, err := spec.Resolve(listColumns, records)
+	if err != nil {
+		return err
+	}
+
+	tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+	return tf.PrintRecords(columns, records)
+}
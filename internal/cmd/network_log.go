@@ -0,0 +1,610 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+// networkEntry is a request paired with its response, assembled from the
+// session's network log files
+type networkEntry struct {
+	ID              string            `json:"id"`
+	Started         time.Time         `json:"started"`
+	Duration        float64           `json:"duration_ms"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	Host            string            `json:"host"`
+	Status          int               `json:"status"`
+	StatusText      string            `json:"status_text,omitempty"`
+	MimeType        string            `json:"mime_type,omitempty"`
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	RequestBody     string            `json:"-"`
+	RequestBytes    int64             `json:"request_bytes"`
+	ResponseBytes   int64             `json:"response_bytes"`
+
+	ended time.Time
+}
+
+// networkLogRecord is one logged request or response. The log files are
+// JSON objects, arrays of objects or JSON lines. The API does not publish
+// their schema and field names vary, so the common spellings are all
+// accepted. A key means the same thing in both record kinds: "time" and
+// "timestamp" are always points in time, never durations.
+type networkLogRecord map[string]any
+
+// parseNetworkLogFile decodes the records of one log file
+func parseNetworkLogFile(data []byte) ([]networkLogRecord, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var records []networkLogRecord
+	for {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			return nil, err
+		}
+		switch v := v.(type) {
+		case map[string]any:
+			records = append(records, v)
+		case []any:
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					records = append(records, m)
+				}
+			}
+		}
+	}
+}
+
+func (r networkLogRecord) str(keys ...string) string {
+	for _, k := range keys {
+		switch v := r[k].(type) {
+		case string:
+			if v != "" {
+				return v
+			}
+		case json.Number:
+			return v.String()
+		}
+	}
+	return ""
+}
+
+func (r networkLogRecord) num(keys ...string) (float64, bool) {
+	for _, k := range keys {
+		switch v := r[k].(type) {
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f, true
+			}
+		case float64:
+			return v, true
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// time reads a timestamp given as RFC 3339 or as epoch seconds or
+// milliseconds
+func (r networkLogRecord) time(keys ...string) time.Time {
+	for _, k := range keys {
+		switch v := r[k].(type) {
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				continue
+			}
+			if f > 1e12 {
+				return time.UnixMilli(int64(f)).UTC()
+			}
+			return time.Unix(0, int64(f*float64(time.Second))).UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// headers reads headers given as an object or as a list of name/value pairs
+func (r networkLogRecord) headers(keys ...string) map[string]string {
+	for _, k := range keys {
+		switch v := r[k].(type) {
+		case map[string]any:
+			h := make(map[string]string, len(v))
+			for name, value := range v {
+				h[name] = fmt.Sprint(value)
+			}
+			return h
+		case []any:
+			h := make(map[string]string, len(v))
+			for _, item := range v {
+				if m, ok := item.(map[string]any); ok {
+					name, _ := m["name"].(string)
+					value, _ := m["value"].(string)
+					if name != "" {
+						h[name] = value
+					}
+				}
+			}
+			return h
+		}
+	}
+	return nil
+}
+
+func (r networkLogRecord) id() string {
+	return r.str("request_id", "requestId", "id")
+}
+
+// headerValue looks up a header case-insensitively
+func headerValue(h map[string]string, name string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// logFileKey pairs request and response files that carry no request ID by
+// their file name with "request" or "response" removed
+func logFileKey(f api.NetworkLogFile) string {
+	name := strings.TrimSuffix(path.Base(f.Filename), path.Ext(f.Filename))
+	name = strings.NewReplacer("response", "", "request", "").Replace(name)
+	return strings.Trim(name, "_-.")
+}
+
+// networkLogGroup holds the records of one log file, keyed for pairing
+type networkLogGroup struct {
+	Key     string
+	Records []networkLogRecord
+}
+
+// buildNetworkEntries pairs request and response records into entries,
+// ordered by start time
+func buildNetworkEntries(requests, responses []networkLogGroup) []networkEntry {
+	entries := map[string]*networkEntry{}
+	var order []string
+	get := func(id string) *networkEntry {
+		if e, ok := entries[id]; ok {
+			return e
+		}
+		e := &networkEntry{ID: id}
+		entries[id] = e
+		order = append(order, id)
+		return e
+	}
+
+	for _, g := range requests {
+		for i, r := range g.Records {
+			applyRequestRecord(get(recordKey(r, g.Key, i, len(g.Records))), r)
+		}
+	}
+	for _, g := range responses {
+		for i, r := range g.Records {
+			applyResponseRecord(get(recordKey(r, g.Key, i, len(g.Records))), r)
+		}
+	}
+
+	result := make([]networkEntry, 0, len(order))
+	for _, id := range order {
+		e := entries[id]
+		if u, err := url.Parse(e.URL); err == nil {
+			e.Host = u.Host
+		}
+		if e.Duration == 0 && !e.Started.IsZero() && e.ended.After(e.Started) {
+			e.Duration = float64(e.ended.Sub(e.Started)) / float64(time.Millisecond)
+		}
+		result = append(result, *e)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Started.Before(result[j].Started) })
+	return result
+}
+
+func recordKey(r networkLogRecord, fileKey string, i, n int) string {
+	if id := r.id(); id != "" {
+		return id
+	}
+	if n == 1 {
+		return fileKey
+	}
+	return fileKey + "#" + strconv.Itoa(i)
+}
+
+func applyRequestRecord(e *networkEntry, r networkLogRecord) {
+	if m := r.str("method"); m != "" {
+		e.Method = strings.ToUpper(m)
+	}
+	if u := r.str("url"); u != "" {
+		e.URL = u
+	}
+	if t := r.time("timestamp", "time", "started_at", "start_time", "startedDateTime"); !t.IsZero() {
+		e.Started = t
+	}
+	if h := r.headers("headers", "request_headers"); h != nil {
+		e.RequestHeaders = h
+	}
+	e.RequestBody = r.str("post_data", "body")
+	if n, ok := r.num("size", "body_size", "bytes", "content_length"); ok {
+		e.RequestBytes = int64(n)
+	} else {
+		e.RequestBytes = int64(len(e.RequestBody))
+	}
+}
+
+func applyResponseRecord(e *networkEntry, r networkLogRecord) {
+	if u := r.str("url"); u != "" && e.URL == "" {
+		e.URL = u
+	}
+	if m := r.str("method"); m != "" && e.Method == "" {
+		e.Method = strings.ToUpper(m)
+	}
+	if s, ok := r.num("status", "status_code"); ok {
+		e.Status = int(s)
+	}
+	e.StatusText = r.str("status_text", "statusText", "reason")
+	if h := r.headers("headers", "response_headers"); h != nil {
+		e.ResponseHeaders = h
+	}
+	e.MimeType = r.str("mime_type", "mimeType", "content_type")
+	if e.MimeType == "" {
+		e.MimeType = headerValue(e.ResponseHeaders, "Content-Type")
+	}
+	if n, ok := r.num("size", "body_size", "encoded_data_length", "bytes", "content_length"); ok {
+		e.ResponseBytes = int64(n)
+	} else if n, err := strconv.ParseInt(headerValue(e.ResponseHeaders, "Content-Length"), 10, 64); err == nil {
+		e.ResponseBytes = n
+	}
+	if d, ok := r.num("duration_ms", "duration", "elapsed_ms"); ok {
+		e.Duration = d
+	}
+	e.ended = r.time("timestamp", "time", "ended_at", "end_time")
+}
+
+// networkFilter selects log entries by URL, status, method and size
+type networkFilter struct {
+	url      *regexp.Regexp
+	statuses []statusRange
+	methods  map[string]bool
+	minSize  int64
+}
+
+type statusRange struct{ lo, hi int }
+
+func newNetworkFilter(urlMatch, statuses, methods, minSize string) (*networkFilter, error) {
+	f := &networkFilter{}
+	var err error
+	if urlMatch != "" {
+		if f.url, err = regexp.Compile(urlMatch); err != nil {
+			return nil, fmt.Errorf("invalid --url-match: %w", err)
+		}
+	}
+	if statuses != "" {
+		if f.statuses, err = parseStatusRanges(statuses); err != nil {
+			return nil, err
+		}
+	}
+	if methods != "" {
+		f.methods = map[string]bool{}
+		for _, m := range strings.Split(methods, ",") {
+			if m = strings.TrimSpace(m); m != "" {
+				f.methods[strings.ToUpper(m)] = true
+			}
+		}
+	}
+	if minSize != "" {
+		if f.minSize, err = parseByteSize(minSize); err != nil {
+			return nil, fmt.Errorf("invalid --min-size: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// parseStatusRanges parses a comma-separated list of codes (404), classes
+// (4xx) and ranges (500-503)
+func parseStatusRanges(s string) ([]statusRange, error) {
+	var ranges []statusRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		var r statusRange
+		switch {
+		case len(part) == 3 && strings.HasSuffix(part, "xx") && part[0] >= '1' && part[0] <= '5':
+			r.lo = int(part[0]-'0') * 100
+			r.hi = r.lo + 99
+		case strings.Contains(part, "-"):
+			lo, hi, _ := strings.Cut(part, "-")
+			a, err1 := strconv.Atoi(lo)
+			b, err2 := strconv.Atoi(hi)
+			if err1 != nil || err2 != nil || a > b {
+				return nil, fmt.Errorf("invalid --status %q: expected a code, class (4xx) or range (500-503)", part)
+			}
+			r = statusRange{a, b}
+		default:
+			code, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --status %q: expected a code, class (4xx) or range (500-503)", part)
+			}
+			r = statusRange{code, code}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func (f *networkFilter) match(e networkEntry) bool {
+	if f.url != nil && !f.url.MatchString(e.URL) {
+		return false
+	}
+	if len(f.statuses) > 0 {
+		ok := false
+		for _, r := range f.statuses {
+			if e.Status >= r.lo && e.Status <= r.hi {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if f.methods != nil && !f.methods[e.Method] {
+		return false
+	}
+	return e.ResponseBytes >= f.minSize
+}
+
+// parseByteSize parses a size such as 512, 10k, 1.5MB or 2MiB
+func parseByteSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	num, unit := s, ""
+	if i >= 0 {
+		num, unit = s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	mult := map[string]float64{
+		"": 1, "b": 1,
+		"k": 1e3, "kb": 1e3, "kib": 1 << 10,
+		"m": 1e6, "mb": 1e6, "mib": 1 << 20,
+		"g": 1e9, "gb": 1e9, "gib": 1 << 30,
+	}[unit]
+	if mult == 0 {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, unit)
+	}
+	return int64(n * mult), nil
+}
+
+// formatBytes formats a byte count with a decimal unit
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1e9:
+		return strconv.FormatFloat(float64(n)/1e9, 'f', 1, 64) + " GB"
+	case n >= 1e6:
+		return strconv.FormatFloat(float64(n)/1e6, 'f', 1, 64) + " MB"
+	case n >= 1e3:
+		return strconv.FormatFloat(float64(n)/1e3, 'f', 1, 64) + " kB"
+	default:
+		return strconv.FormatInt(n, 10) + " B"
+	}
+}
+
+// hostSummary totals the traffic of one host
+type hostSummary struct {
+	Host          string `json:"host"`
+	Requests      int    `json:"requests"`
+	Failed        int    `json:"failed"`
+	RequestBytes  int64  `json:"request_bytes"`
+	ResponseBytes int64  `json:"response_bytes"`
+}
+
+// summarizeHosts totals entries by host, largest first
+func summarizeHosts(entries []networkEntry) []hostSummary {
+	byHost := map[string]*hostSummary{}
+	var hosts []*hostSummary
+	for _, e := range entries {
+		h, ok := byHost[e.Host]
+		if !ok {
+			h = &hostSummary{Host: e.Host}
+			byHost[e.Host] = h
+			hosts = append(hosts, h)
+		}
+		h.Requests++
+		if e.Status >= 400 || e.Status == 0 {
+			h.Failed++
+		}
+		h.RequestBytes += e.RequestBytes
+		h.ResponseBytes += e.ResponseBytes
+	}
+
+	sort.SliceStable(hosts, func(i, j int) bool {
+		a, b := hosts[i], hosts[j]
+		if a.RequestBytes+a.ResponseBytes != b.RequestBytes+b.ResponseBytes {
+			return a.RequestBytes+a.ResponseBytes > b.RequestBytes+b.ResponseBytes
+		}
+		return a.Host < b.Host
+	})
+	summary := make([]hostSummary, len(hosts))
+	for i, h := range hosts {
+		summary[i] = *h
+	}
+	return summary
+}
+
+// HAR 1.2 types, see http://www.softwareishard.com/blog/har-12-spec/
+type (
+	harFile struct {
+		Log harLog `json:"log"`
+	}
+	harLog struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	}
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	harEntry struct {
+		StartedDateTime string      `json:"startedDateTime"`
+		Time            float64     `json:"time"`
+		Request         harRequest  `json:"request"`
+		Response        harResponse `json:"response"`
+		Cache           struct{}    `json:"cache"`
+		Timings         harTimings  `json:"timings"`
+	}
+	harRequest struct {
+		Method      string         `json:"method"`
+		URL         string         `json:"url"`
+		HTTPVersion string         `json:"httpVersion"`
+		Cookies     []any          `json:"cookies"`
+		Headers     []harNameValue `json:"headers"`
+		QueryString []harNameValue `json:"queryString"`
+		PostData    *harPostData   `json:"postData,omitempty"`
+		HeadersSize int            `json:"headersSize"`
+		BodySize    int64          `json:"bodySize"`
+	}
+	harResponse struct {
+		Status      int            `json:"status"`
+		StatusText  string         `json:"statusText"`
+		HTTPVersion string         `json:"httpVersion"`
+		Cookies     []any          `json:"cookies"`
+		Headers     []harNameValue `json:"headers"`
+		Content     harContent     `json:"content"`
+		RedirectURL string         `json:"redirectURL"`
+		HeadersSize int            `json:"headersSize"`
+		BodySize    int64          `json:"bodySize"`
+	}
+	harNameValue struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	harPostData struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	}
+	harContent struct {
+		Size     int64  `json:"size"`
+		MimeType string `json:"mimeType"`
+	}
+	harTimings struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	}
+)
+
+// buildHAR converts entries to an HTTP Archive
+func buildHAR(entries []networkEntry) harFile {
+	har := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "notte", Version: Version},
+		Entries: make([]harEntry, 0, len(entries)),
+	}}
+	for _, e := range entries {
+		started := e.Started
+		if started.IsZero() {
+			started = time.Unix(0, 0).UTC()
+		}
+		method := e.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		statusText := e.StatusText
+		if statusText == "" {
+			statusText = http.StatusText(e.Status)
+		}
+
+		req := harRequest{
+			Method:      method,
+			URL:         e.URL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []any{},
+			Headers:     harHeaders(e.RequestHeaders),
+			QueryString: harQuery(e.URL),
+			HeadersSize: -1,
+			BodySize:    e.RequestBytes,
+		}
+		if e.RequestBody != "" {
+			req.PostData = &harPostData{MimeType: headerValue(e.RequestHeaders, "Content-Type"), Text: e.RequestBody}
+		}
+
+		har.Log.Entries = append(har.Log.Entries, harEntry{
+			StartedDateTime: started.Format(time.RFC3339Nano),
+			Time:            e.Duration,
+			Request:         req,
+			Response: harResponse{
+				Status:      e.Status,
+				StatusText:  statusText,
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []any{},
+				Headers:     harHeaders(e.ResponseHeaders),
+				Content:     harContent{Size: e.ResponseBytes, MimeType: e.MimeType},
+				RedirectURL: headerValue(e.ResponseHeaders, "Location"),
+				HeadersSize: -1,
+				BodySize:    e.ResponseBytes,
+			},
+			Timings: harTimings{Wait: e.Duration},
+		})
+	}
+	return har
+}
+
+func harHeaders(h map[string]string) []harNameValue {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	headers := make([]harNameValue, 0, len(h))
+	for _, name := range names {
+		headers = append(headers, harNameValue{Name: name, Value: h[name]})
+	}
+	return headers
+}
+
+func harQuery(raw string) []harNameValue {
+	query := []harNameValue{}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return query
+	}
+	values := u.Query()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range values[name] {
+			query = append(query, harNameValue{Name: name, Value: v})
+		}
+	}
+	return query
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+func parseTestLog(t *testing.T, data string) []networkLogRecord {
+	t.Helper()
+	records, err := parseNetworkLogFile([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func testNetworkEntries(t *testing.T) []networkEntry {
+	t.Helper()
+	requests := []networkLogGroup{
+		{Key: "1", Records: parseTestLog(t, `{"request_id":"r1","method":"get","url":"https://example.com/?q=1","timestamp":"2026-01-01T00:00:00Z","headers":{"Accept":"*/*"}}`)},
+		{Key: "2", Records: parseTestLog(t, `{"request_id":"r2","method":"POST","url":"https://api.example.com/login","timestamp":1767225601.5,"post_data":"user=a"}
+{"request_id":"r3","method":"GET","url":"https://cdn.example.net/app.js","timestamp":1767225602000}`)},
+	}
+	responses := []networkLogGroup{
+		{Key: "1", Records: parseTestLog(t, `[{"request_id":"r1","status":200,"headers":[{"name":"Content-Type","value":"text/html"},{"name":"Content-Length","value":"2000"}],"timestamp":"2026-01-01T00:00:00.250Z"}]`)},
+		{Key: "2", Records: parseTestLog(t, `{"request_id":"r2","status_code":"401","size":120,"duration_ms":80}`)},
+		{Key: "3", Records: parseTestLog(t, `{"request_id":"r3","status":200,"encoded_data_length":150000}`)},
+	}
+	return buildNetworkEntries(requests, responses)
+}
+
+func TestBuildNetworkEntries(t *testing.T) {
+	entries := testNetworkEntries(t)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	e := entries[0]
+	if e.ID != "r1" || e.Method != "GET" || e.Host != "example.com" || e.Status != 200 {
+		t.Errorf("unexpected first entry: %+v", e)
+	}
+	if e.ResponseBytes != 2000 || e.MimeType != "text/html" || e.Duration != 250 {
+		t.Errorf("expected size, type and duration from the response, got %+v", e)
+	}
+
+	e = entries[1]
+	if e.ID != "r2" || e.Status != 401 || e.RequestBytes != 6 || e.ResponseBytes != 120 || e.Duration != 80 {
+		t.Errorf("unexpected second entry: %+v", e)
+	}
+	if want := time.Date(2026, 1, 1, 0, 0, 1, 500_000_000, time.UTC); !e.Started.Equal(want) {
+		t.Errorf("expected epoch seconds to parse as %v, got %v", want, e.Started)
+	}
+	if entries[2].Host != "cdn.example.net" || entries[2].ResponseBytes != 150000 {
+		t.Errorf("unexpected third entry: %+v", entries[2])
+	}
+}
+
+func TestBuildNetworkEntries_TimeIsTimestamp(t *testing.T) {
+	requests := []networkLogGroup{{Key: "0001", Records: parseTestLog(t,
+		`{"request_id":"1024.17","url":"https://example.com/api/items?page=2","method":"GET","headers":{"Accept":"application/json","User-Agent":"Mozilla/5.0"},"time":1767225600123,"resource_type":"fetch"}`)}}
+	responses := []networkLogGroup{{Key: "0001", Records: parseTestLog(t,
+		`{"request_id":"1024.17","url":"https://example.com/api/items?page=2","status":200,"status_text":"OK","headers":{"content-type":"application/json; charset=utf-8","content-length":"5321"},"time":1767225600468,"mime_type":"application/json"}`)}}
+
+	entries := buildNetworkEntries(requests, responses)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if want := time.UnixMilli(1767225600123).UTC(); !e.Started.Equal(want) {
+		t.Errorf("expected start %v, got %v", want, e.Started)
+	}
+	if e.Duration != 345 {
+		t.Errorf("expected the duration between the two timestamps, got %v", e.Duration)
+	}
+	if e.Status != 200 || e.ResponseBytes != 5321 || e.MimeType != "application/json" {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+}
+
+func TestBuildNetworkEntries_PairsByFileName(t *testing.T) {
+	reqFile := api.NetworkLogFile{Filename: "0001_request.json"}
+	respFile := api.NetworkLogFile{Filename: "0001_response.json"}
+	if logFileKey(reqFile) != logFileKey(respFile) {
+		t.Fatalf("expected matching keys, got %q and %q", logFileKey(reqFile), logFileKey(respFile))
+	}
+
+	entries := buildNetworkEntries(
+		[]networkLogGroup{{Key: logFileKey(reqFile), Records: parseTestLog(t, `{"method":"GET","url":"https://a.test/"}`)}},
+		[]networkLogGroup{{Key: logFileKey(respFile), Records: parseTestLog(t, `{"status":404}`)}},
+	)
+	if len(entries) != 1 || entries[0].Status != 404 || entries[0].URL != "https://a.test/" {
+		t.Errorf("expected one paired entry, got %+v", entries)
+	}
+}
+
+func TestNetworkFilter(t *testing.T) {
+	entries := testNetworkEntries(t)
+	tests := []struct {
+		name                            string
+		urlMatch, status, method, minSz string
+		want                            []string
+	}{
+		{name: "none", want: []string{"r1", "r2", "r3"}},
+		{name: "url", urlMatch: `example\.com`, want: []string{"r1", "r2"}},
+		{name: "class", status: "4xx", want: []string{"r2"}},
+		{name: "codes and ranges", status: "200, 400-403", want: []string{"r1", "r2", "r3"}},
+		{name: "method", method: "post,put", want: []string{"r2"}},
+		{name: "size", minSz: "100k", want: []string{"r3"}},
+		{name: "combined", status: "2xx", minSz: "1kb", urlMatch: "example.com", want: []string{"r1"}},
+	}
+	for _, tt := range tests {
+		f, err := newNetworkFilter(tt.urlMatch, tt.status, tt.method, tt.minSz)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var got []string
+		for _, e := range entries {
+			if f.match(e) {
+				got = append(got, e.ID)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestNetworkFilter_Invalid(t *testing.T) {
+	for _, args := range [][4]string{
+		{"(", "", "", ""},
+		{"", "abc", "", ""},
+		{"", "500-400", "", ""},
+		{"", "", "", "10 parsecs"},
+	} {
+		if _, err := newNetworkFilter(args[0], args[1], args[2], args[3]); err == nil {
+			t.Errorf("expected error for %q", args)
+		}
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{"512": 512, "10k": 10000, "1.5MB": 1500000, "2MiB": 2 << 20, "0": 0}
+	for in, want := range tests {
+		if got, err := parseByteSize(in); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+}
+
+func TestSummarizeHosts(t *testing.T) {
+	hosts := summarizeHosts(testNetworkEntries(t))
+	if len(hosts) != 3 {
+		t.Fatalf("expected 3 hosts, got %d", len(hosts))
+	}
+	if hosts[0].Host != "cdn.example.net" || hosts[1].Host != "example.com" {
+		t.Errorf("expected hosts ordered by bytes, got %+v", hosts)
+	}
+	if hosts[2].Host != "api.example.com" || hosts[2].Failed != 1 {
+		t.Errorf("expected the failed login counted, got %+v", hosts[2])
+	}
+}
+
+func TestBuildHAR(t *testing.T) {
+	har := buildHAR(testNetworkEntries(t))
+	if har.Log.Version != "1.2" || len(har.Log.Entries) != 3 {
+		t.Fatalf("unexpected HAR: %+v", har.Log)
+	}
+
+	e := har.Log.Entries[0]
+	if e.StartedDateTime != "2026-01-01T00:00:00Z" || e.Time != 250 {
+		t.Errorf("unexpected timing: %+v", e)
+	}
+	if e.Request.Method != "GET" || len(e.Request.QueryString) != 1 || e.Request.QueryString[0].Value != "1" {
+		t.Errorf("unexpected request: %+v", e.Request)
+	}
+	if e.Response.Status != 200 || e.Response.StatusText != "OK" || e.Response.Content.MimeType != "text/html" {
+		t.Errorf("unexpected response: %+v", e.Response)
+	}
+
+	post := har.Log.Entries[1].Request
+	if post.PostData == nil || post.PostData.Text != "user=a" {
+		t.Errorf("expected post data, got %+v", post)
+	}
+}
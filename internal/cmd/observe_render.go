@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+// observedAction is an interaction action found by observe
+type observedAction struct {
+	ID          string
+	Role        string
+	Description string
+}
+
+// interactionActions returns the space's interaction actions that have an ID
+func interactionActions(space api.ActionSpace) []observedAction {
+	actions := make([]observedAction, 0, len(space.InteractionActions))
+	for _, item := range space.InteractionActions {
+		fields := unionFields(item)
+		id, _ := fields["id"].(string)
+		if id == "" {
+			continue
+		}
+		role, _ := fields["type"].(string)
+		if role == "" {
+			role, _ = fields["category"].(string)
+		}
+		desc, _ := fields["description"].(string)
+		actions = append(actions, observedAction{ID: id, Role: role, Description: desc})
+	}
+	return actions
+}
+
+// browserActionTypes returns the types of the space's browser actions
+func browserActionTypes(space api.ActionSpace) []string {
+	if space.BrowserActions == nil {
+		return nil
+	}
+	var types []string
+	for _, item := range *space.BrowserActions {
+		if t, _ := unionFields(item)["type"].(string); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// printObservation renders an observation for people: the page and its tabs,
+// a numbered table of interaction actions and, with markdown set, the page
+// content
+func printObservation(w io.Writer, obs *api.ObserveResponse, markdown bool) error {
+	meta := obs.Metadata
+	title := meta.Title
+	if title == "" {
+		title = "(untitled)"
+	}
+	_, _ = fmt.Fprintf(w, "%s\n%s\n", title, meta.Url)
+
+	if desc := strings.TrimSpace(obs.Space.Description); desc != "" {
+		_, _ = fmt.Fprintf(w, "\n%s\n", desc)
+	}
+
+	if len(meta.Tabs) > 0 {
+		_, _ = fmt.Fprintln(w, "\nTabs:")
+		for _, tab := range meta.Tabs {
+			marker := " "
+			if tab.Url == meta.Url {
+				marker = "*"
+			}
+			_, _ = fmt.Fprintf(w, "  %s %d  %s  %s\n", marker, tab.TabId, tab.Title, tab.Url)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	actions := interactionActions(obs.Space)
+	if len(actions) == 0 {
+		_, _ = fmt.Fprintln(w, "No interaction actions.")
+	} else {
+		rows := make([]map[string]any, len(actions))
+		for i, a := range actions {
+			rows[i] = map[string]any{
+				"#":           strconv.Itoa(i + 1),
+				"ID":          a.ID,
+				"ROLE":        a.Role,
+				"DESCRIPTION": a.Description,
+			}
+		}
+		tf := &output.TextFormatter{Writer: w, NoColor: noColor}
+		if err := tf.PrintTable([]string{"#", "ID", "ROLE", "DESCRIPTION"}, rows); err != nil {
+			return err
+		}
+	}
+
+	if types := browserActionTypes(obs.Space); len(types) > 0 {
+		_, _ = fmt.Fprintf(w, "\nBrowser actions: %s\n", strings.Join(types, ", "))
+	}
+
+	if markdown {
+		_, _ = fmt.Fprintln(w)
+		if obs.Space.Markdown == nil || strings.TrimSpace(*obs.Space.Markdown) == "" {
+			_, _ = fmt.Fprintln(w, "No page markdown.")
+		} else {
+			_, _ = fmt.Fprintln(w, strings.TrimRight(*obs.Space.Markdown, "\n"))
+		}
+	}
+	return nil
+}
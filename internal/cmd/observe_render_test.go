@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+const observeSpaceJSON = `{"category":"page","description":"A login form","markdown":"# Login\n\nWelcome back","interaction_actions":[{"id":"I1","type":"fill","description":"Email address"},{"id":"B1","type":"click","description":"Sign in"},{"type":"click","description":"no id"}],"browser_actions":[{"type":"goto"},{"type":"scroll_down"}]}`
+
+func testObservation(t *testing.T) *api.ObserveResponse {
+	t.Helper()
+	data := fmt.Sprintf(`{"metadata":{"tabs":[{"tab_id":0,"title":"Login","url":"https://example.com/login"},{"tab_id":1,"title":"Docs","url":"https://example.com/docs"}],"title":"Login","url":"https://example.com/login"},"screenshot":{"raw":null},"session":%s,"space":%s}`, sessionJSON(), observeSpaceJSON)
+	var obs api.ObserveResponse
+	if err := json.Unmarshal([]byte(data), &obs); err != nil {
+		t.Fatal(err)
+	}
+	return &obs
+}
+
+func TestPrintObservation(t *testing.T) {
+	origNoColor := noColor
+	noColor = true
+	t.Cleanup(func() { noColor = origNoColor })
+
+	var buf bytes.Buffer
+	if err := printObservation(&buf, testObservation(t), false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"Login\nhttps://example.com/login\n",
+		"A login form",
+		"* 0  Login  https://example.com/login",
+		"  1  Docs  https://example.com/docs",
+		"Browser actions: goto, scroll_down",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+
+	table := out[strings.Index(out, "#  "):]
+	table = table[:strings.Index(table, "\n\n")]
+	var rows []string
+	for _, line := range strings.Split(table, "\n") {
+		rows = append(rows, strings.Join(strings.Fields(line), " "))
+	}
+	want := []string{"# ID ROLE DESCRIPTION", "1 I1 fill Email address", "2 B1 click Sign in"}
+	if strings.Join(rows, "|") != strings.Join(want, "|") {
+		t.Errorf("expected action table %q, got %q", want, rows)
+	}
+	if strings.Contains(out, "Welcome back") {
+		t.Error("expected markdown to be hidden without --markdown")
+	}
+
+	buf.Reset()
+	if err := printObservation(&buf, testObservation(t), true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "# Login\n\nWelcome back") {
+		t.Errorf("expected page markdown, got:\n%s", buf.String())
+	}
+}
+
+func TestPrintObservation_NoActions(t *testing.T) {
+	var buf bytes.Buffer
+	obs := &api.ObserveResponse{}
+	if err := printObservation(&buf, obs, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"(untitled)", "No interaction actions.", "No page markdown."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRunSessionObserve_TextAndJSON(t *testing.T) {
+	server := setupSessionTest(t)
+	resp := fmt.Sprintf(`{"metadata":{"tabs":[],"title":"Login","url":"https://example.com/login"},"screenshot":{"raw":null},"session":%s,"space":%s}`, sessionJSON(), observeSpaceJSON)
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/observe", 200, resp)
+
+	origFormat, origMarkdown := outputFormat, sessionObserveMarkdown
+	t.Cleanup(func() { outputFormat, sessionObserveMarkdown = origFormat, origMarkdown })
+	outputFormat, sessionObserveMarkdown = "text", true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionObserve(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "B1") || !strings.Contains(stdout, "Welcome back") {
+		t.Errorf("expected rendered observation, got:\n%s", stdout)
+	}
+
+	// JSON output is the response as returned by the API, markdown included
+	outputFormat = "json"
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runSessionObserve(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	var got api.ObserveResponse
+	if err := json.Unmarshal([]byte(stdout), &got); err != nil {
+		t.Fatalf("expected JSON output, got %q: %v", stdout, err)
+	}
+	if len(got.Space.InteractionActions) != 3 || got.Metadata.Title != "Login" {
+		t.Errorf("expected the full response, got %+v", got)
+	}
+}
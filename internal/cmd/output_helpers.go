@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
 // IsJSONOutput returns true if the global output format is set to JSON.
@@ -11,20 +13,27 @@ func IsJSONOutput() bool {
 	return outputFormat == "json"
 }
 
+// IsStructuredOutput returns true if the global output format is meant for
+// machines (json, yaml, csv, ndjson, template or jsonpath). Messages then go
+// to stderr and data is printed with the formatter.
+func IsStructuredOutput() bool {
+	return output.Format(outputFormat).Structured()
+}
+
 // PrintInfo prints an informational message to stdout in text mode,
-// or to stderr in JSON mode to keep stdout clean for machine parsing.
+// or to stderr in structured modes to keep stdout clean for machine parsing.
 func PrintInfo(message string) {
-	if IsJSONOutput() {
+	if IsStructuredOutput() {
 		_, _ = fmt.Fprintln(os.Stderr, message)
 		return
 	}
 	_, _ = fmt.Fprintln(os.Stdout, message)
 }
 
-// PrintResult prints a success result. In JSON mode, outputs structured data
-// to stdout. In text mode, prints the human-readable message.
+// PrintResult prints a success result. In structured modes, outputs data
+// to stdout with the formatter. In text mode, prints the human-readable message.
 func PrintResult(message string, data map[string]any) error {
-	if IsJSONOutput() {
+	if IsStructuredOutput() {
 		if data == nil {
 			data = map[string]any{}
 		}
@@ -42,12 +51,12 @@ func PrintResult(message string, data map[string]any) error {
 }
 
 // PrintListOrEmpty handles empty or nil slice output. If the slice is nil or empty,
-// it prints an empty JSON array in JSON mode or the provided message in text mode.
+// it prints an empty list in structured modes or the provided message in text mode.
 // Returns (true, nil) if output was handled, (false, nil) if the caller should handle
 // non-empty output, or (false, error) if items is not a slice type.
 func PrintListOrEmpty(items any, emptyMsg string) (bool, error) {
 	if items == nil {
-		if IsJSONOutput() {
+		if IsStructuredOutput() {
 			return true, GetFormatter().Print([]any{})
 		}
 		if emptyMsg != "" {
@@ -62,7 +71,7 @@ func PrintListOrEmpty(items any, emptyMsg string) (bool, error) {
 	}
 
 	if v.Len() == 0 {
-		if IsJSONOutput() {
+		if IsStructuredOutput() {
 			empty := reflect.MakeSlice(v.Type(), 0, 0).Interface()
 			return true, GetFormatter().Print(empty)
 		}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/salmonumbrella/notte-cli/internal/output"
 	"github.com/salmonumbrella/notte-cli/internal/testutil"
 )
 
@@ -132,6 +133,8 @@ func TestPrintInfo(t *testing.T) {
 	}{
 		{"text mode prints to stdout", "text", "test message", true},
 		{"json mode prints to stderr", "json", "test message", false},
+		{"yaml mode prints to stderr", "yaml", "test message", false},
+		{"template mode prints to stderr", "template={{.}}", "test message", false},
 	}
 
 	for _, tt := range tests {
@@ -239,3 +242,38 @@ func TestPrintResult(t *testing.T) {
 		}
 	})
 }
+
+func TestPrintList_StructuredFormats(t *testing.T) {
+	origFormat, origSort := outputFormat, listSortBy
+	t.Cleanup(func() { outputFormat, listSortBy = origFormat, origSort })
+	listSortBy = "name"
+
+	type item struct {
+		Name string `json:"name"`
+	}
+	items := []item{{Name: "b"}, {Name: "a"}}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"ndjson", `{"name":"a"}` + "\n" + `{"name":"b"}` + "\n"},
+		{"csv", "name\na\nb\n"},
+		{"yaml", "- name: a\n- name: b\n"},
+		{"template={{.Name}}", "a\nb\n"},
+		{"jsonpath={.name}", "a\nb\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			outputFormat = tt.format
+			stdout, _ := testutil.CaptureOutput(func() {
+				if err := printList(items, output.TableSpec{}, "none"); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+			if stdout != tt.want {
+				t.Errorf("got %q, want %q", stdout, tt.want)
+			}
+		})
+	}
+}
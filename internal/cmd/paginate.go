@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+var (
+	listAll      bool
+	listLimit    int
+	listPage     int
+	listPageSize int
+)
+
+// addPaginationFlags registers the paging flags shared by list commands
+func addPaginationFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&listAll, "all", false, "Fetch every page")
+	cmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of items to return, fetching more pages as needed")
+	cmd.Flags().IntVar(&listPage, "page", 0, "Fetch only this page (starting at 1)")
+	cmd.Flags().IntVar(&listPageSize, "page-size", 0, "Number of items per page (default: server default)")
+}
+
+// pageRequest selects one page. Nil fields are left to the server.
+type pageRequest struct {
+	Page     *int
+	PageSize *int
+}
+
+// pageFetcher fetches one page of a list endpoint and reports whether
+// another page follows
+type pageFetcher[T any] func(ctx context.Context, req pageRequest) (items []T, hasNext bool, err error)
+
+// itemFilter reports whether a fetched item should be listed. It lets list
+// commands filter on fields the API cannot filter on.
+type itemFilter[T any] func(item T) (bool, error)
+
+// paginate fetches pages according to the paging flags and passes each
+// page's items to emit as they arrive. Without --all, --limit or --page
+// only the first page is fetched. Items rejected by keep (if set) are
+// dropped before --limit is applied; since matches may be on any page, a
+// keep filter walks the pages like --all unless --page is given.
+func paginate[T any](ctx context.Context, fetch pageFetcher[T], keep itemFilter[T], emit func([]T) error) error {
+	if listAll && listPage > 0 {
+		return errors.New("--all and --page cannot be used together")
+	}
+	if listLimit < 0 || listPage < 0 || listPageSize < 0 {
+		return errors.New("--limit, --page and --page-size must not be negative")
+	}
+
+	var req pageRequest
+	if listPageSize > 0 {
+		size := listPageSize
+		req.PageSize = &size
+	}
+	page := 1
+	if listPage > 0 {
+		page = listPage
+	}
+	walk := listPage == 0 && (listAll || listLimit > 0 || keep != nil)
+
+	remaining := listLimit
+	for {
+		if listPage > 0 || page > 1 {
+			p := page
+			req.Page = &p
+		}
+
+		pageCtx, cancel := GetContextWithTimeout(ctx)
+		items, hasNext, err := fetch(pageCtx, req)
+		cancel()
+		if err != nil {
+			if page > 1 {
+				return fmt.Errorf("page %d: %w", page, err)
+			}
+			return err
+		}
+		fetched := len(items)
+		if keep != nil {
+			if items, err = filterItems(items, keep); err != nil {
+				return err
+			}
+		}
+
+		if listLimit > 0 && len(items) >= remaining {
+			return emit(items[:remaining])
+		}
+		if err := emit(items); err != nil {
+			return err
+		}
+		remaining -= len(items)
+
+		if !walk || !hasNext || fetched == 0 {
+			return nil
+		}
+		page++
+	}
+}
+
+func filterItems[T any](items []T, keep itemFilter[T]) ([]T, error) {
+	kept := items[:0:0]
+	for _, item := range items {
+		ok, err := keep(item)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+// runPagedList fetches a list with paginate and prints it. Formats that
+// can be written incrementally are streamed page by page; text tables,
+// CSV and --sort-by need every item and are printed at the end.
+func runPagedList[T any](cmd *cobra.Command, spec output.TableSpec, emptyMsg string, fetch pageFetcher[T]) error {
+	return runFilteredList(cmd, spec, emptyMsg, fetch, nil)
+}
+
+// runFilteredList is runPagedList with a client-side filter
+func runFilteredList[T any](cmd *cobra.Command, spec output.TableSpec, emptyMsg string, fetch pageFetcher[T], keep itemFilter[T]) error {
+	var lw output.ListWriter
+	if IsStructuredOutput() && listSortBy == "" {
+		lw = output.NewListWriter(GetFormatter())
+	}
+
+	var items []T
+	err := paginate(cmd.Context(), fetch, keep, func(page []T) error {
+		if lw != nil {
+			return lw.Write(page)
+		}
+		items = append(items, page...)
+		return nil
+	})
+	if lw != nil {
+		if closeErr := lw.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	return printList(items, spec, emptyMsg)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+// setupPaginateTest serves three pages of sessions and resets the list flags
+func setupPaginateTest(t *testing.T) *testutil.MockServer {
+	t.Helper()
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	t.Cleanup(server.Close)
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	server.AddResponseSequence("/sessions", 200,
+		`{"has_next": true, "page": 1, "page_size": 2, "items": [{"session_id": "s1", "status": "ACTIVE"}, {"session_id": "s2", "status": "ACTIVE"}]}`,
+		`{"has_next": true, "page": 2, "page_size": 2, "items": [{"session_id": "s3", "status": "ACTIVE"}, {"session_id": "s4", "status": "ACTIVE"}]}`,
+		`{"has_next": false, "page": 3, "page_size": 2, "items": [{"session_id": "s5", "status": "ACTIVE"}]}`,
+	)
+
+	origFormat, origSort := outputFormat, listSortBy
+	origAll, origLimit, origPage, origPageSize := listAll, listLimit, listPage, listPageSize
+	t.Cleanup(func() {
+		outputFormat, listSortBy = origFormat, origSort
+		listAll, listLimit, listPage, listPageSize = origAll, origLimit, origPage, origPageSize
+	})
+	outputFormat = "json"
+	listSortBy = ""
+	listAll, listLimit, listPage, listPageSize = false, 0, 0, 0
+
+	return server
+}
+
+func runPaginatedSessionsList(t *testing.T) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var err error
+	stdout, _ := testutil.CaptureOutput(func() {
+		err = runSessionsList(cmd, nil)
+	})
+	return stdout, err
+}
+
+func TestPaginate_DefaultFetchesFirstPage(t *testing.T) {
+	server := setupPaginateTest(t)
+
+	stdout, err := runPaginatedSessionsList(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "s2") || strings.Contains(stdout, "s3") {
+		t.Errorf("expected only the first page, got %q", stdout)
+	}
+
+	reqs := server.Requests("/sessions")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].Query.Has("page") || reqs[0].Query.Has("page_size") {
+		t.Errorf("expected no paging params by default, got %v", reqs[0].Query)
+	}
+}
+
+func TestPaginate_All(t *testing.T) {
+	server := setupPaginateTest(t)
+	listAll = true
+	listPageSize = 2
+
+	stdout, err := runPaginatedSessionsList(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var items []map[string]any
+	if err := json.Unmarshal([]byte(stdout), &items); err != nil {
+		t.Fatalf("expected one JSON array, got %q: %v", stdout, err)
+	}
+	if len(items) != 5 || items[4]["session_id"] != "s5" {
+		t.Errorf("expected all 5 items, got %v", items)
+	}
+
+	reqs := server.Requests("/sessions")
+	if len(reqs) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(reqs))
+	}
+	for i, page := range []string{"", "2", "3"} {
+		if got := reqs[i].Query.Get("page"); got != page {
+			t.Errorf("request %d: expected page %q, got %q", i, page, got)
+		}
+		if got := reqs[i].Query.Get("page_size"); got != "2" {
+			t.Errorf("request %d: expected page_size 2, got %q", i, got)
+		}
+	}
+}
+
+func TestPaginate_Limit(t *testing.T) {
+	server := setupPaginateTest(t)
+	listLimit = 3
+
+	stdout, err := runPaginatedSessionsList(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "s3") || strings.Contains(stdout, "s4") {
+		t.Errorf("expected the first 3 items, got %q", stdout)
+	}
+	if n := len(server.Requests("/sessions")); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestPaginate_Page(t *testing.T) {
+	server := setupPaginateTest(t)
+	listPage = 2
+
+	if _, err := runPaginatedSessionsList(t); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reqs := server.Requests("/sessions")
+	if len(reqs) != 1 || reqs[0].Query.Get("page") != "2" {
+		t.Errorf("expected a single request for page 2, got %+v", reqs)
+	}
+
+	listAll = true
+	if _, err := runPaginatedSessionsList(t); err == nil || !strings.Contains(err.Error(), "--all and --page") {
+		t.Errorf("expected --all/--page conflict, got %v", err)
+	}
+}
+
+func TestPaginate_StreamsNDJSON(t *testing.T) {
+	setupPaginateTest(t)
+	outputFormat = "ndjson"
+	listAll = true
+
+	stdout, err := runPaginatedSessionsList(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 5 || !strings.Contains(lines[4], "s5") {
+		t.Errorf("expected one line per item, got %q", stdout)
+	}
+}
+
+func TestPaginate_TextSortsAcrossPages(t *testing.T) {
+	setupPaginateTest(t)
+	outputFormat = "text"
+	listAll = true
+	listSortBy = "-id"
+
+	stdout, err := runPaginatedSessionsList(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 6 || !strings.HasPrefix(lines[1], "s5") || !strings.HasPrefix(lines[5], "s1") {
+		t.Errorf("expected all items sorted in one table, got %q", stdout)
+	}
+}
+
+func TestPaginate_ClientFilterWalksPages(t *testing.T) {
+	server := setupPaginateTest(t)
+	resetListFilterFlags(t)
+	server.AddResponseSequence("/sessions", 200,
+		`{"has_next": true, "items": [{"session_id": "s1", "status": "ACTIVE", "browser_type": "chromium"}]}`,
+		`{"has_next": true, "items": [{"session_id": "s2", "status": "ACTIVE", "browser_type": "chromium"}]}`,
+		`{"has_next": false, "items": [{"session_id": "s3", "status": "ACTIVE", "browser_type": "firefox"}]}`,
+	)
+	listBrowser = []string{"firefox"}
+
+	stdout, err := runPaginatedSessionsList(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "s3") || strings.Contains(stdout, "s1") {
+		t.Errorf("expected the match on the last page, got %q", stdout)
+	}
+	if n := len(server.Requests("/sessions")); n != 3 {
+		t.Errorf("expected every page to be fetched, got %d requests", n)
+	}
+}
+
+func TestPaginate_ClientFilterRespectsPage(t *testing.T) {
+	server := setupPaginateTest(t)
+	resetListFilterFlags(t)
+	listBrowser = []string{"firefox"}
+	listPage = 2
+
+	if _, err := runPaginatedSessionsList(t); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(server.Requests("/sessions")); n != 1 {
+		t.Errorf("expected only the requested page, got %d requests", n)
+	}
+}
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
 var (
@@ -86,6 +88,9 @@ func init() {
 	personasCmd.AddCommand(personasPhoneCreateCmd)
 	personasCmd.AddCommand(personasPhoneDeleteCmd)
 
+	// List output flags
+	addListFlags(personasListCmd)
+
 	// Create command flags
 	personasCreateCmd.Flags().BoolVar(&personasCreatePhoneNumber, "create-phone-number", false, "Create a phone number for the persona")
 	personasCreateCmd.Flags().BoolVar(&personasCreateVault, "create-vault", false, "Create a vault for the persona")
@@ -115,38 +120,42 @@ func init() {
 	_ = personasPhoneDeleteCmd.MarkFlagRequired("id")
 }
 
+// personaTable lists the columns available to `personas list`
+var personaTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "persona_id"},
+		{Name: "email", Header: "EMAIL", Path: "email"},
+		{Name: "first_name", Header: "FIRST NAME", Path: "first_name"},
+		{Name: "last_name", Header: "LAST NAME", Path: "last_name"},
+		{Name: "phone", Header: "PHONE", Path: "phone_number"},
+		{Name: "status", Header: "STATUS", Path: "status"},
+		{Name: "vault", Header: "VAULT", Path: "vault_id"},
+	},
+	Defaults: []string{"id", "email", "first_name", "last_name", "status"},
+}
+
 func runPersonasList(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.ListPersonasParams{}
-	resp, err := client.Client().ListPersonasWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
-
-	formatter := GetFormatter()
-
-	var items []api.PersonaResponse
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
-	}
-	if printed, err := PrintListOrEmpty(items, "No personas found."); err != nil {
-		return err
-	} else if printed {
-		return nil
-	}
-
-	return formatter.Print(items)
+	return runPagedList(cmd, personaTable, "No personas found.", func(ctx context.Context, page pageRequest) ([]api.PersonaResponse, bool, error) {
+		params := &api.ListPersonasParams{Page: page.Page, PageSize: page.PageSize}
+		resp, err := client.Client().ListPersonasWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
+
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
+
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
+	})
 }
 
 func runPersonasCreate(cmd *cobra.Command, args []string) error {
@@ -1,11 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
 var (
@@ -52,6 +54,9 @@ func init() {
 	profilesCmd.AddCommand(profilesShowCmd)
 	profilesCmd.AddCommand(profilesDeleteCmd)
 
+	// List output flags
+	addListFlags(profilesListCmd)
+
 	// Create command flags
 	profilesCreateCmd.Flags().StringVar(&profilesCreateName, "name", "", "Profile name")
 
@@ -64,38 +69,39 @@ func init() {
 	_ = profilesDeleteCmd.MarkFlagRequired("id")
 }
 
+// profileTable lists the columns available to `profiles list`
+var profileTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "profile_id"},
+		{Name: "name", Header: "NAME", Path: "name"},
+		{Name: "created_at", Header: "CREATED", Path: "created_at"},
+		{Name: "updated_at", Header: "UPDATED", Path: "updated_at"},
+	},
+	Defaults: []string{"id", "name", "created_at"},
+}
+
 func runProfilesList(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
+	return runPagedList(cmd, profileTable, "No profiles found.", func(ctx context.Context, page pageRequest) ([]api.ProfileResponse, bool, error) {
+		params := &api.ProfileListParams{Page: page.Page, PageSize: page.PageSize}
+		resp, err := client.Client().ProfileListWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
 
-	params := &api.ProfileListParams{}
-	resp, err := client.Client().ProfileListWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
 
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
-
-	formatter := GetFormatter()
-
-	var items []api.ProfileResponse
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
-	}
-	if printed, err := PrintListOrEmpty(items, "No profiles found."); err != nil {
-		return err
-	} else if printed {
-		return nil
-	}
-
-	return formatter.Print(items)
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
+	})
 }
 
 func runProfilesCreate(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/salmonumbrella/notte-cli/internal/replay"
+	"github.com/salmonumbrella/notte-cli/internal/ui"
+)
+
+// replayProgressInterval limits how often download progress is redrawn
+const replayProgressInterval = 100 * time.Millisecond
+
+var (
+	replayOut  string
+	replayOpen bool
+)
+
+// addReplayFlags registers the flags that save a replay to disk
+func addReplayFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&replayOut, "out", "", "Save the replay to this file instead of printing it")
+	cmd.Flags().BoolVar(&replayOpen, "open", false, "Open the saved replay (in a temporary file unless --out is set)")
+}
+
+// savingReplay reports whether the replay goes to a file rather than stdout
+func savingReplay() bool {
+	return replayOut != "" || replayOpen
+}
+
+// openFile opens path in the default application; tests replace it
+var openFile = ui.Open
+
+// downloadReplay writes the replay body of resp to path, or to a temporary
+// file named after id when path is empty, and returns the path and size
+// written. With trim set, the whole replay is read and trimmed before it is
+// written. The file only appears once the download is complete.
+func downloadReplay(resp *http.Response, path, id string, trim func([]byte) ([]byte, error)) (string, int64, error) {
+	progress := newReplayProgress(resp.ContentLength)
+	body := bufio.NewReader(io.TeeReader(resp.Body, progress))
+	head, _ := body.Peek(12)
+	if path == "" {
+		ext := replay.Ext(head)
+		if ext == "" {
+			ext = ".webp"
+		}
+		path = filepath.Join(os.TempDir(), "notte-replay-"+id+ext)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".notte-replay-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create replay file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	var size int64
+	if trim == nil {
+		size, err = io.Copy(tmp, body)
+	} else {
+		var data []byte
+		if data, err = io.ReadAll(body); err == nil {
+			progress.done()
+			if data, err = trim(data); err != nil {
+				_ = tmp.Close()
+				return "", 0, fmt.Errorf("failed to trim replay: %w", err)
+			}
+			var n int
+			n, err = tmp.Write(data)
+			size = int64(n)
+		}
+	}
+	progress.done()
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to download replay: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("failed to save replay: %w", err)
+	}
+	return path, size, nil
+}
+
+// finishReplay opens the saved replay if --open is set and reports where it
+// was saved, along with fields
+func finishReplay(path string, size int64, fields map[string]any) error {
+	if replayOpen {
+		if err := openFile(path); err != nil {
+			return fmt.Errorf("replay saved to %s but could not be opened: %w", path, err)
+		}
+	}
+	fields["path"] = path
+	fields["bytes"] = size
+	return PrintResult(fmt.Sprintf("Replay saved to %s (%s)", path, formatBytes(size)), fields)
+}
+
+// replayProgress redraws the number of bytes downloaded on stderr. It stays
+// silent when stderr is not a terminal.
+type replayProgress struct {
+	w     io.Writer
+	total int64
+	n     int64
+	drawn time.Time
+}
+
+func newReplayProgress(total int64) *replayProgress {
+	p := &replayProgress{total: total}
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		p.w = os.Stderr
+	}
+	return p
+}
+
+func (p *replayProgress) Write(b []byte) (int, error) {
+	p.n += int64(len(b))
+	if p.w != nil && time.Since(p.drawn) >= replayProgressInterval {
+		p.draw()
+	}
+	return len(b), nil
+}
+
+func (p *replayProgress) draw() {
+	p.drawn = time.Now()
+	if p.total > 0 {
+		_, _ = fmt.Fprintf(p.w, "\rDownloading replay: %s of %s (%d%%)", formatBytes(p.n), formatBytes(p.total), p.n*100/p.total)
+	} else {
+		_, _ = fmt.Fprintf(p.w, "\rDownloading replay: %s", formatBytes(p.n))
+	}
+}
+
+// done draws the final progress and ends its line
+func (p *replayProgress) done() {
+	if p.w == nil {
+		return
+	}
+	p.draw()
+	_, _ = fmt.Fprintln(p.w)
+	p.w = nil
+}
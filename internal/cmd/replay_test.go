@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setReplayFlags(t *testing.T, out string, open, trim bool) {
+	t.Helper()
+	origOut, origOpen, origTrim := replayOut, replayOpen, agentsReplayTrim
+	replayOut, replayOpen, agentsReplayTrim = out, open, trim
+	t.Cleanup(func() { replayOut, replayOpen, agentsReplayTrim = origOut, origOpen, origTrim })
+}
+
+// testWebPReplay builds an animated WebP whose frames carry the given payloads
+func testWebPReplay(frames ...string) string {
+	chunk := func(fourCC string, payload []byte) []byte {
+		b := []byte(fourCC + "\x00\x00\x00\x00")
+		binary.LittleEndian.PutUint32(b[4:8], uint32(len(payload)))
+		b = append(b, payload...)
+		if len(payload)%2 == 1 {
+			b = append(b, 0)
+		}
+		return b
+	}
+	body := []byte("WEBP")
+	body = append(body, chunk("VP8X", make([]byte, 10))...)
+	body = append(body, chunk("ANIM", make([]byte, 6))...)
+	for _, f := range frames {
+		body = append(body, chunk("ANMF", []byte(f))...)
+	}
+	out := []byte("RIFF\x00\x00\x00\x00")
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(body)))
+	return string(append(out, body...))
+}
+
+func TestRunSessionReplay_Out(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/replay", 200, "replay-data")
+
+	path := filepath.Join(t.TempDir(), "replay.webm")
+	setReplayFlags(t, path, false, false)
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionReplay(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read replay: %v", err)
+	}
+	if string(data) != "replay-data" {
+		t.Errorf("unexpected replay contents: %q", data)
+	}
+	if strings.Contains(stdout, "replay_data") {
+		t.Errorf("expected replay data to stay out of stdout, got %q", stdout)
+	}
+	if !strings.Contains(stdout, `"bytes":11`) {
+		t.Errorf("expected size in output, got %q", stdout)
+	}
+}
+
+func TestRunSessionReplay_OutAPIError(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/replay", 404, `{"detail":"not found"}`)
+
+	path := filepath.Join(t.TempDir(), "replay.webm")
+	setReplayFlags(t, path, false, false)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	if err := runSessionReplay(cmd, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no replay file, got %v", err)
+	}
+}
+
+func TestRunSessionReplay_Open(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/replay", 200, testWebPReplay("f0"))
+	t.Setenv("TMPDIR", t.TempDir())
+	setReplayFlags(t, "", true, false)
+
+	var opened string
+	origOpen := openFile
+	openFile = func(path string) error {
+		opened = path
+		return nil
+	}
+	t.Cleanup(func() { openFile = origOpen })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	_, _ = testutil.CaptureOutput(func() {
+		if err := runSessionReplay(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	want := filepath.Join(os.TempDir(), "notte-replay-"+sessionIDTest+".webp")
+	if opened != want {
+		t.Errorf("expected %s to be opened, got %q", want, opened)
+	}
+	if _, err := os.Stat(want); err != nil {
+		t.Errorf("expected replay to be saved: %v", err)
+	}
+}
+
+func TestRunAgentReplay_Trim(t *testing.T) {
+	server := setupAgentTest(t)
+	server.AddResponse("/agents/"+agentIDTest+"/replay", 200, testWebPReplay("f0", "f1", "f2", "f3"))
+	server.AddResponse("/agents/"+agentIDTest, 200,
+		`{"agent_id":"`+agentIDTest+`","session_id":"sess_1","status":"closed","created_at":"2020-01-01T00:00:00Z","replay_start_offset":1,"replay_stop_offset":3}`)
+
+	path := filepath.Join(t.TempDir(), "agent.webp")
+	setReplayFlags(t, path, false, true)
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runAgentReplay(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read replay: %v", err)
+	}
+	if string(data) != testWebPReplay("f1", "f2") {
+		t.Errorf("expected frames 1-2 only, got %q", data)
+	}
+	if !strings.Contains(stdout, `"start_offset":1`) || !strings.Contains(stdout, `"stop_offset":3`) {
+		t.Errorf("expected offsets in output, got %q", stdout)
+	}
+}
+
+func TestRunAgentReplay_TrimStopPastEnd(t *testing.T) {
+	server := setupAgentTest(t)
+	server.AddResponse("/agents/"+agentIDTest+"/replay", 200, testWebPReplay("f0", "f1", "f2"))
+	server.AddResponse("/agents/"+agentIDTest, 200,
+		`{"agent_id":"`+agentIDTest+`","session_id":"sess_1","status":"closed","created_at":"2020-01-01T00:00:00Z","replay_start_offset":1,"replay_stop_offset":40}`)
+
+	path := filepath.Join(t.TempDir(), "agent.webp")
+	setReplayFlags(t, path, false, true)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	_, stderr := testutil.CaptureOutput(func() {
+		if err := runAgentReplay(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(stderr, "stop offset 40 is past the replay's 3 frame(s)") {
+		t.Errorf("expected a warning on stderr, got %q", stderr)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read replay: %v", err)
+	}
+	if string(data) != testWebPReplay("f1", "f2") {
+		t.Errorf("expected frames 1-2, got %q", data)
+	}
+}
+
+func TestRunAgentReplay_TrimRequiresOut(t *testing.T) {
+	_ = setupAgentTest(t)
+	setReplayFlags(t, "", false, true)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := runAgentReplay(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--trim requires") {
+		t.Fatalf("expected --trim error, got %v", err)
+	}
+}
+
+func TestRunAgentReplay_TrimNotWebP(t *testing.T) {
+	server := setupAgentTest(t)
+	server.AddResponse("/agents/"+agentIDTest+"/replay", 200, "replay-data")
+	server.AddResponse("/agents/"+agentIDTest, 200, agentStatusJSON())
+
+	path := filepath.Join(t.TempDir(), "agent.webm")
+	setReplayFlags(t, path, false, true)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := runAgentReplay(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to trim replay") {
+		t.Fatalf("expected trim error, got %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no replay file, got %v", err)
+	}
+}
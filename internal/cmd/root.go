@@ -10,7 +10,9 @@ import (
 	"github.com/salmonumbrella/notte-cli/internal/api"
 	"github.com/salmonumbrella/notte-cli/internal/auth"
 	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/errors"
 	"github.com/salmonumbrella/notte-cli/internal/output"
+	"github.com/salmonumbrella/notte-cli/internal/validate"
 )
 
 var (
@@ -20,6 +22,7 @@ var (
 	verbose        bool
 	requestTimeout int
 	yesFlag        bool // Skip confirmation prompts
+	contextName    string
 
 	// Version set at build time
 	Version = "dev"
@@ -45,7 +48,7 @@ func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		formatter := GetFormatter()
 		formatter.PrintError(err)
-		os.Exit(1)
+		os.Exit(errors.ExitCode(err))
 	}
 }
 
@@ -53,15 +56,18 @@ func init() {
 	// Hide completion command from help output (still accessible via `notte completion`)
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true
 
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json, yaml, csv, ndjson, template=..., jsonpath=...)")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable color output")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.PersistentFlags().IntVar(&requestTimeout, "timeout", 30, "API request timeout in seconds")
 	rootCmd.PersistentFlags().BoolVarP(&yesFlag, "yes", "y", false, "Skip confirmation prompts")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "Config context to use (overrides NOTTE_CONTEXT and the current context)")
 
 	// Set up confirmation state before each command
-	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
 		SetSkipConfirmation(yesFlag)
+		applyContextOutputFormat(cmd)
+		return validate.OutputFormat(outputFormat)
 	}
 
 	// Version command
@@ -89,20 +95,21 @@ func IsVerbose() bool {
 	return verbose
 }
 
-// GetClient creates an authenticated API client
+// GetClient creates an authenticated API client for the active context
 func GetClient() (*api.NotteClient, error) {
-	apiKey, _, err := auth.GetAPIKey("")
+	cfg, name, _, err := activeContext()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, _, err := auth.GetAPIKeyForContext("", name)
 	if err != nil {
 		return nil, err
 	}
 
 	baseURL := os.Getenv(config.EnvAPIURL)
 	if baseURL == "" {
-		cfg, err := config.Load()
-		if err != nil {
-			return nil, err
-		}
-		baseURL = cfg.APIURL
+		baseURL = cfg.APIURLFor(name)
 	}
 
 	if baseURL == "" {
@@ -112,6 +119,35 @@ func GetClient() (*api.NotteClient, error) {
 	return api.NewClientWithURL(apiKey, baseURL)
 }
 
+// activeContext loads the config and resolves the context selected by
+// --context, NOTTE_CONTEXT or the config's current context. The returned
+// context is nil when no context is selected.
+func activeContext() (*config.Config, string, *config.Context, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, "", nil, err
+	}
+	name := cfg.ResolveContextName(contextName)
+	ctx, err := cfg.GetContext(name)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return cfg, name, ctx, nil
+}
+
+// applyContextOutputFormat uses the active context's output format unless
+// --output was given explicitly
+func applyContextOutputFormat(cmd *cobra.Command) {
+	if cmd.Flags().Changed("output") {
+		return
+	}
+	_, _, ctx, err := activeContext()
+	if err != nil || ctx == nil || ctx.OutputFormat == "" {
+		return
+	}
+	outputFormat = ctx.OutputFormat
+}
+
 // GetContextWithTimeout wraps the provided context with a timeout
 func GetContextWithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
 	return context.WithTimeout(ctx, time.Duration(requestTimeout)*time.Second)
@@ -7,7 +7,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/config"
 	"github.com/salmonumbrella/notte-cli/internal/output"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
 )
 
 func TestGetFormatter_NoColor(t *testing.T) {
@@ -31,6 +35,29 @@ func TestGetFormatter_NoColor(t *testing.T) {
 	}
 }
 
+func TestRootPreRun_ValidatesOutputFormat(t *testing.T) {
+	testutil.SetupTestEnv(t)
+	config.SetTestConfigDir(t.TempDir())
+	t.Cleanup(func() { config.SetTestConfigDir("") })
+
+	origFormat := outputFormat
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("output", "", "")
+	_ = cmd.Flags().Set("output", "x")
+
+	outputFormat = "template={{.Name"
+	if err := rootCmd.PersistentPreRunE(cmd, nil); err == nil {
+		t.Error("expected invalid template to be rejected before running the command")
+	}
+
+	outputFormat = "yaml"
+	if err := rootCmd.PersistentPreRunE(cmd, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestIsVerbose(t *testing.T) {
 	origVerbose := verbose
 	t.Cleanup(func() { verbose = origVerbose })
@@ -0,0 +1,506 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+var (
+	runContinueOnError bool
+	runStepTimeout     int
+	runReportFile      string
+	runKeepSession     bool
+)
+
+// Step statuses reported by the runner
+const (
+	stepStatusOK      = "ok"
+	stepStatusFailed  = "failed"
+	stepStatusSkipped = "skipped"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run <file>",
+	Short: "Run a multi-step action file against a session",
+	Long: `Execute an ordered list of actions (goto, click, fill, scrape, wait, ...)
+against a browser session and report per-step results.
+
+The session is taken from --id, NOTTE_SESSION_ID, or the current session.
+If none is available, a new session is started for the run and stopped
+when it finishes (unless --keep-session is set).
+
+The action file is YAML or JSON. Each step is either a bare action or an
+object with an "action" key plus optional step settings:
+
+  steps:
+    - type: goto
+      url: https://example.com
+    - name: login
+      action: {type: click, id: B3}
+      timeout: 45s
+      continue_on_error: true
+
+String values may contain template expressions:
+
+  {{ env.NAME }}                            environment variable
+  {{ vault "vault_id" "https://site" "password" }}  vault credential (redacted in output)
+  {{ steps.login.result.data }}             output of an earlier named step`,
+	Example: `  # Run a script against the current session
+  notte run login.yaml
+
+  # Keep going when a step fails and write a JSON report for CI
+  notte run flow.json --continue-on-error --report report.json
+
+  # Read the action file from stdin
+  cat flow.yaml | notte run -`,
+	Args: cobra.ExactArgs(1),
+	RunE: runActionFile,
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	addSessionIDFlags(runCmd)
+	runCmd.Flags().BoolVar(&runContinueOnError, "continue-on-error", false, "Continue with the next step when a step fails")
+	runCmd.Flags().IntVar(&runStepTimeout, "step-timeout", 0, "Default per-step timeout in seconds (defaults to --timeout)")
+	runCmd.Flags().StringVar(&runReportFile, "report", "", "Also write the JSON report to this file")
+	runCmd.Flags().BoolVar(&runKeepSession, "keep-session", false, "Keep a session started by the run alive after it finishes")
+}
+
+// actionFile is the parsed representation of a `notte run` script
+type actionFile struct {
+	Steps []actionStep `json:"steps"`
+}
+
+// actionStep is a single step in an action file
+type actionStep struct {
+	Name            string         `json:"name,omitempty"`
+	Action          map[string]any `json:"action"`
+	Timeout         string         `json:"timeout,omitempty"`
+	ContinueOnError bool           `json:"continue_on_error,omitempty"`
+
+	// timeoutNotString is set when the step gave a timeout that is not a
+	// duration string; parseActionFile reports it with the step number
+	timeoutNotString bool
+}
+
+// UnmarshalJSON accepts both the explicit {"action": {...}} form and a bare
+// action object such as {"type": "goto", "url": "..."}.
+func (s *actionStep) UnmarshalJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// The timeout is a step setting in both forms and never part of the action
+	timeout, hasTimeout := raw["timeout"]
+	delete(raw, "timeout")
+	timeoutStr, isString := timeout.(string)
+	notString := hasTimeout && timeout != nil && !isString
+
+	if _, ok := raw["action"]; ok {
+		rest, err := json.Marshal(raw)
+		if err != nil {
+			return err
+		}
+		type plain actionStep
+		var p plain
+		if err := json.Unmarshal(rest, &p); err != nil {
+			return err
+		}
+		*s = actionStep(p)
+		s.Timeout, s.timeoutNotString = timeoutStr, notString
+		return nil
+	}
+
+	s.Timeout, s.timeoutNotString = timeoutStr, notString
+
+	// Bare action: lift step settings out, keep the rest as the action
+	if name, ok := raw["name"].(string); ok {
+		s.Name = name
+		delete(raw, "name")
+	}
+	if coe, ok := raw["continue_on_error"].(bool); ok {
+		s.ContinueOnError = coe
+		delete(raw, "continue_on_error")
+	}
+	s.Action = raw
+	return nil
+}
+
+// actionType returns the action's "type" discriminator
+func (s actionStep) actionType() string {
+	t, _ := s.Action["type"].(string)
+	return t
+}
+
+// runStepResult records the outcome of one executed step
+type runStepResult struct {
+	Index      int    `json:"index"`
+	Name       string `json:"name,omitempty"`
+	Type       string `json:"type"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Message    string `json:"message,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Data       any    `json:"data,omitempty"`
+}
+
+// runReport is the structured report emitted at the end of a run
+type runReport struct {
+	SessionID  string          `json:"session_id"`
+	Success    bool            `json:"success"`
+	Total      int             `json:"total"`
+	Passed     int             `json:"passed"`
+	Failed     int             `json:"failed"`
+	Skipped    int             `json:"skipped"`
+	DurationMs int64           `json:"duration_ms"`
+	Steps      []runStepResult `json:"steps"`
+}
+
+// parseActionFile decodes a YAML or JSON action file. A top-level list is
+// treated as the list of steps.
+func parseActionFile(data []byte) (*actionFile, error) {
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		if yamlErr := yaml.Unmarshal(data, &doc); yamlErr != nil {
+			return nil, fmt.Errorf("invalid action file: not valid JSON or YAML: %w", yamlErr)
+		}
+	}
+
+	if list, ok := doc.([]any); ok {
+		doc = map[string]any{"steps": list}
+	}
+
+	// Round-trip through JSON so steps use a single decoding path
+	normalized, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action file: %w", err)
+	}
+
+	var file actionFile
+	if err := json.Unmarshal(normalized, &file); err != nil {
+		return nil, fmt.Errorf("invalid action file: %w", err)
+	}
+
+	if len(file.Steps) == 0 {
+		return nil, errors.New("action file has no steps")
+	}
+
+	names := make(map[string]bool)
+	for i, step := range file.Steps {
+		if step.actionType() == "" {
+			return nil, fmt.Errorf("step %d: action is missing \"type\"", i+1)
+		}
+		if step.timeoutNotString {
+			return nil, fmt.Errorf("step %d: timeout must be a duration string such as \"30s\"", i+1)
+		}
+		if step.Timeout != "" {
+			if _, err := time.ParseDuration(step.Timeout); err != nil {
+				return nil, fmt.Errorf("step %d: invalid timeout %q: %w", i+1, step.Timeout, err)
+			}
+		}
+		if step.Name != "" {
+			if names[step.Name] {
+				return nil, fmt.Errorf("step %d: duplicate step name %q", i+1, step.Name)
+			}
+			names[step.Name] = true
+		}
+	}
+
+	return &file, nil
+}
+
+// readActionFile reads an action file from a path, or stdin for "-"
+func readActionFile(cmd *cobra.Command, path string) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read action file from stdin: %w", err)
+		}
+		path = "<stdin>"
+	} else {
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read action file %q: %w", path, err)
+		}
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, fmt.Errorf("action file %q is empty", path)
+	}
+	return data, nil
+}
+
+func runActionFile(cmd *cobra.Command, args []string) error {
+	data, err := readActionFile(cmd, args[0])
+	if err != nil {
+		return err
+	}
+
+	file, err := parseActionFile(data)
+	if err != nil {
+		return err
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	id := getCurrentSessionID()
+	if id == "" {
+		id, err = startRunSession(cmd, client)
+		if err != nil {
+			return err
+		}
+		if !runKeepSession {
+			defer stopRunSession(cmd, client, id)
+		}
+	}
+
+	renderer := newTemplateRenderer(cmd.Context(), client)
+	report := executeSteps(cmd.Context(), client, renderer, id, file.Steps)
+
+	if runReportFile != "" {
+		if err := writeRunReport(runReportFile, report); err != nil {
+			return err
+		}
+	}
+
+	if err := printRunReport(report); err != nil {
+		return err
+	}
+
+	if !report.Success {
+		return fmt.Errorf("run failed: %d of %d steps failed", report.Failed, report.Total)
+	}
+	return nil
+}
+
+// startRunSession starts a fresh session when the run has none to reuse
+func startRunSession(cmd *cobra.Command, client *api.NotteClient) (string, error) {
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
+	params := &api.SessionStartParams{}
+	resp, err := client.Client().SessionStartWithResponse(ctx, params, api.SessionStartJSONRequestBody{})
+	if err != nil {
+		return "", fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return "", err
+	}
+	if resp.JSON200 == nil {
+		return "", errors.New("failed to start session: empty response")
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Started session %s\n", resp.JSON200.SessionId)
+	return resp.JSON200.SessionId, nil
+}
+
+// stopRunSession stops a session that was started by the run
+func stopRunSession(cmd *cobra.Command, client *api.NotteClient, id string) {
+	ctx, cancel := GetContextWithTimeout(context.WithoutCancel(cmd.Context()))
+	defer cancel()
+
+	params := &api.SessionStopParams{}
+	resp, err := client.Client().SessionStopWithResponse(ctx, id, params)
+	if err == nil {
+		err = HandleAPIResponse(resp.HTTPResponse)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: could not stop session %s: %v\n", id, err)
+		return
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Stopped session %s\n", id)
+}
+
+// executeSteps runs each step in order and collects the results. Template
+// expressions in each action are rendered just before it is sent, so later
+// steps can reference the output of earlier ones.
+func executeSteps(ctx context.Context, client *api.NotteClient, renderer *templateRenderer, id string, steps []actionStep) *runReport {
+	report := &runReport{
+		SessionID: id,
+		Total:     len(steps),
+		Steps:     make([]runStepResult, 0, len(steps)),
+	}
+	start := time.Now()
+	aborted := false
+
+	for i, step := range steps {
+		result := runStepResult{
+			Index: i + 1,
+			Name:  step.Name,
+			Type:  step.actionType(),
+		}
+
+		if aborted {
+			result.Status = stepStatusSkipped
+			report.Skipped++
+			report.Steps = append(report.Steps, result)
+			continue
+		}
+
+		stepStart := time.Now()
+		execResp, err := executeStep(ctx, client, renderer, id, step)
+		result.DurationMs = time.Since(stepStart).Milliseconds()
+
+		switch {
+		case err != nil:
+			result.Status = stepStatusFailed
+			result.Error = err.Error()
+		case !execResp.Success:
+			result.Status = stepStatusFailed
+			result.Message = execResp.Message
+			if execResp.Exception != nil {
+				result.Error = *execResp.Exception
+			} else {
+				result.Error = execResp.Message
+			}
+			result.Data = execResp.Data
+		default:
+			result.Status = stepStatusOK
+			result.Message = execResp.Message
+			result.Data = execResp.Data
+		}
+
+		renderer.recordStep(step.Name, result)
+
+		// Never let resolved secrets reach the report or the terminal
+		result.Message = renderer.redact(result.Message)
+		result.Error = renderer.redact(result.Error)
+		result.Data = renderer.redactValue(result.Data)
+
+		if result.Status == stepStatusOK {
+			report.Passed++
+		} else {
+			report.Failed++
+			if !runContinueOnError && !step.ContinueOnError {
+				aborted = true
+			}
+		}
+
+		printStepProgress(result, len(steps))
+		report.Steps = append(report.Steps, result)
+	}
+
+	report.DurationMs = time.Since(start).Milliseconds()
+	report.Success = report.Failed == 0
+	return report
+}
+
+// executeStep sends a single action to the session with its own timeout
+func executeStep(ctx context.Context, client *api.NotteClient, renderer *templateRenderer, id string, step actionStep) (*api.ApiExecutionResponse, error) {
+	timeout := time.Duration(requestTimeout) * time.Second
+	if runStepTimeout > 0 {
+		timeout = time.Duration(runStepTimeout) * time.Second
+	}
+	if step.Timeout != "" {
+		// Already validated in parseActionFile
+		timeout, _ = time.ParseDuration(step.Timeout)
+	}
+
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	action, err := renderer.renderValue(step.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(action)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action: %w", err)
+	}
+
+	if IsVerbose() {
+		_, _ = fmt.Fprintf(os.Stderr, "-> %s\n", renderer.redact(string(payload)))
+	}
+
+	params := &api.PageExecuteParams{}
+	resp, err := client.Client().PageExecuteWithBodyWithResponse(stepCtx, id, params, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("step timed out after %s", timeout)
+		}
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, errors.New("empty response from API")
+	}
+
+	return resp.JSON200, nil
+}
+
+// printStepProgress reports a finished step on stderr
+func printStepProgress(result runStepResult, total int) {
+	label := result.Type
+	if result.Name != "" {
+		label = fmt.Sprintf("%s (%s)", result.Name, result.Type)
+	}
+
+	line := fmt.Sprintf("[%d/%d] %s: %s in %dms", result.Index, total, label, result.Status, result.DurationMs)
+	if result.Error != "" {
+		line += " - " + result.Error
+	}
+	_, _ = fmt.Fprintln(os.Stderr, line)
+}
+
+// writeRunReport writes the JSON report to a file
+func writeRunReport(path string, report *runReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report %q: %w", path, err)
+	}
+	return nil
+}
+
+// printRunReport prints the report as JSON or as a step table in text mode
+func printRunReport(report *runReport) error {
+	if IsStructuredOutput() {
+		return GetFormatter().Print(report)
+	}
+
+	rows := make([]map[string]any, 0, len(report.Steps))
+	for _, s := range report.Steps {
+		rows = append(rows, map[string]any{
+			"#":        s.Index,
+			"NAME":     s.Name,
+			"TYPE":     s.Type,
+			"STATUS":   strings.ToUpper(s.Status),
+			"DURATION": fmt.Sprintf("%dms", s.DurationMs),
+			"ERROR":    s.Error,
+		})
+	}
+
+	tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+	if err := tf.PrintTable([]string{"#", "NAME", "TYPE", "STATUS", "DURATION", "ERROR"}, rows); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(os.Stdout, "\nSession %s: %d passed, %d failed, %d skipped in %dms\n",
+		report.SessionID, report.Passed, report.Failed, report.Skipped, report.DurationMs)
+	return err
+}
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func writeActionFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write action file: %v", err)
+	}
+	return path
+}
+
+func resetRunFlags(t *testing.T) {
+	t.Helper()
+	origContinue := runContinueOnError
+	origStepTimeout := runStepTimeout
+	origReport := runReportFile
+	origKeep := runKeepSession
+	t.Cleanup(func() {
+		runContinueOnError = origContinue
+		runStepTimeout = origStepTimeout
+		runReportFile = origReport
+		runKeepSession = origKeep
+	})
+	runContinueOnError = false
+	runStepTimeout = 0
+	runReportFile = ""
+	runKeepSession = false
+}
+
+func TestParseActionFile_YAML(t *testing.T) {
+	data := []byte(`
+steps:
+  - type: goto
+    url: https://example.com
+  - name: login
+    action:
+      type: click
+      id: B3
+    timeout: 45s
+    continue_on_error: true
+`)
+
+	file, err := parseActionFile(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(file.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(file.Steps))
+	}
+	if file.Steps[0].actionType() != "goto" || file.Steps[0].Action["url"] != "https://example.com" {
+		t.Errorf("unexpected first step: %+v", file.Steps[0])
+	}
+	second := file.Steps[1]
+	if second.Name != "login" || second.actionType() != "click" || second.Timeout != "45s" || !second.ContinueOnError {
+		t.Errorf("unexpected second step: %+v", second)
+	}
+	if _, ok := second.Action["timeout"]; ok {
+		t.Error("step settings should not leak into the action payload")
+	}
+}
+
+func TestActionStep_NumericTimeoutLeftOutOfAction(t *testing.T) {
+	var step actionStep
+	if err := json.Unmarshal([]byte(`{"type":"goto","url":"https://example.com","timeout":30}`), &step); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := step.Action["timeout"]; ok {
+		t.Error("expected timeout to be removed from the action payload")
+	}
+	if !step.timeoutNotString {
+		t.Error("expected the numeric timeout to be flagged")
+	}
+}
+
+func TestParseActionFile_JSONList(t *testing.T) {
+	file, err := parseActionFile([]byte(`[{"type":"wait","time_ms":100},{"type":"scrape","name":"titles"}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(file.Steps) != 2 {
+		t.Fatalf("expected 2 steps, got %d", len(file.Steps))
+	}
+	if file.Steps[1].Name != "titles" {
+		t.Errorf("expected bare step name to be lifted, got %+v", file.Steps[1])
+	}
+	if _, ok := file.Steps[1].Action["name"]; ok {
+		t.Error("bare step name should not be sent with the action")
+	}
+}
+
+func TestParseActionFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"no steps", `{"steps": []}`, "no steps"},
+		{"missing type", `[{"url":"https://example.com"}]`, "missing \"type\""},
+		{"bad timeout", `[{"type":"wait","timeout":"soon"}]`, "invalid timeout"},
+		{"numeric timeout", "steps:\n  - type: goto\n    url: https://example.com\n    timeout: 30\n", `step 1: timeout must be a duration string such as "30s"`},
+		{"numeric timeout explicit", `[{"type":"wait"},{"action":{"type":"goto","url":"https://example.com"},"timeout":30}]`, `step 2: timeout must be a duration string`},
+		{"duplicate name", `[{"type":"wait","name":"a"},{"type":"wait","name":"a"}]`, "duplicate step name"},
+		{"invalid", "steps: [", "not valid JSON or YAML"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseActionFile([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunActionFile_Success(t *testing.T) {
+	server := setupSessionTest(t)
+	resetRunFlags(t)
+	execResp := fmt.Sprintf(`{"action":{"type":"goto"},"data":{"ok":true},"message":"done","session":%s,"success":true}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200, execResp)
+
+	path := writeActionFile(t, "flow.yaml", `
+steps:
+  - type: goto
+    url: https://example.com
+  - type: wait
+    time_ms: 10
+`)
+	runReportFile = filepath.Join(t.TempDir(), "report.json")
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, stderr := testutil.CaptureOutput(func() {
+		if err := runActionFile(cmd, []string{path}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	var report runReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to parse report: %v\n%s", err, stdout)
+	}
+	if !report.Success || report.Passed != 2 || report.SessionID != sessionIDTest {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if !strings.Contains(stderr, "[1/2] goto: ok") {
+		t.Errorf("expected step progress on stderr, got %q", stderr)
+	}
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/page/execute")
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 execute requests, got %d", len(reqs))
+	}
+	if !strings.Contains(reqs[0].Body, `"url":"https://example.com"`) {
+		t.Errorf("unexpected first request body: %s", reqs[0].Body)
+	}
+
+	if _, err := os.Stat(runReportFile); err != nil {
+		t.Errorf("expected report file to be written: %v", err)
+	}
+}
+
+func TestRunActionFile_StopsOnFailure(t *testing.T) {
+	server := setupSessionTest(t)
+	resetRunFlags(t)
+	execResp := fmt.Sprintf(`{"action":{"type":"click"},"data":null,"exception":"element not found","message":"failed","session":%s,"success":false}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200, execResp)
+
+	path := writeActionFile(t, "flow.json", `[{"type":"click","id":"B1"},{"type":"click","id":"B2"}]`)
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var runErr error
+	stdout, _ := testutil.CaptureOutput(func() {
+		runErr = runActionFile(cmd, []string{path})
+	})
+	if runErr == nil || !strings.Contains(runErr.Error(), "1 of 2 steps failed") {
+		t.Fatalf("expected run failure, got %v", runErr)
+	}
+
+	var report runReport
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to parse report: %v", err)
+	}
+	if report.Steps[0].Error != "element not found" {
+		t.Errorf("expected exception in step error, got %q", report.Steps[0].Error)
+	}
+	if report.Steps[1].Status != stepStatusSkipped || report.Skipped != 1 {
+		t.Errorf("expected second step to be skipped, got %+v", report.Steps[1])
+	}
+	if n := len(server.Requests("/sessions/" + sessionIDTest + "/page/execute")); n != 1 {
+		t.Errorf("expected 1 execute request, got %d", n)
+	}
+}
+
+func TestRunActionFile_ContinueOnError(t *testing.T) {
+	server := setupSessionTest(t)
+	resetRunFlags(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 500, `{"detail":"boom"}`)
+	runContinueOnError = true
+
+	path := writeActionFile(t, "flow.json", `[{"type":"reload"},{"type":"reload"}]`)
+
+	origFormat := outputFormat
+	outputFormat = "text"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var runErr error
+	stdout, _ := testutil.CaptureOutput(func() {
+		runErr = runActionFile(cmd, []string{path})
+	})
+	if runErr == nil {
+		t.Fatal("expected run failure")
+	}
+	if !strings.Contains(stdout, "0 passed, 2 failed, 0 skipped") {
+		t.Errorf("expected both steps to run, got %q", stdout)
+	}
+}
+
+func TestRunActionFile_StartsAndStopsSession(t *testing.T) {
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+	env.SetEnv("NOTTE_SESSION_ID", "")
+	server := testutil.NewMockServer()
+	t.Cleanup(func() { server.Close() })
+	env.SetEnv("NOTTE_API_URL", server.URL())
+	resetRunFlags(t)
+
+	tmpDir := t.TempDir()
+	config.SetTestConfigDir(tmpDir)
+	t.Cleanup(func() { config.SetTestConfigDir("") })
+
+	origID := sessionID
+	sessionID = ""
+	t.Cleanup(func() { sessionID = origID })
+
+	server.AddResponse("/sessions/start", 200, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/stop", 200, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200,
+		fmt.Sprintf(`{"action":{"type":"reload"},"data":null,"message":"ok","session":%s,"success":true}`, sessionJSON()))
+
+	path := writeActionFile(t, "flow.json", `[{"type":"reload"}]`)
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	_, stderr := testutil.CaptureOutput(func() {
+		if err := runActionFile(cmd, []string{path}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if len(server.Requests("/sessions/start")) != 1 {
+		t.Error("expected a session to be started")
+	}
+	if len(server.Requests("/sessions/"+sessionIDTest+"/stop")) != 1 {
+		t.Error("expected the started session to be stopped")
+	}
+	if !strings.Contains(stderr, "Started session "+sessionIDTest) {
+		t.Errorf("expected start notice on stderr, got %q", stderr)
+	}
+}
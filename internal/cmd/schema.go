@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
+	"github.com/salmonumbrella/notte-cli/internal/validate"
+)
+
+// exitSchemaMismatch is the exit code when a response does not conform to
+// the --schema passed on the command line
+const exitSchemaMismatch = 4
+
+// loadSchema reads and parses a JSON Schema from a --schema flag value.
+// Returns nil when the flag was not given.
+func loadSchema(cmd *cobra.Command, value string) (*validate.Schema, error) {
+	if value == "" {
+		return nil, nil
+	}
+	data, err := readJSONInput(cmd, value, "schema")
+	if err != nil {
+		return nil, err
+	}
+	return validate.ParseSchema(data)
+}
+
+// checkSchema validates a decoded JSON value against schema. It returns an
+// error listing every mismatched field, or nil if the value conforms.
+func checkSchema(schema *validate.Schema, value any, what string) error {
+	if schema == nil {
+		return nil
+	}
+
+	violations := schema.Validate(value)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s does not match schema (%d mismatches):", what, len(violations))
+	for _, v := range violations {
+		b.WriteString("\n  ")
+		b.WriteString(v.String())
+	}
+	return &notteErrors.ExitError{Code: exitSchemaMismatch, Err: fmt.Errorf("%s", b.String())}
+}
+
+// checkJSONStringSchema validates a string that should contain JSON, such as
+// an agent answer produced with a response format
+func checkJSONStringSchema(schema *validate.Schema, s string, what string) error {
+	if schema == nil {
+		return nil
+	}
+
+	var value any
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		return &notteErrors.ExitError{
+			Code: exitSchemaMismatch,
+			Err:  fmt.Errorf("%s does not match schema: not valid JSON: %w", what, err),
+		}
+	}
+	return checkSchema(schema, value, what)
+}
+
+// structuredScrapeData unwraps the {"success", "data", "error"} envelope the
+// API uses for structured scrape output
+func structuredScrapeData(structured any) (any, error) {
+	m, ok := structured.(map[string]any)
+	if !ok {
+		return structured, nil
+	}
+	if _, ok := m["data"]; !ok {
+		return structured, nil
+	}
+	if success, ok := m["success"].(bool); ok && !success {
+		msg, _ := m["error"].(string)
+		if msg == "" {
+			msg = "unknown error"
+		}
+		return nil, &notteErrors.ExitError{
+			Code: exitSchemaMismatch,
+			Err:  fmt.Errorf("structured extraction failed: %s", msg),
+		}
+	}
+	return m["data"], nil
+}
+
+// checkScrapeSchema validates the structured part of a scrape response
+func checkScrapeSchema(schema *validate.Schema, structured any) error {
+	if schema == nil {
+		return nil
+	}
+	data, err := structuredScrapeData(structured)
+	if err != nil {
+		return err
+	}
+	return checkSchema(schema, data, "scraped data")
+}
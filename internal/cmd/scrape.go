@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -10,19 +14,49 @@ import (
 )
 
 var (
-	scrapeInstructions string
-	scrapeOnlyMain     bool
+	scrapeInstructions   string
+	scrapeOnlyMain       bool
+	scrapeSchema         string
+	scrapeRequest        string
+	scrapeSelector       string
+	scrapeLinks          bool
+	scrapeImages         bool
+	scrapeOnlyImages     bool
+	scrapeIgnoredTags    []string
+	scrapeLinkHolders    bool
+	scrapeProxies        bool
+	scrapeSolveCaptchas  bool
+	scrapeBrowser        string
+	scrapeHeadless       bool
+	scrapeViewportW      int
+	scrapeViewportH      int
+	scrapeUserAgent      string
+	scrapeCdpURL         string
+	scrapeProfileID      string
+	scrapeProfilePersist bool
+	scrapeChromeArgs     []string
+	scrapeScreenshotType string
+	scrapeIdleTimeout    int
+	scrapeMaxDuration    int
+	scrapeFileStorage    bool
 
 	scrapeHtmlFile         string
 	scrapeHtmlInstructions string
 )
 
 var scrapeCmd = &cobra.Command{
-	Use:   "scrape <url>",
+	Use:   "scrape [url]",
 	Short: "Scrape a webpage",
-	Long:  "Quick scrape a webpage without creating a session.",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runScrape,
+	Long: `Quick scrape a webpage without creating a session.
+
+Every scrape request field is available as a flag. A full request can also be
+loaded with --request; flags given on the command line override its values.`,
+	Example: `  notte scrape https://example.com --selector main --scrape-links=false
+  notte scrape https://example.com --browser firefox --proxies --solve-captchas
+  notte scrape --request @scrape.json --instructions "Extract prices"
+  notte scrape --urls-file urls.txt --concurrency 8 --out results.jsonl --resume`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runScrape,
 }
 
 var scrapeHtmlCmd = &cobra.Command{
@@ -38,6 +72,29 @@ func init() {
 
 	scrapeCmd.Flags().StringVar(&scrapeInstructions, "instructions", "", "Extraction instructions")
 	scrapeCmd.Flags().BoolVar(&scrapeOnlyMain, "only-main-content", false, "Only main content")
+	scrapeCmd.Flags().StringVar(&scrapeSchema, "schema", "", "JSON Schema for structured output (JSON, @file, or - for stdin)")
+	scrapeCmd.Flags().StringVar(&scrapeRequest, "request", "", "Base scrape request (JSON, @file, or - for stdin); flags override its fields")
+	scrapeCmd.Flags().StringVar(&scrapeSelector, "selector", "", "Playwright selector to scope the scrape to")
+	scrapeCmd.Flags().BoolVar(&scrapeLinks, "scrape-links", true, "Scrape links from the page")
+	scrapeCmd.Flags().BoolVar(&scrapeImages, "scrape-images", true, "Scrape images from the page")
+	scrapeCmd.Flags().BoolVar(&scrapeOnlyImages, "only-images", false, "Only scrape images, excluding page content")
+	scrapeCmd.Flags().StringSliceVar(&scrapeIgnoredTags, "ignored-tags", nil, "HTML tags to ignore (comma-separated or repeated)")
+	scrapeCmd.Flags().BoolVar(&scrapeLinkHolders, "use-link-placeholders", false, "Use link/image placeholders to reduce tokens (experimental)")
+	scrapeCmd.Flags().BoolVar(&scrapeProxies, "proxies", false, "Use default proxies")
+	scrapeCmd.Flags().BoolVar(&scrapeSolveCaptchas, "solve-captchas", false, "Automatically solve captchas")
+	scrapeCmd.Flags().StringVar(&scrapeBrowser, "browser", "", "Browser type (chromium, chrome, chrome-nightly, chrome-turbo, firefox)")
+	scrapeCmd.Flags().BoolVar(&scrapeHeadless, "headless", true, "Run the browser in headless mode")
+	scrapeCmd.Flags().IntVar(&scrapeViewportW, "viewport-width", 0, "Viewport width in pixels")
+	scrapeCmd.Flags().IntVar(&scrapeViewportH, "viewport-height", 0, "Viewport height in pixels")
+	scrapeCmd.Flags().StringVar(&scrapeUserAgent, "user-agent", "", "Custom user agent string")
+	scrapeCmd.Flags().StringVar(&scrapeCdpURL, "cdp-url", "", "CDP URL of remote session provider")
+	scrapeCmd.Flags().StringVar(&scrapeProfileID, "profile", "", "Browser profile ID to use")
+	scrapeCmd.Flags().BoolVar(&scrapeProfilePersist, "profile-persist", false, "Save browser state to the profile afterwards")
+	scrapeCmd.Flags().StringArrayVar(&scrapeChromeArgs, "chrome-arg", nil, "Chrome argument (repeatable, replaces the defaults)")
+	scrapeCmd.Flags().StringVar(&scrapeScreenshotType, "screenshot-type", "", "Screenshot type (full, last_action, raw)")
+	scrapeCmd.Flags().IntVar(&scrapeIdleTimeout, "idle-timeout", 0, "Idle timeout in minutes")
+	scrapeCmd.Flags().IntVar(&scrapeMaxDuration, "max-duration", 0, "Maximum session lifetime in minutes")
+	scrapeCmd.Flags().BoolVar(&scrapeFileStorage, "use-file-storage", false, "Attach file storage to the session")
 
 	scrapeHtmlCmd.Flags().StringVar(&scrapeHtmlFile, "file", "", "Path to HTML file (required)")
 	_ = scrapeHtmlCmd.MarkFlagRequired("file")
@@ -45,27 +102,46 @@ func init() {
 }
 
 func runScrape(cmd *cobra.Command, args []string) error {
-	url := args[0]
+	// Only one input can be read from stdin
+	var stdinFlags []string
+	for _, f := range []struct{ name, value string }{
+		{"--urls-file", scrapeURLsFile},
+		{"--request", scrapeRequest},
+		{"--schema", scrapeSchema},
+	} {
+		if readsStdin(f.value) {
+			stdinFlags = append(stdinFlags, f.name)
+		}
+	}
+	if len(stdinFlags) > 1 {
+		return fmt.Errorf("only one input can read stdin, got %s", strings.Join(stdinFlags, ", "))
+	}
 
-	client, err := GetClient()
+	schema, err := loadSchema(cmd, scrapeSchema)
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	body := api.ScrapeWebpageJSONRequestBody{
-		Url: url,
+	if scrapeURLsFile != "" {
+		return runBulkScrape(cmd, args, schema)
 	}
 
-	if scrapeInstructions != "" {
-		body.Instructions = &scrapeInstructions
+	body, err := buildScrapeRequest(cmd, args)
+	if err != nil {
+		return err
 	}
-	if scrapeOnlyMain {
-		body.OnlyMainContent = &scrapeOnlyMain
+	if schema != nil {
+		body.ResponseFormat = schema.Raw()
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
 	}
 
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
 	resp, err := client.Client().ScrapeWebpageWithResponse(ctx, nil, body)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
@@ -75,7 +151,142 @@ func runScrape(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return GetFormatter().Print(resp.JSON200)
+	if err := GetFormatter().Print(resp.JSON200); err != nil {
+		return err
+	}
+	if resp.JSON200 != nil {
+		return checkScrapeSchema(schema, resp.JSON200.Structured)
+	}
+	return nil
+}
+
+// buildScrapeRequest loads the --request base (if any) and applies the URL
+// argument and every flag set on the command line on top of it
+func buildScrapeRequest(cmd *cobra.Command, args []string) (api.ScrapeWebpageJSONRequestBody, error) {
+	var body api.ScrapeWebpageJSONRequestBody
+
+	if scrapeRequest != "" {
+		data, err := readJSONInput(cmd, scrapeRequest, "request")
+		if err != nil {
+			return body, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&body); err != nil {
+			return body, fmt.Errorf("invalid scrape request: %w", err)
+		}
+	}
+
+	if len(args) > 0 {
+		body.Url = args[0]
+	}
+	if body.Url == "" {
+		return body, errors.New("URL required: pass it as an argument or set \"url\" in --request")
+	}
+
+	flags := cmd.Flags()
+
+	if scrapeInstructions != "" {
+		body.Instructions = &scrapeInstructions
+	}
+	if scrapeOnlyMain || flags.Changed("only-main-content") {
+		body.OnlyMainContent = &scrapeOnlyMain
+	}
+	if scrapeSelector != "" {
+		body.Selector = &scrapeSelector
+	}
+	if flags.Changed("scrape-links") {
+		body.ScrapeLinks = &scrapeLinks
+	}
+	if flags.Changed("scrape-images") {
+		body.ScrapeImages = &scrapeImages
+	}
+	if flags.Changed("only-images") {
+		body.OnlyImages = &scrapeOnlyImages
+	}
+	if len(scrapeIgnoredTags) > 0 {
+		tags := make([]interface{}, len(scrapeIgnoredTags))
+		for i, tag := range scrapeIgnoredTags {
+			tags[i] = tag
+		}
+		body.IgnoredTags = &tags
+	}
+	if flags.Changed("use-link-placeholders") {
+		body.UseLinkPlaceholders = &scrapeLinkHolders
+	}
+	if flags.Changed("proxies") {
+		var proxies api.GlobalScrapeRequest_Proxies
+		if err := proxies.FromGlobalScrapeRequestProxies1(scrapeProxies); err != nil {
+			return body, fmt.Errorf("failed to set proxies: %w", err)
+		}
+		body.Proxies = &proxies
+	}
+	if flags.Changed("solve-captchas") {
+		body.SolveCaptchas = &scrapeSolveCaptchas
+	}
+	if scrapeBrowser != "" {
+		browserType := api.GlobalScrapeRequestBrowserType(scrapeBrowser)
+		switch browserType {
+		case api.GlobalScrapeRequestBrowserTypeChromium, api.GlobalScrapeRequestBrowserTypeChrome,
+			api.GlobalScrapeRequestBrowserTypeChromeNightly, api.GlobalScrapeRequestBrowserTypeChromeTurbo,
+			api.GlobalScrapeRequestBrowserTypeFirefox:
+		default:
+			return body, fmt.Errorf("invalid browser: expected chromium|chrome|chrome-nightly|chrome-turbo|firefox, got %q", scrapeBrowser)
+		}
+		body.BrowserType = &browserType
+	}
+	if flags.Changed("headless") {
+		body.Headless = &scrapeHeadless
+	}
+	if scrapeViewportW > 0 {
+		body.ViewportWidth = &scrapeViewportW
+	}
+	if scrapeViewportH > 0 {
+		body.ViewportHeight = &scrapeViewportH
+	}
+	if scrapeUserAgent != "" {
+		body.UserAgent = &scrapeUserAgent
+	}
+	if scrapeCdpURL != "" {
+		body.CdpUrl = &scrapeCdpURL
+	}
+	if scrapeProfileID != "" {
+		profile := api.SessionProfile{Id: scrapeProfileID}
+		if flags.Changed("profile-persist") {
+			profile.Persist = &scrapeProfilePersist
+		}
+		body.Profile = profile
+	} else if flags.Changed("profile-persist") {
+		return body, errors.New("--profile-persist requires --profile")
+	}
+	if len(scrapeChromeArgs) > 0 {
+		chromeArgs := make([]interface{}, len(scrapeChromeArgs))
+		for i, arg := range scrapeChromeArgs {
+			chromeArgs[i] = arg
+		}
+		body.ChromeArgs = &chromeArgs
+	}
+	if scrapeScreenshotType != "" {
+		screenshotType := api.GlobalScrapeRequestScreenshotType(scrapeScreenshotType)
+		switch screenshotType {
+		case api.GlobalScrapeRequestScreenshotTypeFull, api.GlobalScrapeRequestScreenshotTypeLastAction,
+			api.GlobalScrapeRequestScreenshotTypeRaw:
+		default:
+			return body, fmt.Errorf("invalid screenshot type: expected full|last_action|raw, got %q", scrapeScreenshotType)
+		}
+		body.ScreenshotType = &screenshotType
+	}
+	if scrapeIdleTimeout > 0 {
+		body.IdleTimeoutMinutes = &scrapeIdleTimeout
+	}
+	if scrapeMaxDuration > 0 {
+		body.MaxDurationMinutes = &scrapeMaxDuration
+	}
+	if flags.Changed("use-file-storage") {
+		body.UseFileStorage = &scrapeFileStorage
+	}
+
+	return body, nil
 }
 
 func runScrapeHtml(cmd *cobra.Command, args []string) error {
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/validate"
+)
+
+var (
+	scrapeURLsFile    string
+	scrapeConcurrency int
+	scrapeOut         string
+	scrapeFailures    string
+	scrapeResume      bool
+)
+
+func init() {
+	scrapeCmd.Flags().StringVar(&scrapeURLsFile, "urls-file", "", "Scrape every URL in a file, one per line (- for stdin)")
+	scrapeCmd.Flags().IntVar(&scrapeConcurrency, "concurrency", 4, "Parallel requests with --urls-file")
+	scrapeCmd.Flags().StringVar(&scrapeOut, "out", "", "JSONL file for --urls-file results (default: stdout)")
+	scrapeCmd.Flags().StringVar(&scrapeFailures, "failures", "", "JSONL file for failed URLs (default: <out>.failures.jsonl, or stderr)")
+	scrapeCmd.Flags().BoolVar(&scrapeResume, "resume", false, "Skip URLs already present in --out and append to it")
+}
+
+// bulkScrapeResult is one JSONL line in the results file
+type bulkScrapeResult struct {
+	URL    string              `json:"url"`
+	Result *api.ScrapeResponse `json:"result"`
+}
+
+// bulkScrapeFailure is one JSONL line in the failures file
+type bulkScrapeFailure struct {
+	URL   string `json:"url"`
+	Error string `json:"error"`
+}
+
+// bulkScrapeOutcome is passed from workers back to the writer
+type bulkScrapeOutcome struct {
+	url      string
+	result   *api.ScrapeResponse
+	err      error
+	duration time.Duration
+}
+
+func runBulkScrape(cmd *cobra.Command, args []string, schema *validate.Schema) error {
+	if len(args) > 0 {
+		return errors.New("pass either a URL argument or --urls-file, not both")
+	}
+	if scrapeConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", scrapeConcurrency)
+	}
+	if scrapeResume && scrapeOut == "" {
+		return errors.New("--resume requires --out")
+	}
+
+	urls, err := readURLList(cmd, scrapeURLsFile)
+	if err != nil {
+		return err
+	}
+
+	done := map[string]bool{}
+	if scrapeResume {
+		done, err = completedScrapeURLs(scrapeOut)
+		if err != nil {
+			return err
+		}
+	}
+
+	var pending []string
+	for _, u := range urls {
+		if !done[u] {
+			pending = append(pending, u)
+		}
+	}
+	skipped := len(urls) - len(pending)
+
+	// Flags are applied once; each job only swaps the URL
+	base, err := buildScrapeRequest(cmd, []string{urls[0]})
+	if err != nil {
+		return err
+	}
+	if schema != nil {
+		base.ResponseFormat = schema.Raw()
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	out, closeOut, err := openBulkOutput(scrapeOut, scrapeResume)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	failuresPath := scrapeFailures
+	if failuresPath == "" && scrapeOut != "" {
+		failuresPath = strings.TrimSuffix(scrapeOut, ".jsonl") + ".failures.jsonl"
+	}
+	failOut := io.Writer(os.Stderr)
+	if failuresPath != "" {
+		f, err := os.Create(failuresPath)
+		if err != nil {
+			return fmt.Errorf("failed to create failures file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		failOut = f
+	}
+
+	if skipped > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Resuming: %d of %d URLs already scraped\n", skipped, len(urls))
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+	outcomes := scrapeURLs(ctx, client, base, schema, pending, scrapeConcurrency)
+
+	// abort stops the remaining scrapes and drains their outcomes so no
+	// worker is left blocked sending
+	abort := func(err error) error {
+		cancel()
+		for range outcomes {
+		}
+		return err
+	}
+
+	succeeded, failed := 0, 0
+	outEnc := json.NewEncoder(out)
+	failEnc := json.NewEncoder(failOut)
+	for o := range outcomes {
+		n := succeeded + failed + 1
+		if o.err != nil {
+			failed++
+			_, _ = fmt.Fprintf(os.Stderr, "[%d/%d] %s: failed in %dms - %v\n", n, len(pending), o.url, o.duration.Milliseconds(), o.err)
+			if err := failEnc.Encode(bulkScrapeFailure{URL: o.url, Error: o.err.Error()}); err != nil {
+				return abort(fmt.Errorf("failed to write failure: %w", err))
+			}
+			continue
+		}
+
+		succeeded++
+		_, _ = fmt.Fprintf(os.Stderr, "[%d/%d] %s: ok in %dms\n", n, len(pending), o.url, o.duration.Milliseconds())
+		if err := outEnc.Encode(bulkScrapeResult{URL: o.url, Result: o.result}); err != nil {
+			return abort(fmt.Errorf("failed to write result: %w", err))
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stderr, "Scraped %d URLs: %d succeeded, %d failed, %d skipped\n", len(urls), succeeded, failed, skipped)
+
+	if err := cmd.Context().Err(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d URLs failed", failed, len(pending))
+	}
+	return nil
+}
+
+// scrapeURLs fans requests out over a fixed number of workers sharing one
+// client, so retries and the circuit breaker apply across all of them.
+// The returned channel is closed once every URL has been handled.
+func scrapeURLs(ctx context.Context, client *api.NotteClient, base api.ScrapeWebpageJSONRequestBody, schema *validate.Schema, urls []string, concurrency int) <-chan bulkScrapeOutcome {
+	jobs := make(chan string)
+	outcomes := make(chan bulkScrapeOutcome)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, max(len(urls), 1)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range jobs {
+				start := time.Now()
+				result, err := scrapeOne(ctx, client, base, schema, u)
+				outcomes <- bulkScrapeOutcome{url: u, result: result, err: err, duration: time.Since(start)}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for _, u := range urls {
+			select {
+			case jobs <- u:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	return outcomes
+}
+
+// scrapeOne scrapes a single URL and checks it against the schema, if any
+func scrapeOne(ctx context.Context, client *api.NotteClient, base api.ScrapeWebpageJSONRequestBody, schema *validate.Schema, url string) (*api.ScrapeResponse, error) {
+	ctx, cancel := GetContextWithTimeout(ctx)
+	defer cancel()
+
+	body := base
+	body.Url = url
+
+	resp, err := client.Client().ScrapeWebpageWithResponse(ctx, nil, body)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, errors.New("empty response from API")
+	}
+	if err := checkScrapeSchema(schema, resp.JSON200.Structured); err != nil {
+		return nil, err
+	}
+	return resp.JSON200, nil
+}
+
+// readURLList reads URLs one per line, ignoring blank lines, # comments and
+// duplicates
+func readURLList(cmd *cobra.Command, path string) ([]string, error) {
+	var data []byte
+	var err error
+	if readsStdin(path) {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read URLs file: %w", err)
+	}
+
+	seen := map[string]bool{}
+	var urls []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || seen[line] {
+			continue
+		}
+		seen[line] = true
+		urls = append(urls, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read URLs file: %w", err)
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no URLs found in %s", path)
+	}
+	return urls, nil
+}
+
+// completedScrapeURLs returns the URLs already recorded in a results file.
+// A missing file means nothing has been scraped yet; a truncated last line
+// from an interrupted run is ignored.
+func completedScrapeURLs(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var line bulkScrapeResult
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil || line.URL == "" {
+			continue
+		}
+		done[line.URL] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return done, nil
+}
+
+// openBulkOutput opens the results destination. When resuming, a partial
+// trailing line is dropped so appended results start on a fresh line.
+func openBulkOutput(path string, resume bool) (io.Writer, func(), error) {
+	if path == "" {
+		return os.Stdout, func() {}, nil
+	}
+
+	if resume {
+		if err := trimPartialLine(path); err != nil {
+			return nil, nil, err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		return f, func() { _ = f.Close() }, nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return f, func() { _ = f.Close() }, nil
+}
+
+// trimPartialLine truncates a file after its last newline
+func trimPartialLine(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) == 0 || data[len(data)-1] == '\n' {
+		return nil
+	}
+	keep := bytes.LastIndexByte(data, '\n') + 1
+	if err := os.Truncate(path, int64(keep)); err != nil {
+		return fmt.Errorf("failed to trim %s: %w", path, err)
+	}
+	return nil
+}
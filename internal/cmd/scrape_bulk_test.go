@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setupBulkScrapeTest(t *testing.T, urls string) (*testutil.MockServer, string) {
+	t.Helper()
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	t.Cleanup(func() { server.Close() })
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	dir := t.TempDir()
+	urlsPath := filepath.Join(dir, "urls.txt")
+	if err := os.WriteFile(urlsPath, []byte(urls), 0o600); err != nil {
+		t.Fatalf("failed to write URLs file: %v", err)
+	}
+
+	origFile, origConc, origOut, origFail, origResume, origSchema, origRequest := scrapeURLsFile, scrapeConcurrency, scrapeOut, scrapeFailures, scrapeResume, scrapeSchema, scrapeRequest
+	t.Cleanup(func() {
+		scrapeURLsFile, scrapeConcurrency, scrapeOut, scrapeFailures, scrapeResume, scrapeSchema, scrapeRequest = origFile, origConc, origOut, origFail, origResume, origSchema, origRequest
+	})
+	scrapeURLsFile = urlsPath
+	scrapeConcurrency = 1
+	scrapeOut = filepath.Join(dir, "results.jsonl")
+	scrapeFailures = ""
+	scrapeResume = false
+	scrapeSchema = ""
+	scrapeRequest = ""
+
+	return server, dir
+}
+
+func scrapeResponseJSON(structured string) string {
+	return `{"markdown":"md","structured":` + structured + `,"session":` + scrapeSessionJSON() + `}`
+}
+
+func readJSONLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	var lines []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var m map[string]any
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", line, err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+func TestRunScrape_URLsFile(t *testing.T) {
+	server, dir := setupBulkScrapeTest(t, "https://a.example\n\n# comment\nhttps://b.example\nhttps://a.example\nhttps://c.example\n")
+	scrapeSchema = `{"type":"object"}`
+	server.AddResponseSequence("/scrape", 200,
+		scrapeResponseJSON(`{"success":true,"data":{}}`),
+		scrapeResponseJSON(`{"success":false,"data":null,"error":"blocked"}`),
+		scrapeResponseJSON(`{"success":true,"data":{}}`),
+	)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var err error
+	_, stderr := testutil.CaptureOutput(func() {
+		err = runScrape(cmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 URLs failed") {
+		t.Fatalf("expected one failure, got %v", err)
+	}
+	if !strings.Contains(stderr, "2 succeeded, 1 failed, 0 skipped") {
+		t.Errorf("expected summary on stderr, got %q", stderr)
+	}
+
+	results := readJSONLines(t, scrapeOut)
+	if len(results) != 2 || results[0]["url"] != "https://a.example" || results[1]["url"] != "https://c.example" {
+		t.Errorf("unexpected results: %v", results)
+	}
+	failures := readJSONLines(t, filepath.Join(dir, "results.failures.jsonl"))
+	if len(failures) != 1 || failures[0]["url"] != "https://b.example" || !strings.Contains(failures[0]["error"].(string), "blocked") {
+		t.Errorf("unexpected failures: %v", failures)
+	}
+
+	reqs := server.Requests("/scrape")
+	if len(reqs) != 3 {
+		t.Fatalf("expected duplicates and comments to be skipped, got %d requests", len(reqs))
+	}
+	if !strings.Contains(reqs[1].Body, `"url":"https://b.example"`) || !strings.Contains(reqs[1].Body, `"response_format":{"type":"object"}`) {
+		t.Errorf("unexpected request body: %s", reqs[1].Body)
+	}
+}
+
+func TestRunScrape_URLsFileResume(t *testing.T) {
+	server, _ := setupBulkScrapeTest(t, "https://a.example\nhttps://b.example\nhttps://c.example\n")
+	server.AddResponse("/scrape", 200, scrapeResponseJSON(`{}`))
+
+	// An interrupted run left one complete line and one partial line
+	partial := `{"url":"https://a.example","result":{}}` + "\n" + `{"url":"https://b.exa`
+	if err := os.WriteFile(scrapeOut, []byte(partial), 0o600); err != nil {
+		t.Fatalf("failed to write partial output: %v", err)
+	}
+	scrapeResume = true
+	scrapeConcurrency = 4
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	_, stderr := testutil.CaptureOutput(func() {
+		if err := runScrape(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(stderr, "Resuming: 1 of 3 URLs already scraped") {
+		t.Errorf("expected resume notice, got %q", stderr)
+	}
+	if n := len(server.Requests("/scrape")); n != 2 {
+		t.Errorf("expected 2 requests after resume, got %d", n)
+	}
+
+	results := readJSONLines(t, scrapeOut)
+	urls := map[any]bool{}
+	for _, r := range results {
+		urls[r["url"]] = true
+	}
+	if len(results) != 3 || !urls["https://a.example"] || !urls["https://b.example"] || !urls["https://c.example"] {
+		t.Errorf("unexpected results after resume: %v", results)
+	}
+}
+
+func TestRunScrape_URLsFileErrors(t *testing.T) {
+	setupBulkScrapeTest(t, "https://a.example\n")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	if err := runScrape(cmd, []string{"https://x.example"}); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+
+	scrapeOut = ""
+	scrapeResume = true
+	if err := runScrape(cmd, nil); err == nil || !strings.Contains(err.Error(), "--resume requires --out") {
+		t.Errorf("expected resume error, got %v", err)
+	}
+
+	scrapeResume = false
+	for _, flags := range [][3]string{
+		{"-", "-", ""},
+		{"-", "@-", ""},
+		{"-", "", "-"},
+		{"-", "", "@-"},
+		{"@-", "-", ""},
+		{"", "-", "-"},
+		{"", "@-", " @- "},
+	} {
+		scrapeURLsFile, scrapeRequest, scrapeSchema = flags[0], flags[1], flags[2]
+		if err := runScrape(cmd, nil); err == nil || !strings.Contains(err.Error(), "only one input can read stdin") {
+			t.Errorf("expected stdin conflict error for %q, got %v", flags, err)
+		}
+	}
+}
+
+func TestRunScrape_URLsFileWriteError(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full not available")
+	}
+	server, dir := setupBulkScrapeTest(t, "https://a.example\nhttps://b.example\nhttps://c.example\nhttps://d.example\n")
+	scrapeConcurrency = 2
+	scrapeOut = "/dev/full"
+	scrapeFailures = filepath.Join(dir, "failures.jsonl")
+	server.AddResponse("/scrape", 200, scrapeResponseJSON(`{"success":true,"data":{}}`))
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var err error
+	_, _ = testutil.CaptureOutput(func() {
+		err = runScrape(cmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), "failed to write result") {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
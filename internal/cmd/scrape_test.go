@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
 	"github.com/salmonumbrella/notte-cli/internal/testutil"
 )
 
@@ -119,3 +122,152 @@ func TestRunScrapeHtmlMissingFile(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestRunScrape_Schema(t *testing.T) {
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	defer server.Close()
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	schema := `{"type":"object","required":["title","price"],"properties":{"title":{"type":"string"},"price":{"type":"number"}}}`
+	if err := os.WriteFile(schemaPath, []byte(schema), 0o600); err != nil {
+		t.Fatalf("failed to write schema: %v", err)
+	}
+
+	origSchema := scrapeSchema
+	scrapeSchema = "@" + schemaPath
+	t.Cleanup(func() { scrapeSchema = origSchema })
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	tests := []struct {
+		name       string
+		structured string
+		wantErr    []string
+	}{
+		{"conforms", `{"success":true,"data":{"title":"Lamp","price":12}}`, nil},
+		{"mismatch", `{"success":true,"data":{"title":3}}`, []string{"$.price: required field is missing", "$.title: expected string, got number"}},
+		{"extraction failed", `{"success":false,"data":null,"error":"no products"}`, []string{"no products"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.AddResponse("/scrape", 200, `{"markdown":"","structured":`+tt.structured+`,"session":`+scrapeSessionJSON()+`}`)
+
+			var err error
+			stdout, _ := testutil.CaptureOutput(func() {
+				err = runScrape(cmd, []string{"https://example.com"})
+			})
+			if stdout == "" {
+				t.Error("expected the response to be printed")
+			}
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if notteErrors.ExitCode(err) != exitSchemaMismatch {
+				t.Fatalf("expected schema exit code, got %v", err)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in error, got %q", want, err.Error())
+				}
+			}
+		})
+	}
+
+	reqs := server.Requests("/scrape")
+	if len(reqs) == 0 || !strings.Contains(reqs[0].Body, `"response_format":{"properties"`) {
+		t.Errorf("expected schema to be sent as response_format, got %+v", reqs)
+	}
+}
+
+func TestBuildScrapeRequest_MergesRequestFileAndFlags(t *testing.T) {
+	requestPath := filepath.Join(t.TempDir(), "scrape.json")
+	request := `{"url":"https://from-file.example","selector":"#old","scrape_links":true,"browser_type":"firefox","proxies":true}`
+	if err := os.WriteFile(requestPath, []byte(request), 0o600); err != nil {
+		t.Fatalf("failed to write request: %v", err)
+	}
+
+	origRequest, origSelector, origLinks := scrapeRequest, scrapeSelector, scrapeLinks
+	origTags, origArgs, origProfile := scrapeIgnoredTags, scrapeChromeArgs, scrapeProfileID
+	t.Cleanup(func() {
+		scrapeRequest, scrapeSelector, scrapeLinks = origRequest, origSelector, origLinks
+		scrapeIgnoredTags, scrapeChromeArgs, scrapeProfileID = origTags, origArgs, origProfile
+	})
+	scrapeRequest = "@" + requestPath
+	scrapeSelector = "main"
+	scrapeIgnoredTags = []string{"nav", "footer"}
+	scrapeChromeArgs = []string{"--lang=fr,en"}
+	scrapeProfileID = "prof_1"
+
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolVar(&scrapeLinks, "scrape-links", true, "")
+	if err := cmd.Flags().Set("scrape-links", "false"); err != nil {
+		t.Fatalf("failed to set flag: %v", err)
+	}
+
+	body, err := buildScrapeRequest(cmd, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, _ := json.Marshal(body)
+	got := string(data)
+	for _, want := range []string{
+		`"url":"https://from-file.example"`,
+		`"selector":"main"`,
+		`"scrape_links":false`,
+		`"browser_type":"firefox"`,
+		`"proxies":true`,
+		`"ignored_tags":["nav","footer"]`,
+		`"chrome_args":["--lang=fr,en"]`,
+		`"profile":{"id":"prof_1"}`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in request, got %s", want, got)
+		}
+	}
+
+	body, err = buildScrapeRequest(cmd, []string{"https://arg.example"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if body.Url != "https://arg.example" {
+		t.Errorf("expected URL argument to override request file, got %q", body.Url)
+	}
+}
+
+func TestBuildScrapeRequest_Errors(t *testing.T) {
+	origRequest, origBrowser := scrapeRequest, scrapeBrowser
+	t.Cleanup(func() { scrapeRequest, scrapeBrowser = origRequest, origBrowser })
+
+	cmd := &cobra.Command{}
+
+	scrapeRequest = ""
+	if _, err := buildScrapeRequest(cmd, nil); err == nil || !strings.Contains(err.Error(), "URL required") {
+		t.Errorf("expected missing URL error, got %v", err)
+	}
+
+	scrapeRequest = `{"url":"https://example.com","selectr":"main"}`
+	if _, err := buildScrapeRequest(cmd, nil); err == nil || !strings.Contains(err.Error(), "selectr") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+
+	scrapeRequest = ""
+	scrapeBrowser = "safari"
+	if _, err := buildScrapeRequest(cmd, []string{"https://example.com"}); err == nil || !strings.Contains(err.Error(), "invalid browser") {
+		t.Errorf("expected invalid browser error, got %v", err)
+	}
+}
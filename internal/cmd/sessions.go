@@ -2,17 +2,23 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
 	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+	"github.com/salmonumbrella/notte-cli/internal/screenshot"
 )
 
 var (
@@ -31,25 +37,29 @@ var (
 var (
 	sessionID                 string
 	sessionObserveURL         string
+	sessionObserveScreenshot  string
+	sessionObserveAnnotate    bool
+	sessionObserveMarkdown    bool
+	sessionObserveInstruct    string
+	sessionObserveMinActions  int
+	sessionObserveMaxActions  int
+	sessionObservePerception  string
 	sessionExecuteAction      string
 	sessionScrapeInstructions string
 	sessionScrapeOnlyMain     bool
+	sessionScrapeSchema       string
 	sessionCookiesSetFile     string
 )
 
-// getCurrentSessionID returns the session ID from flag, env var, or file (in priority order)
+// getCurrentSessionID returns the session ID from flags, env vars, or file
+// (see resolveCurrentSession), or "" if none is selected or a name is unknown
 func getCurrentSessionID() string {
-	// 1. Check --id flag (already in sessionID variable if set)
-	if sessionID != "" {
-		return sessionID
-	}
-
-	// 2. Check NOTTE_SESSION_ID env var
-	if envID := os.Getenv(config.EnvSessionID); envID != "" {
-		return envID
-	}
+	cur, _ := resolveCurrentSession()
+	return cur.ID
+}
 
-	// 3. Check current_session file
+// readCurrentSessionFile returns the ID in the current_session file
+func readCurrentSessionFile() string {
 	configDir, err := config.Dir()
 	if err != nil {
 		return ""
@@ -94,11 +104,23 @@ func clearCurrentSession() error {
 	return nil
 }
 
-// requireSessionID ensures a session ID is available from flag, env, or file
+// clearCurrentSessionIf clears the current session only if it is id, so
+// stopping another session leaves it in place
+func clearCurrentSessionIf(id string) {
+	if readCurrentSessionFile() == id {
+		_ = clearCurrentSession()
+	}
+}
+
+// requireSessionID ensures a session ID is available from flags, env, or file
 func requireSessionID() error {
-	sessionID = getCurrentSessionID()
+	cur, err := resolveCurrentSession()
+	if err != nil {
+		return err
+	}
+	sessionID = cur.ID
 	if sessionID == "" {
-		return errors.New("session ID required: use --id flag, set NOTTE_SESSION_ID env var, or start a session first")
+		return errors.New("session ID required: use --id or --session, set NOTTE_SESSION_ID or NOTTE_SESSION, or start a session first")
 	}
 	return nil
 }
@@ -112,7 +134,10 @@ var sessionsCmd = &cobra.Command{
 var sessionsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List active sessions",
-	RunE:  runSessionsList,
+	Example: `  notte sessions list --active --current-token
+  notte sessions list --status closed,timed_out --since 24h --all
+  notte sessions list --browser firefox --since 2024-01-01 --until 2024-02-01`,
+	RunE: runSessionsList,
 }
 
 var sessionsStartCmd = &cobra.Command{
@@ -153,7 +178,10 @@ var sessionsExecuteCmd = &cobra.Command{
   notte sessions execute --id <session-id> --action @action.json
 
   # From stdin
-  echo '{"action": "click", "selector": "#btn"}' | notte sessions execute --id <session-id>`,
+  echo '{"action": "click", "selector": "#btn"}' | notte sessions execute --id <session-id>
+
+  # With templates resolved from the environment and a vault
+  notte sessions execute --action '{"type": "fill", "id": "I2", "value": "{{ vault \"vault_123\" \"https://example.com\" \"password\" }}"}'`,
 	RunE: runSessionExecute,
 }
 
@@ -167,15 +195,26 @@ var sessionsScrapeCmd = &cobra.Command{
 var sessionsCookiesCmd = &cobra.Command{
 	Use:   "cookies",
 	Short: "Get all cookies for the session",
-	Args:  cobra.NoArgs,
-	RunE:  runSessionCookies,
+	Long: `Get all cookies for the session.
+
+--format exports them as JSON that cookies-set reads back, as a Netscape
+cookies.txt file for curl -b and wget, or as a Cookie header for curl -H.`,
+	Example: `  notte sessions cookies --format netscape > cookies.txt
+  curl -H "$(notte sessions cookies --format header --domain example.com)" https://example.com`,
+	Args: cobra.NoArgs,
+	RunE: runSessionCookies,
 }
 
 var sessionsCookiesSetCmd = &cobra.Command{
 	Use:   "cookies-set",
-	Short: "Set cookies from a JSON file",
-	Args:  cobra.NoArgs,
-	RunE:  runSessionCookiesSet,
+	Short: "Set cookies from a file",
+	Long: `Set cookies from a file: JSON as exported by 'notte sessions cookies'
+(an object with a "cookies" array, or a bare array), a Netscape cookies.txt
+file, or an EditThisCookie export. Use --file - to read from stdin.`,
+	Example: `  notte sessions cookies-set --file cookies.json
+  notte sessions cookies-set --file cookies.txt --format netscape --domain example.com`,
+	Args: cobra.NoArgs,
+	RunE: runSessionCookiesSet,
 }
 
 var sessionsDebugCmd = &cobra.Command{
@@ -188,15 +227,34 @@ var sessionsDebugCmd = &cobra.Command{
 var sessionsNetworkCmd = &cobra.Command{
 	Use:   "network",
 	Short: "Get network logs for the session",
-	Args:  cobra.NoArgs,
-	RunE:  runSessionNetwork,
+	Long: `Get the network logs of a session.
+
+Without other flags, the log files are listed as returned by the API. With
+--har, a filter or --summary, the logs are downloaded and each request is
+paired with its response. --har writes them as an HTTP Archive that browser
+devtools can import, --summary totals the traffic by host and compares it
+with the session's byte counts, and otherwise the matching requests are
+listed.`,
+	Example: `  notte sessions network --har out.har
+  notte sessions network --status 4xx,5xx --method POST
+  notte sessions network --url-match 'api\.example\.com' --min-size 100k
+  notte sessions network --summary --top 5`,
+	Args: cobra.NoArgs,
+	RunE: runSessionNetwork,
 }
 
 var sessionsReplayCmd = &cobra.Command{
 	Use:   "replay",
 	Short: "Get replay URL/data for the session",
-	Args:  cobra.NoArgs,
-	RunE:  runSessionReplay,
+	Long: `Get the session's replay.
+
+With --out, the replay is streamed to a file, with progress on stderr.
+--open opens the saved replay in the default application, saving it to a
+temporary file when --out is not set.`,
+	Example: `  notte sessions replay --out replay.webp
+  notte sessions replay --open`,
+	Args: cobra.NoArgs,
+	RunE: runSessionReplay,
 }
 
 var sessionsOffsetCmd = &cobra.Command{
@@ -209,8 +267,25 @@ var sessionsOffsetCmd = &cobra.Command{
 var sessionsWorkflowCodeCmd = &cobra.Command{
 	Use:   "workflow-code",
 	Short: "Export session steps as code",
-	Args:  cobra.NoArgs,
-	RunE:  runSessionWorkflowCode,
+	Long: `Export the session's steps as code.
+
+Without --target, the API's response is printed as is. --target picks the
+code to generate:
+
+  python-sdk      the Python script generated by the API
+  notte-actions   an action file to replay with ` + "`notte run`" + `
+  playwright-ts   a standalone Playwright script
+  go              a Go program that replays the steps in a new session
+
+notte-actions, playwright-ts and go are translated locally from the
+session's recorded steps; failed steps are left out. Playwright needs the
+element selectors recorded with each step, and marks steps it cannot
+translate with a TODO comment.`,
+	Example: `  notte sessions workflow-code --target notte-actions --out flow.yaml
+  notte sessions workflow-code --target playwright-ts --out flow.ts
+  notte sessions workflow-code --out workflow.py`,
+	Args: cobra.NoArgs,
+	RunE: runSessionWorkflowCode,
 }
 
 func init() {
@@ -230,99 +305,186 @@ func init() {
 	sessionsCmd.AddCommand(sessionsOffsetCmd)
 	sessionsCmd.AddCommand(sessionsWorkflowCodeCmd)
 
+	// List flags
+	addListFlags(sessionsListCmd)
+	addListFilterFlags(sessionsListCmd, sessionStatuses)
+
 	// Start command flags
-	sessionsStartCmd.Flags().BoolVar(&sessionsStartHeadless, "headless", true, "Run session in headless mode")
-	sessionsStartCmd.Flags().StringVar(&sessionsStartBrowser, "browser", "chromium", "Browser type (chromium, chrome, firefox)")
-	sessionsStartCmd.Flags().IntVar(&sessionsStartIdleTimeout, "idle-timeout", 0, "Idle timeout in minutes (session closes after this period of inactivity)")
-	sessionsStartCmd.Flags().IntVar(&sessionsStartMaxDuration, "max-duration", 0, "Maximum session lifetime in minutes (absolute maximum, not affected by activity)")
-	sessionsStartCmd.Flags().BoolVar(&sessionsStartProxies, "proxies", false, "Use default proxies")
-	sessionsStartCmd.Flags().BoolVar(&sessionsStartSolveCaptchas, "solve-captchas", false, "Automatically solve captchas")
-	sessionsStartCmd.Flags().IntVar(&sessionsStartViewportW, "viewport-width", 0, "Viewport width in pixels")
-	sessionsStartCmd.Flags().IntVar(&sessionsStartViewportH, "viewport-height", 0, "Viewport height in pixels")
-	sessionsStartCmd.Flags().StringVar(&sessionsStartUserAgent, "user-agent", "", "Custom user agent string")
-	sessionsStartCmd.Flags().StringVar(&sessionsStartCdpURL, "cdp-url", "", "CDP URL of remote session provider")
+	addSessionStartFlags(sessionsStartCmd)
 
 	// Status command flags
-	sessionsStatusCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsStatusCmd)
 
 	// Stop command flags
-	sessionsStopCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsStopCmd)
 
 	// Observe command flags
-	sessionsObserveCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsObserveCmd)
 	sessionsObserveCmd.Flags().StringVar(&sessionObserveURL, "url", "", "Navigate to URL before observing")
+	sessionsObserveCmd.Flags().StringVar(&sessionObserveScreenshot, "screenshot", "", "Save the page screenshot to this file (.png or .jpg)")
+	sessionsObserveCmd.Flags().BoolVar(&sessionObserveAnnotate, "annotate", false, "Draw action bounding boxes and IDs on the saved screenshot")
+	sessionsObserveCmd.Flags().StringVar(&sessionObserveInstruct, "instructions", "", "What to look for on the page (e.g. \"find the login form\")")
+	sessionsObserveCmd.Flags().IntVar(&sessionObserveMinActions, "min-actions", 0, "List at least this many actions before stopping")
+	sessionsObserveCmd.Flags().IntVar(&sessionObserveMaxActions, "max-actions", 0, "List at most this many actions (used when --min-actions is not set)")
+	sessionsObserveCmd.Flags().StringVar(&sessionObservePerception, "perception", "", "Perception mode: fast or deep")
+	sessionsObserveCmd.Flags().BoolVar(&sessionObserveMarkdown, "markdown", false, "Also show the page content as markdown (text output)")
 
 	// Execute command flags
-	sessionsExecuteCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsExecuteCmd)
 	sessionsExecuteCmd.Flags().StringVar(&sessionExecuteAction, "action", "", "Action JSON, @file, or '-' for stdin")
 
 	// Scrape command flags
-	sessionsScrapeCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsScrapeCmd)
 	sessionsScrapeCmd.Flags().StringVar(&sessionScrapeInstructions, "instructions", "", "Extraction instructions")
 	sessionsScrapeCmd.Flags().BoolVar(&sessionScrapeOnlyMain, "only-main-content", false, "Only scrape main content")
+	sessionsScrapeCmd.Flags().StringVar(&sessionScrapeSchema, "schema", "", "JSON Schema for structured output (JSON, @file, or - for stdin)")
 
 	// Cookies command flags
-	sessionsCookiesCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsCookiesCmd)
 
 	// Cookies-set command flags
-	sessionsCookiesSetCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
-	sessionsCookiesSetCmd.Flags().StringVar(&sessionCookiesSetFile, "file", "", "JSON file containing cookies array (required)")
+	addSessionIDFlags(sessionsCookiesSetCmd)
+	sessionsCookiesSetCmd.Flags().StringVar(&sessionCookiesSetFile, "file", "", "Cookies file, or - for stdin (required)")
 	_ = sessionsCookiesSetCmd.MarkFlagRequired("file")
 
 	// Debug command flags
-	sessionsDebugCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsDebugCmd)
 
 	// Network command flags
-	sessionsNetworkCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsNetworkCmd)
 
 	// Replay command flags
-	sessionsReplayCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsReplayCmd)
+	addReplayFlags(sessionsReplayCmd)
 
 	// Offset command flags
-	sessionsOffsetCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsOffsetCmd)
 
 	// Workflow-code command flags
-	sessionsWorkflowCodeCmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	addSessionIDFlags(sessionsWorkflowCodeCmd)
+	addWorkflowCodeFlags(sessionsWorkflowCodeCmd)
+}
+
+// sessionTable lists the columns available to `sessions list`
+var sessionTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "session_id"},
+		{Name: "status", Header: "STATUS", Path: "status"},
+		{Name: "browser", Header: "BROWSER", Path: "browser_type"},
+		{Name: "created_at", Header: "CREATED", Path: "created_at"},
+		{Name: "last_accessed_at", Header: "LAST ACCESSED", Path: "last_accessed_at"},
+		{Name: "closed_at", Header: "CLOSED", Path: "closed_at"},
+		{Name: "headless", Header: "HEADLESS", Path: "headless"},
+		{Name: "proxies", Header: "PROXIES", Path: "proxies"},
+		{Name: "credit_usage", Header: "CREDITS", Path: "credit_usage"},
+	},
+	Defaults: []string{"id", "status", "browser", "created_at"},
 }
 
+// sessionStatuses are the statuses accepted by `sessions list --status`
+var sessionStatuses = []string{"active", "closed", "error", "timed_out"}
+
 func runSessionsList(cmd *cobra.Command, args []string) error {
+	filter, err := newListFilter(sessionStatuses, time.Now())
+	if err != nil {
+		return err
+	}
+
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
+	fetch := func(ctx context.Context, page pageRequest) ([]api.SessionResponse, bool, error) {
+		params := &api.ListSessionsParams{Page: page.Page, PageSize: page.PageSize}
+		if filter.onlyActive() {
+			active := true
+			params.OnlyActive = &active
+		}
+		if listCurrentToken {
+			params.OnlyCurrentToken = &listCurrentToken
+		}
+		resp, err := client.Client().ListSessionsWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
 
-	params := &api.ListSessionsParams{}
-	resp, err := client.Client().ListSessionsWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
+
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
 	}
 
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
+	var keep itemFilter[api.SessionResponse]
+	if filter.clientSide() {
+		keep = func(s api.SessionResponse) (bool, error) {
+			browser := ""
+			if s.BrowserType != nil {
+				browser = string(*s.BrowserType)
+			}
+			return filter.match(string(s.Status), s.CreatedAt) && filter.matchBrowser(browser), nil
+		}
 	}
 
-	formatter := GetFormatter()
+	return runFilteredList(cmd, sessionTable, "No active sessions.", fetch, keep)
+}
 
-	var items []api.SessionResponse
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
+func runSessionsStart(cmd *cobra.Command, args []string) error {
+	if sessionsStartName != "" {
+		if err := config.ValidateSlotName(sessionsStartName); err != nil {
+			return err
+		}
 	}
-	if printed, err := PrintListOrEmpty(items, "No active sessions."); err != nil {
+
+	session, err := startSession(cmd)
+	if err != nil {
 		return err
-	} else if printed {
-		return nil
 	}
 
-	return formatter.Print(items)
+	// Save session ID as current session, under its name if given
+	if session != nil {
+		if err := useSession(sessionsStartName, session.SessionId); err != nil {
+			PrintInfo(fmt.Sprintf("Warning: could not save current session: %v", err))
+		}
+	}
+
+	formatter := GetFormatter()
+	return formatter.Print(session)
+}
+
+// addSessionStartFlags registers the session options shared by commands
+// that start a session
+func addSessionStartFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&sessionsStartHeadless, "headless", true, "Run session in headless mode")
+	cmd.Flags().StringVar(&sessionsStartBrowser, "browser", "chromium", "Browser type (chromium, chrome, firefox)")
+	cmd.Flags().IntVar(&sessionsStartIdleTimeout, "idle-timeout", 0, "Idle timeout in minutes (session closes after this period of inactivity)")
+	cmd.Flags().IntVar(&sessionsStartMaxDuration, "max-duration", 0, "Maximum session lifetime in minutes (absolute maximum, not affected by activity)")
+	cmd.Flags().BoolVar(&sessionsStartProxies, "proxies", false, "Use default proxies")
+	cmd.Flags().BoolVar(&sessionsStartSolveCaptchas, "solve-captchas", false, "Automatically solve captchas")
+	cmd.Flags().IntVar(&sessionsStartViewportW, "viewport-width", 0, "Viewport width in pixels")
+	cmd.Flags().IntVar(&sessionsStartViewportH, "viewport-height", 0, "Viewport height in pixels")
+	cmd.Flags().StringVar(&sessionsStartUserAgent, "user-agent", "", "Custom user agent string")
+	cmd.Flags().StringVar(&sessionsStartCdpURL, "cdp-url", "", "CDP URL of remote session provider")
 }
 
-func runSessionsStart(cmd *cobra.Command, args []string) error {
+// startSession starts a session from the start flags registered on cmd,
+// filling unset flags from the configured session defaults
+func startSession(cmd *cobra.Command) (*api.SessionResponse, error) {
 	client, err := GetClient()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	cfg, name, _, err := activeContext()
+	if err != nil {
+		return nil, err
+	}
+	if err := applySessionDefaults(cmd, cfg.SessionDefaultsFor(name)); err != nil {
+		return nil, err
 	}
 
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
@@ -356,7 +518,7 @@ func runSessionsStart(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("proxies") {
 		var proxies api.ApiSessionStartRequest_Proxies
 		if err := proxies.FromApiSessionStartRequestProxies1(sessionsStartProxies); err != nil {
-			return fmt.Errorf("failed to set proxies: %w", err)
+			return nil, fmt.Errorf("failed to set proxies: %w", err)
 		}
 		body.Proxies = &proxies
 	}
@@ -387,22 +549,14 @@ func runSessionsStart(cmd *cobra.Command, args []string) error {
 	params := &api.SessionStartParams{}
 	resp, err := client.Client().SessionStartWithResponse(ctx, params, body)
 	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
 
 	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
-
-	// Save session ID as current session
-	if resp.JSON200 != nil {
-		if err := setCurrentSession(resp.JSON200.SessionId); err != nil {
-			PrintInfo(fmt.Sprintf("Warning: could not save current session: %v", err))
-		}
+		return nil, err
 	}
 
-	formatter := GetFormatter()
-	return formatter.Print(resp.JSON200)
+	return resp.JSON200, nil
 }
 
 func runSessionStatus(cmd *cobra.Command, args []string) error {
@@ -461,14 +615,7 @@ func runSessionStop(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Clear current session only if it matches the stopped session
-	configDir, _ := config.Dir()
-	if configDir != "" {
-		data, _ := os.ReadFile(filepath.Join(configDir, config.CurrentSessionFile))
-		if strings.TrimSpace(string(data)) == sessionID {
-			_ = clearCurrentSession()
-		}
-	}
+	forgetSession(sessionID)
 
 	return PrintResult(fmt.Sprintf("Session %s stopped.", sessionID), map[string]any{
 		"id":     sessionID,
@@ -477,34 +624,93 @@ func runSessionStop(cmd *cobra.Command, args []string) error {
 }
 
 func runSessionObserve(cmd *cobra.Command, args []string) error {
+	if sessionObserveAnnotate && sessionObserveScreenshot == "" {
+		return errors.New("--annotate requires --screenshot")
+	}
+	body, err := buildObserveRequest(cmd)
+	if err != nil {
+		return err
+	}
 	if err := requireSessionID(); err != nil {
 		return err
 	}
 
-	client, err := GetClient()
+	obs, err := observePage(cmd, body)
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
+	if sessionObserveScreenshot != "" && obs != nil {
+		if err := screenshot.Save(sessionObserveScreenshot, obs.Screenshot, sessionObserveAnnotate); err != nil {
+			return err
+		}
+		PrintInfo(fmt.Sprintf("Screenshot saved to %s", sessionObserveScreenshot))
+		// The image is on disk; don't dump it as base64 too
+		obs.Screenshot.Raw = openapi_types.File{}
+	}
+
+	if IsStructuredOutput() || obs == nil {
+		return GetFormatter().Print(obs)
+	}
+	return printObservation(os.Stdout, obs, sessionObserveMarkdown)
+}
 
+// buildObserveRequest builds the observe request from the observe flags
+func buildObserveRequest(cmd *cobra.Command) (api.PageObserveJSONRequestBody, error) {
 	body := api.PageObserveJSONRequestBody{}
 	if sessionObserveURL != "" {
 		body.Url = &sessionObserveURL
 	}
+	if sessionObserveInstruct != "" {
+		body.Instructions = &sessionObserveInstruct
+	}
+	if cmd.Flags().Changed("min-actions") {
+		if sessionObserveMinActions < 1 {
+			return body, fmt.Errorf("--min-actions must be at least 1, got %d", sessionObserveMinActions)
+		}
+		body.MinNbActions = &sessionObserveMinActions
+	}
+	if cmd.Flags().Changed("max-actions") {
+		if sessionObserveMaxActions < 1 {
+			return body, fmt.Errorf("--max-actions must be at least 1, got %d", sessionObserveMaxActions)
+		}
+		body.MaxNbActions = &sessionObserveMaxActions
+	}
+	if body.MinNbActions != nil && body.MaxNbActions != nil && *body.MinNbActions > *body.MaxNbActions {
+		return body, fmt.Errorf("--min-actions (%d) cannot exceed --max-actions (%d)", *body.MinNbActions, *body.MaxNbActions)
+	}
+	if sessionObservePerception != "" {
+		switch sessionObservePerception {
+		case "fast", "deep":
+		default:
+			return body, fmt.Errorf("invalid perception: expected fast|deep, got %q", sessionObservePerception)
+		}
+		body.PerceptionType = &sessionObservePerception
+	}
+	return body, nil
+}
+
+// observePage observes the current session's page
+func observePage(cmd *cobra.Command, body api.PageObserveJSONRequestBody) (*api.ObserveResponse, error) {
+	client, err := GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
 
 	params := &api.PageObserveParams{}
 	resp, err := client.Client().PageObserveWithResponse(ctx, sessionID, params, body)
 	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
 
 	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
+		return nil, err
 	}
 
-	return GetFormatter().Print(resp.JSON200)
+	return resp.JSON200, nil
 }
 
 func runSessionExecute(cmd *cobra.Command, args []string) error {
@@ -531,6 +737,17 @@ func runSessionExecute(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid action JSON: %w", err)
 	}
 
+	// Resolve {{ env.X }} and {{ vault ... }} expressions
+	renderer := newTemplateRenderer(cmd.Context(), client)
+	actionData, err = renderer.renderJSON(actionData)
+	if err != nil {
+		return err
+	}
+
+	if IsVerbose() {
+		_, _ = fmt.Fprintf(os.Stderr, "-> %s\n", renderer.redact(string(actionData)))
+	}
+
 	params := &api.PageExecuteParams{}
 	resp, err := client.Client().PageExecuteWithBodyWithResponse(ctx, sessionID, params, "application/json", bytes.NewReader(actionData))
 	if err != nil {
@@ -541,7 +758,7 @@ func runSessionExecute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return GetFormatter().Print(resp.JSON200)
+	return GetFormatter().Print(renderer.redactAny(resp.JSON200))
 }
 
 func runSessionScrape(cmd *cobra.Command, args []string) error {
@@ -549,6 +766,11 @@ func runSessionScrape(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	schema, err := loadSchema(cmd, sessionScrapeSchema)
+	if err != nil {
+		return err
+	}
+
 	client, err := GetClient()
 	if err != nil {
 		return err
@@ -564,6 +786,9 @@ func runSessionScrape(cmd *cobra.Command, args []string) error {
 	if sessionScrapeOnlyMain {
 		body.OnlyMainContent = &sessionScrapeOnlyMain
 	}
+	if schema != nil {
+		body.ResponseFormat = schema.Raw()
+	}
 
 	params := &api.PageScrapeParams{}
 	resp, err := client.Client().PageScrapeWithResponse(ctx, sessionID, params, body)
@@ -575,10 +800,16 @@ func runSessionScrape(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return GetFormatter().Print(resp.JSON200)
+	if err := GetFormatter().Print(resp.JSON200); err != nil {
+		return err
+	}
+	if resp.JSON200 != nil {
+		return checkScrapeSchema(schema, resp.JSON200.Structured)
+	}
+	return nil
 }
 
-func runSessionCookies(cmd *cobra.Command, args []string) error {
+func runSessionDebug(cmd *cobra.Command, args []string) error {
 	if err := requireSessionID(); err != nil {
 		return err
 	}
@@ -591,8 +822,8 @@ func runSessionCookies(cmd *cobra.Command, args []string) error {
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
 	defer cancel()
 
-	params := &api.SessionCookiesGetParams{}
-	resp, err := client.Client().SessionCookiesGetWithResponse(ctx, sessionID, params)
+	params := &api.SessionDebugInfoParams{}
+	resp, err := client.Client().SessionDebugInfoWithResponse(ctx, sessionID, params)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -604,7 +835,7 @@ func runSessionCookies(cmd *cobra.Command, args []string) error {
 	return GetFormatter().Print(resp.JSON200)
 }
 
-func runSessionCookiesSet(cmd *cobra.Command, args []string) error {
+func runSessionReplay(cmd *cobra.Command, args []string) error {
 	if err := requireSessionID(); err != nil {
 		return err
 	}
@@ -617,20 +848,11 @@ func runSessionCookiesSet(cmd *cobra.Command, args []string) error {
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
 	defer cancel()
 
-	// Read cookies from JSON file
-	fileData, err := os.ReadFile(sessionCookiesSetFile)
-	if err != nil {
-		return fmt.Errorf("failed to read cookies file: %w", err)
-	}
-
-	// Parse the cookies JSON
-	var body api.SessionCookiesSetJSONRequestBody
-	if err := json.Unmarshal(fileData, &body); err != nil {
-		return fmt.Errorf("failed to parse cookies JSON: %w", err)
+	params := &api.SessionReplayParams{}
+	if savingReplay() {
+		return saveSessionReplay(ctx, client, params)
 	}
-
-	params := &api.SessionCookiesSetParams{}
-	resp, err := client.Client().SessionCookiesSetWithResponse(ctx, sessionID, params, body)
+	resp, err := client.Client().SessionReplayWithResponse(ctx, sessionID, params)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -639,36 +861,34 @@ func runSessionCookiesSet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	return GetFormatter().Print(resp.JSON200)
+	// Wrap raw body for formatter compatibility
+	result := map[string]interface{}{
+		"session_id":  sessionID,
+		"replay_data": string(resp.Body),
+	}
+	return GetFormatter().Print(result)
 }
 
-func runSessionDebug(cmd *cobra.Command, args []string) error {
-	if err := requireSessionID(); err != nil {
-		return err
+// saveSessionReplay streams the session's replay to disk
+func saveSessionReplay(ctx context.Context, client *api.NotteClient, params *api.SessionReplayParams) error {
+	resp, err := client.Client().SessionReplay(ctx, sessionID, params)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
 	}
+	defer func() { _ = resp.Body.Close() }()
 
-	client, err := GetClient()
-	if err != nil {
+	if err := HandleAPIResponse(resp); err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.SessionDebugInfoParams{}
-	resp, err := client.Client().SessionDebugInfoWithResponse(ctx, sessionID, params)
+	path, size, err := downloadReplay(resp, replayOut, sessionID, nil)
 	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
 		return err
 	}
-
-	return GetFormatter().Print(resp.JSON200)
+	return finishReplay(path, size, map[string]any{"session_id": sessionID})
 }
 
-func runSessionNetwork(cmd *cobra.Command, args []string) error {
+func runSessionOffset(cmd *cobra.Command, args []string) error {
 	if err := requireSessionID(); err != nil {
 		return err
 	}
@@ -681,8 +901,8 @@ func runSessionNetwork(cmd *cobra.Command, args []string) error {
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
 	defer cancel()
 
-	params := &api.SessionNetworkLogsParams{}
-	resp, err := client.Client().SessionNetworkLogsWithResponse(ctx, sessionID, params)
+	params := &api.SessionOffsetParams{}
+	resp, err := client.Client().SessionOffsetWithResponse(ctx, sessionID, params)
 	if err != nil {
 		return fmt.Errorf("API request failed: %w", err)
 	}
@@ -694,7 +914,7 @@ func runSessionNetwork(cmd *cobra.Command, args []string) error {
 	return GetFormatter().Print(resp.JSON200)
 }
 
-func runSessionReplay(cmd *cobra.Command, args []string) error {
+func runSessionWorkflowCode(cmd *cobra.Command, args []string) error {
 	if err := requireSessionID(); err != nil {
 		return err
 	}
@@ -704,77 +924,111 @@ func runSessionReplay(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if workflowCodeRequested() {
+		return runWorkflowCodeTarget(cmd, workflowSource{
+			Kind: "session",
+			ID:   sessionID,
+			Script: func(ctx context.Context) (*api.AgentFunctionCodeResponse, error) {
+				return fetchSessionScript(ctx, client)
+			},
+			Steps: func(ctx context.Context) ([]map[string]any, error) { return fetchSessionSteps(ctx, client) },
+		})
+	}
+
 	ctx, cancel := GetContextWithTimeout(cmd.Context())
 	defer cancel()
 
-	params := &api.SessionReplayParams{}
-	resp, err := client.Client().SessionReplayWithResponse(ctx, sessionID, params)
+	script, err := fetchSessionScript(ctx, client)
 	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
 		return err
 	}
-
-	// Wrap raw body for formatter compatibility
-	result := map[string]interface{}{
-		"session_id":  sessionID,
-		"replay_data": string(resp.Body),
-	}
-	return GetFormatter().Print(result)
+	return GetFormatter().Print(script)
 }
 
-func runSessionOffset(cmd *cobra.Command, args []string) error {
-	if err := requireSessionID(); err != nil {
-		return err
+// fetchSessionScript fetches the session's code as a standalone workflow
+func fetchSessionScript(ctx context.Context, client *api.NotteClient) (*api.AgentFunctionCodeResponse, error) {
+	params := &api.GetSessionScriptParams{
+		AsWorkflow: true,
 	}
-
-	client, err := GetClient()
+	resp, err := client.Client().GetSessionScriptWithResponse(ctx, sessionID, params)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, errors.New("empty response from API")
+	}
+	return resp.JSON200, nil
+}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.SessionOffsetParams{}
-	resp, err := client.Client().SessionOffsetWithResponse(ctx, sessionID, params)
+// fetchSessionSteps fetches the steps recorded for the session
+func fetchSessionSteps(ctx context.Context, client *api.NotteClient) ([]map[string]any, error) {
+	resp, err := client.Client().SessionStatusWithResponse(ctx, sessionID, &api.SessionStatusParams{})
 	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+		return nil, fmt.Errorf("API request failed: %w", err)
 	}
-
 	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
+		return nil, err
 	}
-
-	return GetFormatter().Print(resp.JSON200)
+	if resp.JSON200 == nil {
+		return nil, errors.New("empty response from API")
+	}
+	if resp.JSON200.Steps == nil {
+		return nil, nil
+	}
+	return *resp.JSON200.Steps, nil
 }
 
-func runSessionWorkflowCode(cmd *cobra.Command, args []string) error {
-	if err := requireSessionID(); err != nil {
-		return err
+// applySessionDefaults fills in `sessions start` flags that were not given on
+// the command line from configured defaults. Setting them through the flag
+// set keeps the Changed checks in runSessionsStart meaningful.
+func applySessionDefaults(cmd *cobra.Command, defaults *config.SessionDefaults) error {
+	if defaults == nil {
+		return nil
 	}
 
-	client, err := GetClient()
-	if err != nil {
-		return err
+	values := map[string]string{}
+	if defaults.Headless != nil {
+		values["headless"] = strconv.FormatBool(*defaults.Headless)
 	}
-
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.GetSessionScriptParams{
-		AsWorkflow: true,
+	if defaults.Browser != "" {
+		values["browser"] = defaults.Browser
 	}
-	resp, err := client.Client().GetSessionScriptWithResponse(ctx, sessionID, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
+	if defaults.IdleTimeout > 0 {
+		values["idle-timeout"] = strconv.Itoa(defaults.IdleTimeout)
 	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
+	if defaults.MaxDuration > 0 {
+		values["max-duration"] = strconv.Itoa(defaults.MaxDuration)
+	}
+	if defaults.Proxies != nil {
+		values["proxies"] = strconv.FormatBool(*defaults.Proxies)
+	}
+	if defaults.SolveCaptchas != nil {
+		values["solve-captchas"] = strconv.FormatBool(*defaults.SolveCaptchas)
+	}
+	if defaults.ViewportWidth > 0 {
+		values["viewport-width"] = strconv.Itoa(defaults.ViewportWidth)
+	}
+	if defaults.ViewportHeight > 0 {
+		values["viewport-height"] = strconv.Itoa(defaults.ViewportHeight)
+	}
+	if defaults.UserAgent != "" {
+		values["user-agent"] = defaults.UserAgent
+	}
+	if defaults.CdpURL != "" {
+		values["cdp-url"] = defaults.CdpURL
 	}
 
-	return GetFormatter().Print(resp.JSON200)
+	flags := cmd.Flags()
+	for name, value := range values {
+		if flags.Lookup(name) == nil || flags.Changed(name) {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("invalid session default %s=%q: %w", name, value, err)
+		}
+	}
+	return nil
 }
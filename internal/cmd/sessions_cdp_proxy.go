@@ -0,0 +1,385 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+var (
+	cdpProxyHost string
+	cdpProxyPort int
+)
+
+var sessionsCdpProxyCmd = &cobra.Command{
+	Use:   "cdp-proxy",
+	Short: "Serve a session's Chrome DevTools endpoint on a local port",
+	Long: `Serve a session's Chrome DevTools Protocol endpoint on a local port, so that
+Playwright, Puppeteer and other CDP tools can connect without Notte
+credentials.
+
+The proxy answers /json/version and /json/list like a local Chrome, and
+forwards WebSocket connections to the session with the API key added. It
+runs until interrupted.
+
+Only requests for the proxy's own address or localhost are served, and
+requests from web pages are refused unless the page is served from this
+machine, so that sites open in a local browser cannot use the session.`,
+	Example: `  notte sessions cdp-proxy --port 9222
+  # Playwright: chromium.connectOverCDP("http://127.0.0.1:9222")
+  # Puppeteer:  puppeteer.connect({ browserURL: "http://127.0.0.1:9222" })`,
+	Args: cobra.NoArgs,
+	RunE: runSessionsCdpProxy,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsCdpProxyCmd)
+
+	addSessionIDFlags(sessionsCdpProxyCmd)
+	sessionsCdpProxyCmd.Flags().StringVar(&cdpProxyHost, "host", "127.0.0.1", "Address to listen on")
+	sessionsCdpProxyCmd.Flags().IntVar(&cdpProxyPort, "port", 9222, "Port to listen on (0 picks a free port)")
+}
+
+// cdpProxy serves the DevTools HTTP endpoints for one session and proxies
+// its WebSocket connections
+type cdpProxy struct {
+	sessionID string
+	browser   string
+	apiKey    string
+	// addr is the address the proxy listens on, accepted as a Host besides
+	// the loopback names
+	addr string
+	// upstream is the session's browser endpoint, with an http(s) scheme
+	upstream *url.URL
+	// tabs lists the session's open tabs
+	tabs func(ctx context.Context) ([]api.TabSessionDebugResponse, error)
+
+	mu    sync.Mutex
+	pages map[string]*url.URL
+}
+
+// cdpTarget is one entry of /json/list
+type cdpTarget struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	Title                string `json:"title"`
+	URL                  string `json:"url"`
+	WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+}
+
+func (p *cdpProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The proxy adds the API key to what it forwards, so web pages open in a
+	// local browser must not reach it: cross-origin WebSockets are not
+	// subject to CORS, and DNS rebinding shows up as a foreign Host
+	if origin := r.Header.Get("Origin"); origin != "" && !isLoopbackOrigin(origin) {
+		http.Error(w, "forbidden origin", http.StatusForbidden)
+		return
+	}
+	if !p.allowedHost(r.Host) {
+		http.Error(w, "forbidden host", http.StatusForbidden)
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/json/version":
+		p.serveVersion(w, r)
+	case path == "/json" || path == "/json/list":
+		p.serveList(w, r)
+	case strings.HasPrefix(path, "/devtools/page/"):
+		target, err := p.page(r.Context(), strings.TrimPrefix(path, "/devtools/page/"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		p.proxy(w, r, target)
+	case isWebSocketUpgrade(r):
+		p.proxy(w, r, p.upstream)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (p *cdpProxy) serveVersion(w http.ResponseWriter, r *http.Request) {
+	writeCDPJSON(w, map[string]string{
+		"Browser":              "Notte/" + p.browser,
+		"Protocol-Version":     "1.3",
+		"webSocketDebuggerUrl": "ws://" + r.Host + "/devtools/browser/" + p.sessionID,
+	})
+}
+
+func (p *cdpProxy) serveList(w http.ResponseWriter, r *http.Request) {
+	tabs, err := p.refreshPages(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	targets := make([]cdpTarget, 0, len(tabs))
+	for _, tab := range tabs {
+		id := strconv.Itoa(tab.Metadata.TabId)
+		targets = append(targets, cdpTarget{
+			ID:                   id,
+			Type:                 "page",
+			Title:                tab.Metadata.Title,
+			URL:                  tab.Metadata.Url,
+			WebSocketDebuggerURL: "ws://" + r.Host + "/devtools/page/" + id,
+		})
+	}
+	writeCDPJSON(w, targets)
+}
+
+// refreshPages fetches the open tabs and remembers their endpoints
+func (p *cdpProxy) refreshPages(ctx context.Context) ([]api.TabSessionDebugResponse, error) {
+	tabs, err := p.tabs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[string]*url.URL, len(tabs))
+	for _, tab := range tabs {
+		u, err := httpURL(tab.WsUrl)
+		if err != nil {
+			return nil, err
+		}
+		pages[strconv.Itoa(tab.Metadata.TabId)] = u
+	}
+
+	p.mu.Lock()
+	p.pages = pages
+	p.mu.Unlock()
+	return tabs, nil
+}
+
+// page returns the endpoint of a tab, refreshing the tabs if it is unknown
+func (p *cdpProxy) page(ctx context.Context, id string) (*url.URL, error) {
+	p.mu.Lock()
+	u, ok := p.pages[id]
+	p.mu.Unlock()
+	if ok {
+		return u, nil
+	}
+
+	if _, err := p.refreshPages(ctx); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if u, ok := p.pages[id]; ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("no page %q in session %s", id, p.sessionID)
+}
+
+// proxy forwards a request, including WebSocket upgrades, to target with
+// the API key added
+func (p *cdpProxy) proxy(w http.ResponseWriter, r *http.Request, target *url.URL) {
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			u := *target
+			pr.Out.URL = &u
+			pr.Out.Host = u.Host
+			pr.Out.Header.Set("Authorization", "Bearer "+p.apiKey)
+			// ServeHTTP only lets loopback origins through, and the remote
+			// browser rejects DevTools connections from unexpected origins
+			pr.Out.Header.Del("Origin")
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			_, _ = fmt.Fprintf(os.Stderr, "CDP proxy: %v\n", err)
+			w.WriteHeader(http.StatusBadGateway)
+		},
+	}
+	rp.ServeHTTP(w, r)
+}
+
+// allowedHost reports whether a request's Host names the proxy itself
+func (p *cdpProxy) allowedHost(host string) bool {
+	if host == p.addr {
+		return true
+	}
+	name := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		name = h
+	}
+	return isLoopbackHost(strings.Trim(name, "[]"))
+}
+
+// isLoopbackOrigin reports whether an Origin header belongs to a page served
+// from this machine
+func isLoopbackOrigin(origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return isLoopbackHost(u.Hostname())
+}
+
+func isLoopbackHost(name string) bool {
+	if strings.EqualFold(name, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(name)
+	return ip != nil && ip.IsLoopback()
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// httpURL converts a ws:// or wss:// URL to http:// or https:// for the
+// HTTP transport, which performs the upgrade itself
+func httpURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CDP URL %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "ws":
+		u.Scheme = "http"
+	case "wss":
+		u.Scheme = "https"
+	case "http", "https":
+	default:
+		return nil, fmt.Errorf("invalid CDP URL %q: unsupported scheme", raw)
+	}
+	return u, nil
+}
+
+func writeCDPJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+// sessionCDPURL returns a session's CDP endpoint, from the session itself or
+// from its debug info
+func sessionCDPURL(ctx context.Context, client *api.NotteClient, id string) (string, string, error) {
+	ctx, cancel := GetContextWithTimeout(ctx)
+	defer cancel()
+
+	resp, err := client.Client().SessionStatusWithResponse(ctx, id, &api.SessionStatusParams{})
+	if err != nil {
+		return "", "", fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return "", "", err
+	}
+	if resp.JSON200 == nil {
+		return "", "", errors.New("empty response from API")
+	}
+
+	browser := "chromium"
+	if resp.JSON200.BrowserType != nil {
+		browser = string(*resp.JSON200.BrowserType)
+	}
+	if resp.JSON200.CdpUrl != nil && *resp.JSON200.CdpUrl != "" {
+		return *resp.JSON200.CdpUrl, browser, nil
+	}
+
+	debug, err := client.Client().SessionDebugInfoWithResponse(ctx, id, &api.SessionDebugInfoParams{})
+	if err != nil {
+		return "", "", fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(debug.HTTPResponse); err != nil {
+		return "", "", err
+	}
+	if debug.JSON200 == nil || debug.JSON200.Ws.Cdp == "" {
+		return "", "", fmt.Errorf("session %s has no CDP URL", id)
+	}
+	return debug.JSON200.Ws.Cdp, browser, nil
+}
+
+func runSessionsCdpProxy(cmd *cobra.Command, args []string) error {
+	if err := requireSessionID(); err != nil {
+		return err
+	}
+	id := sessionID
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	cdpURL, browser, err := sessionCDPURL(cmd.Context(), client, id)
+	if err != nil {
+		return err
+	}
+	upstream, err := httpURL(cdpURL)
+	if err != nil {
+		return err
+	}
+
+	proxy := &cdpProxy{
+		sessionID: id,
+		browser:   browser,
+		apiKey:    client.APIKey(),
+		upstream:  upstream,
+		tabs: func(ctx context.Context) ([]api.TabSessionDebugResponse, error) {
+			ctx, cancel := GetContextWithTimeout(ctx)
+			defer cancel()
+
+			resp, err := client.Client().SessionDebugInfoWithResponse(ctx, id, &api.SessionDebugInfoParams{})
+			if err != nil {
+				return nil, fmt.Errorf("API request failed: %w", err)
+			}
+			if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+				return nil, err
+			}
+			if resp.JSON200 == nil {
+				return nil, nil
+			}
+			return resp.JSON200.Tabs, nil
+		},
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(cdpProxyHost, strconv.Itoa(cdpProxyPort)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	addr := listener.Addr().String()
+	proxy.addr = addr
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Handler: proxy, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := PrintResult(
+		fmt.Sprintf("Proxying session %s on http://%s (WebSocket: ws://%s/devtools/browser/%s). Press Ctrl-C to stop.", id, addr, addr, id),
+		map[string]any{
+			"session_id": id,
+			"http_url":   "http://" + addr,
+			"ws_url":     "ws://" + addr + "/devtools/browser/" + id,
+		},
+	); err != nil {
+		return err
+	}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+// newCDPUpstream starts a server that accepts WebSocket upgrades carrying the
+// API key and echoes everything sent after the handshake
+func newCDPUpstream(t *testing.T, paths chan<- string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		paths <- r.URL.Path
+
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		_ = rw.Flush()
+		_, _ = io.Copy(conn, rw)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestCDPProxy(t *testing.T, upstream string) *httptest.Server {
+	t.Helper()
+	browserURL, err := httpURL(strings.Replace(upstream, "http://", "ws://", 1) + "/devtools/browser/abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &cdpProxy{
+		sessionID: "sess_cdp",
+		browser:   "chromium",
+		apiKey:    "test-key",
+		upstream:  browserURL,
+		tabs: func(ctx context.Context) ([]api.TabSessionDebugResponse, error) {
+			tab := api.TabSessionDebugResponse{
+				WsUrl: strings.Replace(upstream, "http://", "ws://", 1) + "/devtools/page/XYZ",
+			}
+			tab.Metadata.TabId = 0
+			tab.Metadata.Title = "Example"
+			tab.Metadata.Url = "https://example.com"
+			return []api.TabSessionDebugResponse{tab}, nil
+		},
+	}
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func getCDPJSON(t *testing.T, u string, v any) {
+	t.Helper()
+	resp, err := http.Get(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", u, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCDPProxy_JSONEndpoints(t *testing.T) {
+	upstream := newCDPUpstream(t, make(chan string, 1))
+	proxy := newTestCDPProxy(t, upstream.URL)
+	host := strings.TrimPrefix(proxy.URL, "http://")
+
+	var version map[string]string
+	getCDPJSON(t, proxy.URL+"/json/version", &version)
+	if version["webSocketDebuggerUrl"] != "ws://"+host+"/devtools/browser/sess_cdp" {
+		t.Errorf("unexpected webSocketDebuggerUrl: %q", version["webSocketDebuggerUrl"])
+	}
+	if version["Protocol-Version"] == "" || version["Browser"] == "" {
+		t.Errorf("expected Browser and Protocol-Version, got %v", version)
+	}
+
+	var targets []cdpTarget
+	getCDPJSON(t, proxy.URL+"/json/list", &targets)
+	if len(targets) != 1 {
+		t.Fatalf("expected 1 target, got %d", len(targets))
+	}
+	want := cdpTarget{
+		ID:                   "0",
+		Type:                 "page",
+		Title:                "Example",
+		URL:                  "https://example.com",
+		WebSocketDebuggerURL: "ws://" + host + "/devtools/page/0",
+	}
+	if targets[0] != want {
+		t.Errorf("expected %+v, got %+v", want, targets[0])
+	}
+
+	resp, err := http.Get(proxy.URL + "/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown path, got %d", resp.StatusCode)
+	}
+}
+
+// cdpHandshake sends a WebSocket handshake for path to the proxy, with extra
+// header lines, and returns the connection and the proxy's response
+func cdpHandshake(t *testing.T, proxyURL, path, extra string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	if !strings.Contains(extra, "Host: ") {
+		extra = "Host: " + u.Host + "\r\n" + extra
+	}
+	req := "GET " + path + " HTTP/1.1\r\n" + extra +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := conn.Write([]byte(req)); err != nil {
+		t.Fatal(err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, br, resp
+}
+
+// dialCDP performs a WebSocket handshake against the proxy and returns the
+// connection once the upgrade succeeds
+func dialCDP(t *testing.T, proxyURL, path string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, br, resp := cdpHandshake(t, proxyURL, path, "")
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected 101, got %d", resp.StatusCode)
+	}
+	return conn, br
+}
+
+func TestCDPProxy_WebSocket(t *testing.T) {
+	paths := make(chan string, 2)
+	upstream := newCDPUpstream(t, paths)
+	proxy := newTestCDPProxy(t, upstream.URL)
+
+	tests := []struct {
+		path     string
+		upstream string
+	}{
+		{"/devtools/browser/sess_cdp", "/devtools/browser/abc"},
+		{"/devtools/page/0", "/devtools/page/XYZ"},
+	}
+	for _, tt := range tests {
+		conn, br := dialCDP(t, proxy.URL, tt.path)
+		if got := <-paths; got != tt.upstream {
+			t.Errorf("%s: expected upstream path %s, got %s", tt.path, tt.upstream, got)
+		}
+
+		if _, err := conn.Write([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != "ping" {
+			t.Errorf("%s: expected echo, got %q", tt.path, buf)
+		}
+	}
+}
+
+func TestCDPProxy_RejectsForeignOrigin(t *testing.T) {
+	paths := make(chan string, 1)
+	upstream := newCDPUpstream(t, paths)
+	proxy := newTestCDPProxy(t, upstream.URL)
+
+	_, _, resp := cdpHandshake(t, proxy.URL, "/devtools/browser/sess_cdp", "Origin: https://evil.example\r\n")
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.StatusCode)
+	}
+	select {
+	case p := <-paths:
+		t.Errorf("expected no upstream connection, got %s", p)
+	default:
+	}
+
+	// Tools served from this machine may still connect
+	_, _, resp = cdpHandshake(t, proxy.URL, "/devtools/browser/sess_cdp", "Origin: http://localhost:3000\r\n")
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Errorf("expected 101 for a loopback origin, got %d", resp.StatusCode)
+	}
+}
+
+func TestCDPProxy_RejectsForeignHost(t *testing.T) {
+	upstream := newCDPUpstream(t, make(chan string, 1))
+	proxy := newTestCDPProxy(t, upstream.URL)
+
+	req, err := http.NewRequest(http.MethodGet, proxy.URL+"/json/version", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Host = "rebind.evil.example:9222"
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for a foreign Host, got %d", resp.StatusCode)
+	}
+
+	_, _, hresp := cdpHandshake(t, proxy.URL, "/devtools/browser/sess_cdp", "Host: rebind.evil.example\r\n")
+	if hresp.StatusCode != http.StatusForbidden {
+		t.Errorf("expected 403 for an upgrade with a foreign Host, got %d", hresp.StatusCode)
+	}
+}
+
+func TestCDPProxy_AllowedHost(t *testing.T) {
+	p := &cdpProxy{addr: "0.0.0.0:9222"}
+	for host, want := range map[string]bool{
+		"127.0.0.1:9222":   true,
+		"localhost:9222":   true,
+		"[::1]:9222":       true,
+		"0.0.0.0:9222":     true,
+		"localhost":        true,
+		"evil.example":     false,
+		"192.168.1.5:9222": false,
+	} {
+		if got := p.allowedHost(host); got != want {
+			t.Errorf("allowedHost(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestCDPProxy_UnknownPage(t *testing.T) {
+	upstream := newCDPUpstream(t, make(chan string, 1))
+	proxy := newTestCDPProxy(t, upstream.URL)
+
+	resp, err := http.Get(proxy.URL + "/devtools/page/42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPURL(t *testing.T) {
+	tests := map[string]string{
+		"ws://host/a":    "http://host/a",
+		"wss://host/a?b": "https://host/a?b",
+		"https://host/a": "https://host/a",
+	}
+	for in, want := range tests {
+		got, err := httpURL(in)
+		if err != nil || got.String() != want {
+			t.Errorf("httpURL(%q) = %v, %v; want %s", in, got, err, want)
+		}
+	}
+	if _, err := httpURL("ftp://host"); err == nil {
+		t.Error("expected error for unsupported scheme")
+	}
+}
+
+func TestSessionCDPURL(t *testing.T) {
+	server, _ := setupSessionSlotsTest(t)
+	server.AddResponse("/sessions/sess_a", 200, `{"session_id":"sess_a","status":"active","cdp_url":"wss://cdp/a","browser_type":"firefox"}`)
+	server.AddResponse("/sessions/sess_b", 200, `{"session_id":"sess_b","status":"active"}`)
+	server.AddResponse("/sessions/sess_b/debug", 200, `{"debug_url":"http://debug","tabs":[],"ws":{"cdp":"wss://cdp/b","logs":"","recording":""}}`)
+
+	client, err := GetClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cdp, browser, err := sessionCDPURL(context.Background(), client, "sess_a")
+	if err != nil || cdp != "wss://cdp/a" || browser != "firefox" {
+		t.Errorf("expected session CDP URL, got %q, %q, %v", cdp, browser, err)
+	}
+
+	cdp, _, err = sessionCDPURL(context.Background(), client, "sess_b")
+	if err != nil || cdp != "wss://cdp/b" {
+		t.Errorf("expected debug CDP URL, got %q, %v", cdp, err)
+	}
+}
@@ -0,0 +1,396 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+const netscapeHTTPOnlyPrefix = "#HttpOnly_"
+
+var (
+	sessionCookiesFormat    string
+	sessionCookiesDomain    string
+	sessionCookiesSetFormat string
+	sessionCookiesSetDomain string
+	sessionCookiesCopyFrom  string
+	sessionCookiesCopyTo    string
+	sessionCookiesCopyDom   string
+)
+
+var sessionsCookiesCopyCmd = &cobra.Command{
+	Use:   "cookies-copy",
+	Short: "Copy cookies from one session to another",
+	Long: `Copy the cookies of one session into another, for example to reuse a
+logged-in state. Sessions are given by ID or name; --domain limits the copy
+to the cookies of one site.`,
+	Example: `  notte sessions cookies-copy --from sess_abc --to checkout
+  notte sessions cookies-copy --from login --to sess_def --domain example.com`,
+	Args: cobra.NoArgs,
+	RunE: runSessionCookiesCopy,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsCookiesCopyCmd)
+
+	sessionsCookiesCmd.Flags().StringVar(&sessionCookiesFormat, "format", "", "Export format: json, netscape (cookies.txt) or header (default: the output format)")
+	sessionsCookiesCmd.Flags().StringVar(&sessionCookiesDomain, "domain", "", "Only cookies that apply to this domain or its subdomains")
+
+	sessionsCookiesSetCmd.Flags().StringVar(&sessionCookiesSetFormat, "format", "json", "File format: json, netscape (cookies.txt) or editthiscookie")
+	sessionsCookiesSetCmd.Flags().StringVar(&sessionCookiesSetDomain, "domain", "", "Only set cookies that apply to this domain or its subdomains")
+
+	sessionsCookiesCopyCmd.Flags().StringVar(&sessionCookiesCopyFrom, "from", "", "Session ID or name to copy cookies from (required)")
+	sessionsCookiesCopyCmd.Flags().StringVar(&sessionCookiesCopyTo, "to", "", "Session ID or name to copy cookies to (required)")
+	sessionsCookiesCopyCmd.Flags().StringVar(&sessionCookiesCopyDom, "domain", "", "Only copy cookies that apply to this domain or its subdomains")
+	_ = sessionsCookiesCopyCmd.MarkFlagRequired("from")
+	_ = sessionsCookiesCopyCmd.MarkFlagRequired("to")
+}
+
+func runSessionCookies(cmd *cobra.Command, args []string) error {
+	switch sessionCookiesFormat {
+	case "", "json", "netscape", "header":
+	default:
+		return fmt.Errorf("invalid format: expected json|netscape|header, got %q", sessionCookiesFormat)
+	}
+	if err := requireSessionID(); err != nil {
+		return err
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	cookies, err := getSessionCookies(cmd, client, sessionID)
+	if err != nil {
+		return err
+	}
+	cookies = filterCookies(cookies, sessionCookiesDomain)
+
+	w := os.Stdout
+	switch sessionCookiesFormat {
+	case "json":
+		data, err := json.MarshalIndent(api.GetCookiesResponse{Cookies: cookies}, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "netscape":
+		return writeNetscapeCookies(w, cookies)
+	case "header":
+		if len(cookies) == 0 {
+			if sessionCookiesDomain != "" {
+				return fmt.Errorf("no cookies match --domain %s", sessionCookiesDomain)
+			}
+			return fmt.Errorf("session %s has no cookies", sessionID)
+		}
+		_, err := fmt.Fprintln(w, cookieHeader(cookies))
+		return err
+	default:
+		return GetFormatter().Print(&api.GetCookiesResponse{Cookies: cookies})
+	}
+}
+
+func runSessionCookiesSet(cmd *cobra.Command, args []string) error {
+	switch sessionCookiesSetFormat {
+	case "json", "netscape", "editthiscookie":
+	default:
+		return fmt.Errorf("invalid format: expected json|netscape|editthiscookie, got %q", sessionCookiesSetFormat)
+	}
+	if err := requireSessionID(); err != nil {
+		return err
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	var fileData []byte
+	if sessionCookiesSetFile == "-" {
+		fileData, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		fileData, err = os.ReadFile(sessionCookiesSetFile)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read cookies file: %w", err)
+	}
+
+	cookies, err := parseCookies(fileData, sessionCookiesSetFormat)
+	if err != nil {
+		return err
+	}
+	cookies = filterCookies(cookies, sessionCookiesSetDomain)
+	if len(cookies) == 0 && sessionCookiesSetDomain != "" {
+		return fmt.Errorf("no cookies match --domain %s", sessionCookiesSetDomain)
+	}
+
+	result, err := setSessionCookies(cmd, client, sessionID, cookies)
+	if err != nil {
+		return err
+	}
+	return GetFormatter().Print(result)
+}
+
+func runSessionCookiesCopy(cmd *cobra.Command, args []string) error {
+	from := resolveSessionRef(sessionCookiesCopyFrom)
+	to := resolveSessionRef(sessionCookiesCopyTo)
+	if from == to {
+		return errors.New("--from and --to are the same session")
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	cookies, err := getSessionCookies(cmd, client, from)
+	if err != nil {
+		return err
+	}
+	cookies = filterCookies(cookies, sessionCookiesCopyDom)
+	if len(cookies) == 0 {
+		return PrintResult(fmt.Sprintf("No cookies to copy from %s.", from), map[string]any{
+			"from":   from,
+			"to":     to,
+			"copied": 0,
+		})
+	}
+
+	if _, err := setSessionCookies(cmd, client, to, cookies); err != nil {
+		return err
+	}
+	return PrintResult(fmt.Sprintf("Copied %d cookie(s) from %s to %s.", len(cookies), from, to), map[string]any{
+		"from":   from,
+		"to":     to,
+		"copied": len(cookies),
+	})
+}
+
+func getSessionCookies(cmd *cobra.Command, client *api.NotteClient, id string) ([]api.Cookie, error) {
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
+	params := &api.SessionCookiesGetParams{}
+	resp, err := client.Client().SessionCookiesGetWithResponse(ctx, id, params)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	if resp.JSON200 == nil {
+		return nil, nil
+	}
+	return resp.JSON200.Cookies, nil
+}
+
+func setSessionCookies(cmd *cobra.Command, client *api.NotteClient, id string, cookies []api.Cookie) (*api.ExecutionResponse, error) {
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
+	params := &api.SessionCookiesSetParams{}
+	body := api.SessionCookiesSetJSONRequestBody{Cookies: cookies}
+	resp, err := client.Client().SessionCookiesSetWithResponse(ctx, id, params, body)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	return resp.JSON200, nil
+}
+
+// filterCookies keeps the cookies of domain and its subdomains, and the
+// parent-domain cookies a browser would send to it. An empty domain keeps
+// every cookie.
+func filterCookies(cookies []api.Cookie, domain string) []api.Cookie {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	if domain == "" {
+		return cookies
+	}
+
+	var kept []api.Cookie
+	for _, c := range cookies {
+		d := strings.ToLower(strings.TrimPrefix(c.Domain, "."))
+		if d == domain || strings.HasSuffix(d, "."+domain) || strings.HasSuffix(domain, "."+d) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// parseCookies reads cookies in the given import format
+func parseCookies(data []byte, format string) ([]api.Cookie, error) {
+	switch format {
+	case "netscape":
+		return parseNetscapeCookies(data)
+	case "editthiscookie":
+		var cookies []api.Cookie
+		if err := json.Unmarshal(data, &cookies); err != nil {
+			return nil, fmt.Errorf("failed to parse EditThisCookie JSON: %w", err)
+		}
+		for i := range cookies {
+			cookies[i].SameSite = normalizeSameSite(cookies[i].SameSite)
+		}
+		return cookies, nil
+	default:
+		// Accept both {"cookies": [...]}, as exported, and a bare array
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			var cookies []api.Cookie
+			if err := json.Unmarshal(trimmed, &cookies); err != nil {
+				return nil, fmt.Errorf("failed to parse cookies JSON: %w", err)
+			}
+			return cookies, nil
+		}
+		var body api.SessionCookiesSetJSONRequestBody
+		if err := json.Unmarshal(data, &body); err != nil {
+			return nil, fmt.Errorf("failed to parse cookies JSON: %w", err)
+		}
+		return body.Cookies, nil
+	}
+}
+
+// normalizeSameSite maps the EditThisCookie sameSite values to the API's
+func normalizeSameSite(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	var v string
+	switch strings.ToLower(*s) {
+	case "no_restriction", "none":
+		v = "None"
+	case "lax":
+		v = "Lax"
+	case "strict":
+		v = "Strict"
+	default:
+		return nil
+	}
+	return &v
+}
+
+// parseNetscapeCookies reads a Netscape cookies.txt file, as written by curl
+// and browser extensions
+func parseNetscapeCookies(data []byte) ([]api.Cookie, error) {
+	var cookies []api.Cookie
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+		if strings.HasPrefix(line, netscapeHTTPOnlyPrefix) {
+			line = strings.TrimPrefix(line, netscapeHTTPOnlyPrefix)
+			httpOnly = true
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) == 6 {
+			// Cookies with an empty value lose their trailing tab
+			fields = append(fields, "")
+		}
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("invalid cookies.txt line %d: expected 7 tab-separated fields, got %d", n, len(fields))
+		}
+
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cookies.txt line %d: invalid expiry %q", n, fields[4])
+		}
+		hostOnly := !strings.EqualFold(fields[1], "TRUE")
+		secure := strings.EqualFold(fields[3], "TRUE")
+		session := expires <= 0
+
+		c := api.Cookie{
+			Domain:   fields[0],
+			HostOnly: &hostOnly,
+			HttpOnly: httpOnly,
+			Path:     fields[2],
+			Secure:   &secure,
+			Session:  &session,
+			Name:     fields[5],
+			Value:    fields[6],
+		}
+		if !session {
+			exp := float32(expires)
+			c.ExpirationDate = &exp
+			c.Expires = &exp
+		}
+		cookies = append(cookies, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
+// writeNetscapeCookies writes cookies in the Netscape cookies.txt format
+// read by curl (-b) and wget (--load-cookies)
+func writeNetscapeCookies(w io.Writer, cookies []api.Cookie) error {
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintln(bw, "# Netscape HTTP Cookie File")
+	for _, c := range cookies {
+		domain := c.Domain
+		if c.HttpOnly {
+			domain = netscapeHTTPOnlyPrefix + domain
+		}
+		includeSubdomains := strings.HasPrefix(c.Domain, ".")
+		if c.HostOnly != nil {
+			includeSubdomains = !*c.HostOnly
+		}
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		_, _ = fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain, netscapeBool(includeSubdomains), path, netscapeBool(c.Secure != nil && *c.Secure),
+			cookieExpiry(c), c.Name, c.Value)
+	}
+	return bw.Flush()
+}
+
+func netscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
+
+// cookieExpiry returns the cookie's expiry in Unix seconds, or 0 for a
+// session cookie
+func cookieExpiry(c api.Cookie) int64 {
+	if c.Session != nil && *c.Session {
+		return 0
+	}
+	for _, exp := range []*float32{c.ExpirationDate, c.Expires} {
+		if exp != nil && *exp > 0 {
+			return int64(*exp)
+		}
+	}
+	return 0
+}
+
+// cookieHeader formats cookies as a Cookie request header, e.g. for
+// curl -H
+func cookieHeader(cookies []api.Cookie) string {
+	pairs := make([]string, len(cookies))
+	for i, c := range cookies {
+		pairs[i] = c.Name + "=" + c.Value
+	}
+	return "Cookie: " + strings.Join(pairs, "; ")
+}
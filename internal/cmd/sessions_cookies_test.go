@@ -0,0 +1,271 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+const cookiesJSON = `{"cookies":[
+	{"domain":".example.com","hostOnly":false,"httpOnly":true,"name":"sid","path":"/","secure":true,"session":false,"expirationDate":1893456000,"value":"abc"},
+	{"domain":"www.example.com","hostOnly":true,"httpOnly":false,"name":"theme","path":"/","secure":false,"session":true,"value":"dark"},
+	{"domain":"other.test","httpOnly":false,"name":"x","path":"/app","value":"1"}]}`
+
+func testCookies(t *testing.T) []api.Cookie {
+	t.Helper()
+	var resp api.GetCookiesResponse
+	if err := json.Unmarshal([]byte(cookiesJSON), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Cookies
+}
+
+func cookieNames(cookies []api.Cookie) string {
+	names := make([]string, len(cookies))
+	for i, c := range cookies {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestFilterCookies(t *testing.T) {
+	cookies := testCookies(t)
+	tests := map[string]string{
+		"":                "sid,theme,x",
+		"example.com":     "sid,theme",
+		"www.example.com": "sid,theme",
+		"api.example.com": "sid",
+		".other.test":     "x",
+		"example.org":     "",
+	}
+	for domain, want := range tests {
+		if got := cookieNames(filterCookies(cookies, domain)); got != want {
+			t.Errorf("filterCookies(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
+
+func TestNetscapeCookies_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeNetscapeCookies(&buf, testCookies(t)); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Netscape HTTP Cookie File\n",
+		"#HttpOnly_.example.com\tTRUE\t/\tTRUE\t1893456000\tsid\tabc\n",
+		"www.example.com\tFALSE\t/\tFALSE\t0\ttheme\tdark\n",
+		"other.test\tFALSE\t/app\tFALSE\t0\tx\t1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+
+	cookies, err := parseNetscapeCookies(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookieNames(cookies) != "sid,theme,x" {
+		t.Fatalf("unexpected cookies: %+v", cookies)
+	}
+	sid := cookies[0]
+	if !sid.HttpOnly || *sid.HostOnly || !*sid.Secure || *sid.Session || sid.ExpirationDate == nil || int64(*sid.ExpirationDate) != 1893456000 {
+		t.Errorf("unexpected sid cookie: %+v", sid)
+	}
+	if theme := cookies[1]; !*theme.HostOnly || !*theme.Session || theme.ExpirationDate != nil {
+		t.Errorf("unexpected theme cookie: %+v", theme)
+	}
+}
+
+func TestParseNetscapeCookies_Invalid(t *testing.T) {
+	if _, err := parseNetscapeCookies([]byte("example.com\tFALSE\t/\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected line error, got %v", err)
+	}
+	cookies, err := parseNetscapeCookies([]byte("# comment\n\nexample.com\tFALSE\t/\tFALSE\t0\tempty\n"))
+	if err != nil || len(cookies) != 1 || cookies[0].Value != "" {
+		t.Errorf("expected a cookie with an empty value, got %+v, %v", cookies, err)
+	}
+}
+
+func TestParseCookies_Formats(t *testing.T) {
+	etc := `[{"domain":".example.com","hostOnly":false,"httpOnly":true,"name":"sid","path":"/","sameSite":"no_restriction","secure":true,"session":true,"storeId":"0","value":"abc","id":1},
+		{"domain":"example.com","name":"b","path":"/","sameSite":"unspecified","value":"2","httpOnly":false}]`
+	cookies, err := parseCookies([]byte(etc), "editthiscookie")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookies[0].SameSite == nil || *cookies[0].SameSite != "None" || cookies[1].SameSite != nil {
+		t.Errorf("expected normalized sameSite, got %+v", cookies)
+	}
+
+	cookies, err = parseCookies([]byte(`[{"domain":"a.test","name":"n","path":"/","value":"v","httpOnly":false}]`), "json")
+	if err != nil || cookieNames(cookies) != "n" {
+		t.Errorf("expected a bare array to parse, got %+v, %v", cookies, err)
+	}
+	cookies, err = parseCookies([]byte(cookiesJSON), "json")
+	if err != nil || cookieNames(cookies) != "sid,theme,x" {
+		t.Errorf("expected the exported object to parse, got %+v, %v", cookies, err)
+	}
+}
+
+func TestCookieHeader(t *testing.T) {
+	if got := cookieHeader(filterCookies(testCookies(t), "www.example.com")); got != "Cookie: sid=abc; theme=dark" {
+		t.Errorf("unexpected header %q", got)
+	}
+}
+
+func TestRunSessionCookies_Formats(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/cookies", 200, cookiesJSON)
+
+	origFormat, origDomain := sessionCookiesFormat, sessionCookiesDomain
+	t.Cleanup(func() { sessionCookiesFormat, sessionCookiesDomain = origFormat, origDomain })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	sessionCookiesFormat, sessionCookiesDomain = "header", "other.test"
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionCookies(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if stdout != "Cookie: x=1\n" {
+		t.Errorf("unexpected header output %q", stdout)
+	}
+
+	sessionCookiesDomain = "nomatch.test"
+	if err := runSessionCookies(cmd, nil); err == nil || !strings.Contains(err.Error(), "no cookies match --domain nomatch.test") {
+		t.Errorf("expected no match error, got %v", err)
+	}
+
+	sessionCookiesFormat, sessionCookiesDomain = "json", "example.com"
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runSessionCookies(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	cookies, err := parseCookies([]byte(stdout), "json")
+	if err != nil || cookieNames(cookies) != "sid,theme" {
+		t.Errorf("expected exported JSON to read back, got %+v, %v", cookies, err)
+	}
+
+	sessionCookiesFormat = "xml"
+	if err := runSessionCookies(cmd, nil); err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Errorf("expected invalid format error, got %v", err)
+	}
+}
+
+func TestRunSessionCookiesSet_Netscape(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/cookies", 200, `{"message":"ok","success":true}`)
+
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	data := "# Netscape HTTP Cookie File\n#HttpOnly_.example.com\tTRUE\t/\tTRUE\t0\tsid\tabc\nother.test\tFALSE\t/\tFALSE\t0\tx\t1\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	origFile, origFormat, origDomain := sessionCookiesSetFile, sessionCookiesSetFormat, sessionCookiesSetDomain
+	t.Cleanup(func() {
+		sessionCookiesSetFile, sessionCookiesSetFormat, sessionCookiesSetDomain = origFile, origFormat, origDomain
+	})
+	sessionCookiesSetFile, sessionCookiesSetFormat, sessionCookiesSetDomain = path, "netscape", "example.com"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	testutil.CaptureOutput(func() {
+		if err := runSessionCookiesSet(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/cookies")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	var body api.SessionCookiesSetJSONRequestBody
+	if err := json.Unmarshal([]byte(reqs[0].Body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if cookieNames(body.Cookies) != "sid" || !body.Cookies[0].HttpOnly {
+		t.Errorf("expected only the example.com cookie, got %+v", body.Cookies)
+	}
+}
+
+func TestRunSessionCookiesSet_NoDomainMatch(t *testing.T) {
+	server := setupSessionTest(t)
+
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	data := "# Netscape HTTP Cookie File\nother.test\tFALSE\t/\tFALSE\t0\tx\t1\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	origFile, origFormat, origDomain := sessionCookiesSetFile, sessionCookiesSetFormat, sessionCookiesSetDomain
+	t.Cleanup(func() {
+		sessionCookiesSetFile, sessionCookiesSetFormat, sessionCookiesSetDomain = origFile, origFormat, origDomain
+	})
+	sessionCookiesSetFile, sessionCookiesSetFormat, sessionCookiesSetDomain = path, "netscape", "example.com"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	err := runSessionCookiesSet(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "no cookies match --domain example.com") {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+	if n := len(server.Requests("/sessions/" + sessionIDTest + "/cookies")); n != 0 {
+		t.Errorf("expected no cookies request, got %d", n)
+	}
+}
+
+func TestRunSessionCookiesCopy(t *testing.T) {
+	server, _ := setupSessionSlotsTest(t)
+	saveTestSlots(t, "", map[string]string{"login": "sess_from"})
+	server.AddResponse("/sessions/sess_from/cookies", 200, cookiesJSON)
+	server.AddResponse("/sessions/sess_to/cookies", 200, `{"message":"ok","success":true}`)
+
+	origFrom, origTo, origDomain := sessionCookiesCopyFrom, sessionCookiesCopyTo, sessionCookiesCopyDom
+	t.Cleanup(func() {
+		sessionCookiesCopyFrom, sessionCookiesCopyTo, sessionCookiesCopyDom = origFrom, origTo, origDomain
+	})
+	sessionCookiesCopyFrom, sessionCookiesCopyTo, sessionCookiesCopyDom = "login", "sess_to", "example.com"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionCookiesCopy(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "Copied 2 cookie(s) from sess_from to sess_to") {
+		t.Errorf("unexpected output %q", stdout)
+	}
+
+	reqs := server.Requests("/sessions/sess_to/cookies")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	var body api.SessionCookiesSetJSONRequestBody
+	if err := json.Unmarshal([]byte(reqs[0].Body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if cookieNames(body.Cookies) != "sid,theme" {
+		t.Errorf("unexpected copied cookies: %+v", body.Cookies)
+	}
+
+	sessionCookiesCopyTo = "login"
+	if err := runSessionCookiesCopy(cmd, nil); err == nil {
+		t.Error("expected error when copying a session onto itself")
+	}
+}
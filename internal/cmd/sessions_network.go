@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+// networkDownloadConcurrency is the number of log files downloaded at once
+const networkDownloadConcurrency = 8
+
+var (
+	sessionNetworkHAR      string
+	sessionNetworkURLMatch string
+	sessionNetworkStatus   string
+	sessionNetworkMethod   string
+	sessionNetworkMinSize  string
+	sessionNetworkSummary  bool
+	sessionNetworkTop      int
+	sessionNetworkLimit    int
+)
+
+func init() {
+	sessionsNetworkCmd.Flags().StringVar(&sessionNetworkHAR, "har", "", "Write the matching requests to this HAR file")
+	sessionsNetworkCmd.Flags().StringVar(&sessionNetworkURLMatch, "url-match", "", "Only requests whose URL matches this regular expression")
+	sessionsNetworkCmd.Flags().StringVar(&sessionNetworkStatus, "status", "", "Only responses with these statuses (e.g. 404, 4xx, 500-503, comma-separated)")
+	sessionsNetworkCmd.Flags().StringVar(&sessionNetworkMethod, "method", "", "Only requests with these methods (comma-separated)")
+	sessionsNetworkCmd.Flags().StringVar(&sessionNetworkMinSize, "min-size", "", "Only responses of at least this size (e.g. 512, 10k, 1MB)")
+	sessionsNetworkCmd.Flags().BoolVar(&sessionNetworkSummary, "summary", false, "Show the hosts with the most traffic")
+	sessionsNetworkCmd.Flags().IntVar(&sessionNetworkTop, "top", 10, "Number of hosts in --summary (0 for all)")
+	sessionsNetworkCmd.Flags().IntVar(&sessionNetworkLimit, "limit", 0, "Maximum number of log files to fetch")
+}
+
+// networkShaping reports whether any flag needs the log contents
+func networkShaping() bool {
+	return sessionNetworkHAR != "" || sessionNetworkSummary || sessionNetworkURLMatch != "" ||
+		sessionNetworkStatus != "" || sessionNetworkMethod != "" || sessionNetworkMinSize != ""
+}
+
+func runSessionNetwork(cmd *cobra.Command, args []string) error {
+	filter, err := newNetworkFilter(sessionNetworkURLMatch, sessionNetworkStatus, sessionNetworkMethod, sessionNetworkMinSize)
+	if err != nil {
+		return err
+	}
+	if sessionNetworkTop < 0 {
+		return fmt.Errorf("--top must not be negative, got %d", sessionNetworkTop)
+	}
+	if err := requireSessionID(); err != nil {
+		return err
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
+	params := &api.SessionNetworkLogsParams{}
+	if sessionNetworkLimit > 0 {
+		params.Limit = &sessionNetworkLimit
+	}
+	shaping := networkShaping()
+	if shaping {
+		download := true
+		params.Download = &download
+	}
+	resp, err := client.Client().SessionNetworkLogsWithResponse(ctx, sessionID, params)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return err
+	}
+
+	if !shaping {
+		return GetFormatter().Print(resp.JSON200)
+	}
+	if resp.JSON200 == nil {
+		return errors.New("empty response from API")
+	}
+
+	all, err := fetchNetworkEntries(cmd.Context(), resp.JSON200)
+	if err != nil {
+		return err
+	}
+	var entries []networkEntry
+	for _, e := range all {
+		if filter.match(e) {
+			entries = append(entries, e)
+		}
+	}
+
+	if sessionNetworkHAR != "" {
+		if err := writeHAR(sessionNetworkHAR, entries); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("Wrote %d of %d request(s) to %s", len(entries), len(all), sessionNetworkHAR)
+		if sessionNetworkSummary {
+			PrintInfo(msg)
+		} else {
+			return PrintResult(msg, map[string]any{
+				"path":    sessionNetworkHAR,
+				"entries": len(entries),
+				"total":   len(all),
+			})
+		}
+	}
+
+	if sessionNetworkSummary {
+		return printNetworkSummary(cmd.Context(), client, all, entries)
+	}
+	return printNetworkEntries(entries)
+}
+
+// fetchNetworkEntries downloads the request and response log files and
+// pairs them into entries. Files that fail to download are skipped with a
+// warning.
+func fetchNetworkEntries(ctx context.Context, logs *api.NetworkLogsResponse) ([]networkEntry, error) {
+	files := append(append([]api.NetworkLogFile{}, logs.Requests...), logs.Responses...)
+	groups := make([]networkLogGroup, len(files))
+	errs := make([]error, len(files))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(networkDownloadConcurrency, len(files)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				records, err := downloadNetworkLog(ctx, files[i])
+				groups[i] = networkLogGroup{Key: logFileKey(files[i]), Records: records}
+				errs[i] = err
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if len(files) > 0 && len(failed) == len(files) {
+		return nil, fmt.Errorf("failed to download network logs: %w", failed[0])
+	}
+	if len(failed) > 0 {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: skipped %d of %d log file(s): %v\n", len(failed), len(files), failed[0])
+	}
+
+	n := len(logs.Requests)
+	return buildNetworkEntries(groups[:n], groups[n:]), nil
+}
+
+// downloadNetworkLog fetches one log file from its download URL. The URL is
+// pre-signed, so the API key is not sent with it.
+func downloadNetworkLog(ctx context.Context, f api.NetworkLogFile) ([]networkLogRecord, error) {
+	if f.DownloadUrl == nil || *f.DownloadUrl == "" {
+		return nil, fmt.Errorf("%s: no download URL", f.Filename)
+	}
+
+	ctx, cancel := GetContextWithTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, *f.DownloadUrl, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Filename, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Filename, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: download failed with status %d", f.Filename, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Filename, err)
+	}
+	records, err := parseNetworkLogFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid log: %w", f.Filename, err)
+	}
+	return records, nil
+}
+
+func writeHAR(path string, entries []networkEntry) error {
+	data, err := json.MarshalIndent(buildHAR(entries), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write HAR file: %w", err)
+	}
+	return nil
+}
+
+func printNetworkEntries(entries []networkEntry) error {
+	if IsStructuredOutput() {
+		if entries == nil {
+			entries = []networkEntry{}
+		}
+		return GetFormatter().Print(entries)
+	}
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "No matching requests.")
+		return nil
+	}
+
+	rows := make([]map[string]any, len(entries))
+	for i, e := range entries {
+		started := ""
+		if !e.Started.IsZero() {
+			started = e.Started.Local().Format("15:04:05.000")
+		}
+		rows[i] = map[string]any{
+			"STARTED": started,
+			"METHOD":  e.Method,
+			"STATUS":  strconv.Itoa(e.Status),
+			"SIZE":    formatBytes(e.ResponseBytes),
+			"TIME":    strconv.FormatFloat(e.Duration, 'f', 0, 64) + " ms",
+			"URL":     e.URL,
+		}
+	}
+	tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+	return tf.PrintTable([]string{"STARTED", "METHOD", "STATUS", "SIZE", "TIME", "URL"}, rows)
+}
+
+// printNetworkSummary prints the top hosts of entries, and compares the
+// bytes in all logged entries with the totals the session reports
+func printNetworkSummary(ctx context.Context, client *api.NotteClient, all, entries []networkEntry) error {
+	hosts := summarizeHosts(entries)
+	if sessionNetworkTop > 0 && len(hosts) > sessionNetworkTop {
+		hosts = hosts[:sessionNetworkTop]
+	}
+
+	var loggedSent, loggedReceived int64
+	for _, e := range all {
+		loggedSent += e.RequestBytes
+		loggedReceived += e.ResponseBytes
+	}
+
+	// The session's totals are a cross-check; the summary stands without them
+	var session *api.SessionResponse
+	statusCtx, cancel := GetContextWithTimeout(ctx)
+	defer cancel()
+	if resp, err := client.Client().SessionStatusWithResponse(statusCtx, sessionID, &api.SessionStatusParams{}); err == nil && HandleAPIResponse(resp.HTTPResponse) == nil {
+		session = resp.JSON200
+	}
+
+	if IsStructuredOutput() {
+		data := map[string]any{
+			"hosts":                 hosts,
+			"requests":              len(entries),
+			"logged_request_bytes":  loggedSent,
+			"logged_response_bytes": loggedReceived,
+		}
+		if session != nil {
+			data["session_request_bytes"] = session.NetworkRequestBytes
+			data["session_response_bytes"] = session.NetworkResponseBytes
+		}
+		return GetFormatter().Print(data)
+	}
+
+	if len(hosts) == 0 {
+		_, _ = fmt.Fprintln(os.Stdout, "No matching requests.")
+	} else {
+		rows := make([]map[string]any, len(hosts))
+		for i, h := range hosts {
+			rows[i] = map[string]any{
+				"HOST":     h.Host,
+				"REQUESTS": strconv.Itoa(h.Requests),
+				"FAILED":   strconv.Itoa(h.Failed),
+				"SENT":     formatBytes(h.RequestBytes),
+				"RECEIVED": formatBytes(h.ResponseBytes),
+			}
+		}
+		tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+		if err := tf.PrintTable([]string{"HOST", "REQUESTS", "FAILED", "SENT", "RECEIVED"}, rows); err != nil {
+			return err
+		}
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "\nLogged: %d request(s), %s sent, %s received\n", len(all), formatBytes(loggedSent), formatBytes(loggedReceived))
+	if session != nil && session.NetworkRequestBytes != nil && session.NetworkResponseBytes != nil {
+		_, _ = fmt.Fprintf(os.Stdout, "Session reports: %s sent, %s received\n",
+			formatBytes(int64(*session.NetworkRequestBytes)), formatBytes(int64(*session.NetworkResponseBytes)))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setupNetworkTest(t *testing.T) *testutil.MockServer {
+	t.Helper()
+	server := setupSessionTest(t)
+
+	origHAR, origURL, origStatus, origMethod := sessionNetworkHAR, sessionNetworkURLMatch, sessionNetworkStatus, sessionNetworkMethod
+	origMinSize, origSummary, origTop, origLimit, origFormat := sessionNetworkMinSize, sessionNetworkSummary, sessionNetworkTop, sessionNetworkLimit, outputFormat
+	t.Cleanup(func() {
+		sessionNetworkHAR, sessionNetworkURLMatch, sessionNetworkStatus, sessionNetworkMethod = origHAR, origURL, origStatus, origMethod
+		sessionNetworkMinSize, sessionNetworkSummary, sessionNetworkTop, sessionNetworkLimit, outputFormat = origMinSize, origSummary, origTop, origLimit, origFormat
+	})
+	sessionNetworkHAR, sessionNetworkURLMatch, sessionNetworkStatus, sessionNetworkMethod = "", "", "", ""
+	sessionNetworkMinSize, sessionNetworkSummary, sessionNetworkTop, sessionNetworkLimit, outputFormat = "", false, 10, 0, "text"
+
+	logs := `{"session_id":"` + sessionIDTest + `","total_count":4,
+		"requests":[
+			{"filename":"a_request.json","path":"a","type":"request","download_url":"` + server.URL() + `/logs/a_request.json"},
+			{"filename":"b_request.json","path":"b","type":"request","download_url":"` + server.URL() + `/logs/b_request.json"}],
+		"responses":[
+			{"filename":"a_response.json","path":"a","type":"response","download_url":"` + server.URL() + `/logs/a_response.json"},
+			{"filename":"b_response.json","path":"b","type":"response","download_url":"` + server.URL() + `/logs/b_response.json"}]}`
+	server.AddResponse("/sessions/"+sessionIDTest+"/network/logs", 200, logs)
+	server.AddResponse("/logs/a_request.json", 200, `{"method":"GET","url":"https://example.com/","timestamp":"2026-01-01T00:00:00Z"}`)
+	server.AddResponse("/logs/a_response.json", 200, `{"status":200,"size":5000}`)
+	server.AddResponse("/logs/b_request.json", 200, `{"method":"POST","url":"https://api.example.com/login","timestamp":"2026-01-01T00:00:01Z","size":40}`)
+	server.AddResponse("/logs/b_response.json", 200, `{"status":500,"size":100}`)
+	return server
+}
+
+func runNetworkCmd(t *testing.T) string {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionNetwork(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	return stdout
+}
+
+func TestRunSessionNetwork_HAR(t *testing.T) {
+	server := setupNetworkTest(t)
+	sessionNetworkHAR = filepath.Join(t.TempDir(), "out.har")
+	sessionNetworkStatus = "5xx"
+
+	stdout := runNetworkCmd(t)
+	if !strings.Contains(stdout, "Wrote 1 of 2 request(s)") {
+		t.Errorf("expected HAR message, got %q", stdout)
+	}
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/network/logs")
+	if len(reqs) != 1 || reqs[0].Query.Get("download") != "true" {
+		t.Errorf("expected download URLs to be requested, got %+v", reqs)
+	}
+	for _, r := range server.Requests("/logs/a_request.json") {
+		if r.Headers.Get("Authorization") != "" {
+			t.Error("expected the API key not to be sent to download URLs")
+		}
+	}
+
+	data, err := os.ReadFile(sessionNetworkHAR)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		t.Fatal(err)
+	}
+	if len(har.Log.Entries) != 1 || har.Log.Entries[0].Request.URL != "https://api.example.com/login" || har.Log.Entries[0].Response.Status != 500 {
+		t.Errorf("unexpected HAR entries: %+v", har.Log.Entries)
+	}
+}
+
+func TestRunSessionNetwork_List(t *testing.T) {
+	setupNetworkTest(t)
+	sessionNetworkMethod = "get"
+
+	stdout := runNetworkCmd(t)
+	if !strings.Contains(stdout, "https://example.com/") || strings.Contains(stdout, "/login") {
+		t.Errorf("expected only the GET request, got %q", stdout)
+	}
+}
+
+func TestRunSessionNetwork_Summary(t *testing.T) {
+	server := setupNetworkTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest, 200, `{"session_id":"`+sessionIDTest+`","status":"active","created_at":"2020-01-01T00:00:00Z","last_accessed_at":"2020-01-01T00:00:00Z","timeout_minutes":0,"network_request_bytes":50,"network_response_bytes":6000}`)
+	sessionNetworkSummary = true
+
+	stdout := runNetworkCmd(t)
+	for _, want := range []string{"example.com", "api.example.com", "Logged: 2 request(s), 40 B sent, 5.1 kB received", "Session reports: 50 B sent, 6.0 kB received"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected %q in summary:\n%s", want, stdout)
+		}
+	}
+	if strings.Index(stdout, "example.com") > strings.Index(stdout, "api.example.com") {
+		t.Errorf("expected the busiest host first:\n%s", stdout)
+	}
+
+	outputFormat = "json"
+	stdout = runNetworkCmd(t)
+	var summary map[string]any
+	if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+		t.Fatalf("expected JSON, got %q", stdout)
+	}
+	if summary["session_response_bytes"] != float64(6000) || summary["logged_response_bytes"] != float64(5100) {
+		t.Errorf("unexpected summary: %v", summary)
+	}
+}
@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+var (
+	sessionsPruneIdle         string
+	sessionsPruneOlderThan    string
+	sessionsPruneDryRun       bool
+	sessionsPruneConcurrency  int
+	sessionsPruneCurrentToken bool
+)
+
+var sessionsPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Stop forgotten sessions",
+	Long: `Stop active sessions that have been idle or running for too long.
+
+A session is selected when it has not been accessed for longer than --idle,
+or was created longer ago than --older-than. The selected sessions are shown
+and, once confirmed, stopped in parallel.`,
+	Example: `  notte sessions prune --idle 10m --dry-run
+  notte sessions prune --older-than 2h --current-token --yes`,
+	Args: cobra.NoArgs,
+	RunE: runSessionsPrune,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsPruneCmd)
+
+	sessionsPruneCmd.Flags().StringVar(&sessionsPruneIdle, "idle", "", "Select sessions not accessed for longer than this (e.g. 10m, 2h)")
+	sessionsPruneCmd.Flags().StringVar(&sessionsPruneOlderThan, "older-than", "", "Select sessions created longer ago than this (e.g. 2h, 1d)")
+	sessionsPruneCmd.Flags().BoolVar(&sessionsPruneDryRun, "dry-run", false, "Show the sessions that would be stopped without stopping them")
+	sessionsPruneCmd.Flags().IntVar(&sessionsPruneConcurrency, "concurrency", 4, "Sessions to stop in parallel")
+	sessionsPruneCmd.Flags().BoolVar(&sessionsPruneCurrentToken, "current-token", false, "Only consider sessions created with the current API key")
+}
+
+// pruneCandidate is a session selected by `sessions prune`
+type pruneCandidate struct {
+	ID             string    `json:"id"`
+	CreatedAt      time.Time `json:"created_at"`
+	LastAccessedAt time.Time `json:"last_accessed_at"`
+	Idle           string    `json:"idle"`
+	Reason         string    `json:"reason"`
+	CreditUsage    float64   `json:"credit_usage"`
+	Stopped        bool      `json:"stopped"`
+	Error          string    `json:"error,omitempty"`
+}
+
+func runSessionsPrune(cmd *cobra.Command, args []string) error {
+	if sessionsPruneIdle == "" && sessionsPruneOlderThan == "" {
+		return errors.New("specify --idle and/or --older-than")
+	}
+	if sessionsPruneConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", sessionsPruneConcurrency)
+	}
+
+	var idle, olderThan time.Duration
+	var err error
+	if sessionsPruneIdle != "" {
+		if idle, err = parseDurationFlag(sessionsPruneIdle); err != nil {
+			return fmt.Errorf("invalid --idle: %w", err)
+		}
+	}
+	if sessionsPruneOlderThan != "" {
+		if olderThan, err = parseDurationFlag(sessionsPruneOlderThan); err != nil {
+			return fmt.Errorf("invalid --older-than: %w", err)
+		}
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := listActiveSessions(cmd.Context(), client, sessionsPruneCurrentToken)
+	if err != nil {
+		return err
+	}
+	candidates := selectPruneCandidates(sessions, idle, olderThan, time.Now())
+
+	if len(candidates) == 0 {
+		return PrintResult("No sessions to prune.", map[string]any{"sessions": []pruneCandidate{}})
+	}
+
+	if !IsStructuredOutput() {
+		if err := printPrunePlan(candidates); err != nil {
+			return err
+		}
+	}
+
+	if sessionsPruneDryRun {
+		PrintInfo(fmt.Sprintf("Dry run: %d session(s) would be stopped.", len(candidates)))
+		if IsStructuredOutput() {
+			return GetFormatter().Print(candidates)
+		}
+		return nil
+	}
+
+	confirmed, err := ConfirmPrompt(fmt.Sprintf("Stop %d session(s)?", len(candidates)))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		return PrintResult("Cancelled.", map[string]any{"cancelled": true})
+	}
+
+	stopSessions(cmd.Context(), client, candidates, sessionsPruneConcurrency)
+
+	stopped, credits := 0, 0.0
+	for i := range candidates {
+		c := &candidates[i]
+		if !c.Stopped {
+			PrintInfo(fmt.Sprintf("Failed to stop %s: %s", c.ID, c.Error))
+			continue
+		}
+		stopped++
+		credits += c.CreditUsage
+		forgetSession(c.ID)
+	}
+
+	msg := fmt.Sprintf("Stopped %d of %d session(s), which had used %s credits.", stopped, len(candidates), strconv.FormatFloat(credits, 'f', -1, 64))
+	if err := PrintResult(msg, map[string]any{
+		"sessions":     candidates,
+		"stopped":      stopped,
+		"credit_usage": credits,
+	}); err != nil {
+		return err
+	}
+
+	if failed := len(candidates) - stopped; failed > 0 {
+		return fmt.Errorf("%d of %d sessions failed to stop", failed, len(candidates))
+	}
+	return nil
+}
+
+// listActiveSessions fetches every page of active sessions
+func listActiveSessions(ctx context.Context, client *api.NotteClient, currentToken bool) ([]api.SessionResponse, error) {
+	active := true
+	params := &api.ListSessionsParams{OnlyActive: &active}
+	if currentToken {
+		params.OnlyCurrentToken = &currentToken
+	}
+
+	var sessions []api.SessionResponse
+	for page := 1; ; page++ {
+		p := page
+		params.Page = &p
+
+		pageCtx, cancel := GetContextWithTimeout(ctx)
+		resp, err := client.Client().ListSessionsWithResponse(pageCtx, params)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("API request failed: %w", err)
+		}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, err
+		}
+		if resp.JSON200 == nil || len(resp.JSON200.Items) == 0 {
+			return sessions, nil
+		}
+
+		sessions = append(sessions, resp.JSON200.Items...)
+		if !resp.JSON200.HasNext {
+			return sessions, nil
+		}
+	}
+}
+
+// selectPruneCandidates picks the sessions idle for longer than idle or
+// created longer ago than olderThan. A zero duration disables that check.
+func selectPruneCandidates(sessions []api.SessionResponse, idle, olderThan time.Duration, now time.Time) []pruneCandidate {
+	var candidates []pruneCandidate
+	for _, s := range sessions {
+		idleFor := now.Sub(s.LastAccessedAt)
+		age := now.Sub(s.CreatedAt)
+
+		var reason string
+		switch {
+		case idle > 0 && idleFor > idle:
+			reason = "idle " + idleFor.Round(time.Second).String()
+		case olderThan > 0 && age > olderThan:
+			reason = "age " + age.Round(time.Second).String()
+		default:
+			continue
+		}
+
+		c := pruneCandidate{
+			ID:             s.SessionId,
+			CreatedAt:      s.CreatedAt,
+			LastAccessedAt: s.LastAccessedAt,
+			Idle:           idleFor.Round(time.Second).String(),
+			Reason:         reason,
+		}
+		if s.CreditUsage != nil {
+			c.CreditUsage = float64(*s.CreditUsage)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+func printPrunePlan(candidates []pruneCandidate) error {
+	tf := &output.TextFormatter{Writer: os.Stdout, NoColor: noColor}
+	rows := make([]map[string]any, len(candidates))
+	for i, c := range candidates {
+		rows[i] = map[string]any{
+			"ID":            c.ID,
+			"CREATED":       c.CreatedAt.Local().Format(time.DateTime),
+			"LAST ACCESSED": c.LastAccessedAt.Local().Format(time.DateTime),
+			"REASON":        c.Reason,
+			"CREDITS":       strconv.FormatFloat(c.CreditUsage, 'f', -1, 64),
+		}
+	}
+	return tf.PrintTable([]string{"ID", "CREATED", "LAST ACCESSED", "REASON", "CREDITS"}, rows)
+}
+
+// stopSessions stops the candidates over a fixed number of workers,
+// recording the outcome and final credit usage on each candidate
+func stopSessions(ctx context.Context, client *api.NotteClient, candidates []pruneCandidate, concurrency int) {
+	jobs := make(chan *pruneCandidate)
+
+	var wg sync.WaitGroup
+	for i := 0; i < min(concurrency, len(candidates)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := range jobs {
+				session, err := stopSession(ctx, client, c.ID)
+				if err != nil {
+					c.Error = err.Error()
+					continue
+				}
+				c.Stopped = true
+				if session != nil && session.CreditUsage != nil {
+					c.CreditUsage = float64(*session.CreditUsage)
+				}
+			}
+		}()
+	}
+
+	for i := range candidates {
+		if ctx.Err() != nil {
+			candidates[i].Error = ctx.Err().Error()
+			continue
+		}
+		jobs <- &candidates[i]
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func stopSession(ctx context.Context, client *api.NotteClient, id string) (*api.SessionResponse, error) {
+	ctx, cancel := GetContextWithTimeout(ctx)
+	defer cancel()
+
+	resp, err := client.Client().SessionStopWithResponse(ctx, id, &api.SessionStopParams{})
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return nil, err
+	}
+	return resp.JSON200, nil
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func TestSelectPruneCandidates(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	sessions := []api.SessionResponse{
+		{SessionId: "idle", CreatedAt: now.Add(-30 * time.Minute), LastAccessedAt: now.Add(-20 * time.Minute)},
+		{SessionId: "busy", CreatedAt: now.Add(-30 * time.Minute), LastAccessedAt: now.Add(-time.Minute)},
+		{SessionId: "old", CreatedAt: now.Add(-3 * time.Hour), LastAccessedAt: now.Add(-time.Minute)},
+	}
+
+	got := selectPruneCandidates(sessions, 10*time.Minute, 0, now)
+	if len(got) != 1 || got[0].ID != "idle" || got[0].Reason != "idle 20m0s" {
+		t.Errorf("expected only the idle session, got %+v", got)
+	}
+
+	got = selectPruneCandidates(sessions, 10*time.Minute, 2*time.Hour, now)
+	if len(got) != 2 || got[1].ID != "old" || !strings.HasPrefix(got[1].Reason, "age") {
+		t.Errorf("expected idle and old sessions, got %+v", got)
+	}
+}
+
+func setupPruneTest(t *testing.T) *testutil.MockServer {
+	t.Helper()
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	t.Cleanup(server.Close)
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	server.AddResponse("/sessions", 200, `{"has_next": false, "items": [
+		{"session_id": "sess_idle", "status": "active", "created_at": "2000-01-01T00:00:00Z", "last_accessed_at": "2000-01-01T00:00:00Z", "credit_usage": 1.5},
+		{"session_id": "sess_busy", "status": "active", "created_at": "2999-01-01T00:00:00Z", "last_accessed_at": "2999-01-01T00:00:00Z"}
+	]}`)
+	server.AddResponse("/sessions/sess_idle/stop", 200, `{"session_id": "sess_idle", "status": "closed", "credit_usage": 2.5}`)
+
+	origIdle, origOlder, origDry := sessionsPruneIdle, sessionsPruneOlderThan, sessionsPruneDryRun
+	origConc, origToken, origSkip, origFormat := sessionsPruneConcurrency, sessionsPruneCurrentToken, skipConfirmation, outputFormat
+	t.Cleanup(func() {
+		sessionsPruneIdle, sessionsPruneOlderThan, sessionsPruneDryRun = origIdle, origOlder, origDry
+		sessionsPruneConcurrency, sessionsPruneCurrentToken, skipConfirmation, outputFormat = origConc, origToken, origSkip, origFormat
+	})
+	sessionsPruneIdle, sessionsPruneOlderThan, sessionsPruneDryRun = "10m", "", false
+	sessionsPruneConcurrency, sessionsPruneCurrentToken, skipConfirmation, outputFormat = 4, false, true, "text"
+
+	return server
+}
+
+func runPrune(t *testing.T) (string, string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var err error
+	stdout, stderr := testutil.CaptureOutput(func() {
+		err = runSessionsPrune(cmd, nil)
+	})
+	return stdout, stderr, err
+}
+
+func TestRunSessionsPrune_DryRun(t *testing.T) {
+	server := setupPruneTest(t)
+	sessionsPruneDryRun = true
+
+	stdout, _, err := runPrune(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(stdout, "sess_idle") || strings.Contains(stdout, "sess_busy") {
+		t.Errorf("expected plan with only the idle session, got %q", stdout)
+	}
+	if !strings.Contains(stdout, "Dry run: 1 session(s) would be stopped.") {
+		t.Errorf("expected dry run summary, got %q", stdout)
+	}
+	if n := len(server.Requests("/sessions/sess_idle/stop")); n != 0 {
+		t.Errorf("expected no stop requests in dry run, got %d", n)
+	}
+	if q := server.Requests("/sessions")[0].Query; q.Get("only_active") != "true" {
+		t.Errorf("expected only active sessions to be listed, got %v", q)
+	}
+}
+
+func TestRunSessionsPrune_Stops(t *testing.T) {
+	server := setupPruneTest(t)
+
+	stdout, _, err := runPrune(t)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(server.Requests("/sessions/sess_idle/stop")); n != 1 {
+		t.Errorf("expected 1 stop request, got %d", n)
+	}
+	if !strings.Contains(stdout, "Stopped 1 of 1 session(s), which had used 2.5 credits.") {
+		t.Errorf("expected summary with final credit usage, got %q", stdout)
+	}
+}
+
+func TestRunSessionsPrune_StopFailure(t *testing.T) {
+	server := setupPruneTest(t)
+	server.AddResponse("/sessions/sess_idle/stop", 500, `{"detail": "boom"}`)
+
+	stdout, _, err := runPrune(t)
+	if err == nil || !strings.Contains(err.Error(), "1 of 1 sessions failed to stop") {
+		t.Errorf("expected failure error, got %v", err)
+	}
+	if !strings.Contains(stdout, "Failed to stop sess_idle") {
+		t.Errorf("expected per-session failure, got %q", stdout)
+	}
+}
+
+func TestRunSessionsPrune_RequiresSelector(t *testing.T) {
+	setupPruneTest(t)
+	sessionsPruneIdle = ""
+
+	if _, _, err := runPrune(t); err == nil || !strings.Contains(err.Error(), "--idle") {
+		t.Errorf("expected selector error, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/screenshot"
+)
+
+var (
+	sessionsScreenshotOut      string
+	sessionsScreenshotURL      string
+	sessionsScreenshotAnnotate bool
+)
+
+var sessionsScreenshotCmd = &cobra.Command{
+	Use:   "screenshot",
+	Short: "Save a screenshot of the session's page",
+	Long: `Save a screenshot of the session's current page to a file.
+
+With --annotate, the bounding box of each action found on the page is drawn
+onto the image with its action ID, to see which ID maps to which element.
+Annotated screenshots are written as JPEG for .jpg and .jpeg files and as
+PNG otherwise.`,
+	Example: `  notte sessions screenshot --out page.png
+  notte sessions screenshot --url https://example.com --out page.png --annotate`,
+	Args: cobra.NoArgs,
+	RunE: runSessionsScreenshot,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsScreenshotCmd)
+
+	addSessionIDFlags(sessionsScreenshotCmd)
+	sessionsScreenshotCmd.Flags().StringVar(&sessionsScreenshotOut, "out", "", "File to save the screenshot to (required)")
+	sessionsScreenshotCmd.Flags().StringVar(&sessionsScreenshotURL, "url", "", "Navigate to URL before taking the screenshot")
+	sessionsScreenshotCmd.Flags().BoolVar(&sessionsScreenshotAnnotate, "annotate", false, "Draw action bounding boxes and IDs on the screenshot")
+	_ = sessionsScreenshotCmd.MarkFlagRequired("out")
+}
+
+func runSessionsScreenshot(cmd *cobra.Command, args []string) error {
+	if err := requireSessionID(); err != nil {
+		return err
+	}
+
+	body := api.PageObserveJSONRequestBody{}
+	if sessionsScreenshotURL != "" {
+		body.Url = &sessionsScreenshotURL
+	}
+
+	obs, err := observePage(cmd, body)
+	if err != nil {
+		return err
+	}
+	if obs == nil {
+		return errors.New("empty response from API")
+	}
+
+	if err := screenshot.Save(sessionsScreenshotOut, obs.Screenshot, sessionsScreenshotAnnotate); err != nil {
+		return err
+	}
+
+	boxes := 0
+	if obs.Screenshot.Bboxes != nil {
+		boxes = len(*obs.Screenshot.Bboxes)
+	}
+	msg := fmt.Sprintf("Screenshot saved to %s", sessionsScreenshotOut)
+	if sessionsScreenshotAnnotate {
+		msg = fmt.Sprintf("Screenshot saved to %s with %d annotated action(s)", sessionsScreenshotOut, boxes)
+	}
+	return PrintResult(msg, map[string]any{
+		"path":      sessionsScreenshotOut,
+		"url":       obs.Metadata.Url,
+		"annotated": sessionsScreenshotAnnotate,
+		"bboxes":    boxes,
+	})
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func observeWithScreenshot(t *testing.T) (string, []byte) {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	raw := buf.Bytes()
+	resp := fmt.Sprintf(`{"metadata":{"tabs":[],"title":"Tab","url":"https://example.com"},"screenshot":{"raw":%q,"bboxes":[{"x":2,"y":12,"width":10,"height":8,"scroll_x":0,"scroll_y":0,"viewport_width":40,"viewport_height":30,"notte_id":"B1"}]},"session":%s,"space":{"category":"page","description":"desc","interaction_actions":[]}}`,
+		base64.StdEncoding.EncodeToString(raw), sessionJSON())
+	return resp, raw
+}
+
+func TestRunSessionsScreenshot(t *testing.T) {
+	server := setupSessionTest(t)
+	resp, raw := observeWithScreenshot(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/observe", 200, resp)
+
+	out := filepath.Join(t.TempDir(), "page.png")
+	origOut, origAnnotate, origFormat := sessionsScreenshotOut, sessionsScreenshotAnnotate, outputFormat
+	t.Cleanup(func() {
+		sessionsScreenshotOut, sessionsScreenshotAnnotate, outputFormat = origOut, origAnnotate, origFormat
+	})
+	sessionsScreenshotOut, sessionsScreenshotAnnotate, outputFormat = out, false, "text"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionsScreenshot(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "Screenshot saved to "+out) {
+		t.Errorf("expected saved message, got %q", stdout)
+	}
+	if got, _ := os.ReadFile(out); !bytes.Equal(got, raw) {
+		t.Error("expected the raw screenshot on disk")
+	}
+
+	sessionsScreenshotAnnotate = true
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runSessionsScreenshot(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "1 annotated action(s)") {
+		t.Errorf("expected annotation count, got %q", stdout)
+	}
+	got, _ := os.ReadFile(out)
+	if bytes.Equal(got, raw) {
+		t.Error("expected the annotated screenshot to differ from the raw one")
+	}
+	if _, err := png.Decode(bytes.NewReader(got)); err != nil {
+		t.Errorf("expected a PNG, got %v", err)
+	}
+}
+
+func TestRunSessionObserve_Screenshot(t *testing.T) {
+	server := setupSessionTest(t)
+	resp, raw := observeWithScreenshot(t)
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/observe", 200, resp)
+
+	out := filepath.Join(t.TempDir(), "page.png")
+	origShot, origAnnotate, origFormat := sessionObserveScreenshot, sessionObserveAnnotate, outputFormat
+	t.Cleanup(func() {
+		sessionObserveScreenshot, sessionObserveAnnotate, outputFormat = origShot, origAnnotate, origFormat
+	})
+	sessionObserveScreenshot, sessionObserveAnnotate, outputFormat = out, false, "json"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionObserve(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if strings.Contains(stdout, base64.StdEncoding.EncodeToString(raw)) {
+		t.Error("expected the screenshot to be left out of the output")
+	}
+	if !strings.Contains(stdout, `"notte_id":"B1"`) {
+		t.Errorf("expected bounding boxes in the output, got %q", stdout)
+	}
+	if got, _ := os.ReadFile(out); !bytes.Equal(got, raw) {
+		t.Error("expected the screenshot on disk")
+	}
+}
+
+func TestRunSessionObserve_AnnotateRequiresScreenshot(t *testing.T) {
+	origShot, origAnnotate := sessionObserveScreenshot, sessionObserveAnnotate
+	t.Cleanup(func() { sessionObserveScreenshot, sessionObserveAnnotate = origShot, origAnnotate })
+	sessionObserveScreenshot, sessionObserveAnnotate = "", true
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	if err := runSessionObserve(cmd, nil); err == nil || !strings.Contains(err.Error(), "--screenshot") {
+		t.Errorf("expected --screenshot error, got %v", err)
+	}
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
+)
+
+var (
+	sessionName       string
+	sessionsStartName string
+)
+
+var sessionsUseCmd = &cobra.Command{
+	Use:   "use <name|id>",
+	Short: "Make a named session or session ID the current session",
+	Long: `Make a named session or session ID the current session.
+
+Sessions are named with 'notte sessions start --name <name>'. To work on
+different sessions in parallel terminals, select one per terminal with
+--session <name> or the NOTTE_SESSION environment variable instead.`,
+	Example: `  notte sessions use checkout
+  notte sessions use sess_abc123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSessionsUse,
+}
+
+var sessionsCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the current session and whether it is still running",
+	Long: `Show the session that commands use by default, where it was selected from,
+and whether it is still running. Sessions that have ended are removed from
+the named sessions and the current session.`,
+	Args: cobra.NoArgs,
+	RunE: runSessionsCurrent,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsUseCmd)
+	sessionsCmd.AddCommand(sessionsCurrentCmd)
+
+	sessionsStartCmd.Flags().StringVar(&sessionsStartName, "name", "", "Name the session so it can be selected with --session or 'notte sessions use'")
+	addSessionIDFlags(sessionsCurrentCmd)
+}
+
+// addSessionIDFlags registers --id and --session on commands that act on a
+// session
+func addSessionIDFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sessionID, "id", "", "Session ID (uses current session if not specified)")
+	cmd.Flags().StringVar(&sessionName, "session", "", "Named session (see 'notte sessions use')")
+	cmd.MarkFlagsMutuallyExclusive("id", "session")
+}
+
+// currentSession is the session commands act on and where it came from
+type currentSession struct {
+	ID     string
+	Name   string
+	Source string
+}
+
+// resolveCurrentSession finds the session to act on, in priority order:
+// --id, --session, NOTTE_SESSION_ID, NOTTE_SESSION, then the current
+// session file. An empty ID means no session is selected.
+func resolveCurrentSession() (currentSession, error) {
+	if sessionID != "" {
+		return currentSession{ID: sessionID, Source: "--id"}, nil
+	}
+	if sessionName != "" {
+		return namedSession(sessionName, "--session")
+	}
+	if envID := os.Getenv(config.EnvSessionID); envID != "" {
+		return currentSession{ID: envID, Source: config.EnvSessionID}, nil
+	}
+	if name := os.Getenv(config.EnvSession); name != "" {
+		return namedSession(name, config.EnvSession)
+	}
+
+	cur := currentSession{ID: readCurrentSessionFile(), Source: "current session"}
+	if cur.ID != "" {
+		if slots, err := config.LoadSessionSlots(); err == nil {
+			cur.Name = slots.NameOf(cur.ID)
+		}
+	}
+	return cur, nil
+}
+
+func namedSession(name, source string) (currentSession, error) {
+	slots, err := config.LoadSessionSlots()
+	if err != nil {
+		return currentSession{}, err
+	}
+	id, ok := slots.Lookup(name)
+	if !ok {
+		return currentSession{}, unknownSessionName(name, slots)
+	}
+	return currentSession{ID: id, Name: name, Source: source}, nil
+}
+
+func unknownSessionName(name string, slots *config.SessionSlots) error {
+	if names := slots.Names(); len(names) > 0 {
+		return fmt.Errorf("no session named %q (known: %s)", name, strings.Join(names, ", "))
+	}
+	return fmt.Errorf("no session named %q; name one with 'notte sessions start --name %s'", name, name)
+}
+
+// resolveSessionRef returns the session ID for a flag that accepts either a
+// session name or an ID
+func resolveSessionRef(ref string) string {
+	if ref == "" {
+		return ""
+	}
+	if slots, err := config.LoadSessionSlots(); err == nil {
+		if id, ok := slots.Lookup(ref); ok {
+			return id
+		}
+	}
+	return ref
+}
+
+// useSession makes id the current session, naming it if name is set
+func useSession(name, id string) error {
+	if err := setCurrentSession(id); err != nil {
+		return err
+	}
+
+	return config.UpdateSessionSlots(func(slots *config.SessionSlots) bool {
+		if name != "" {
+			slots.Set(name, id)
+		}
+		slots.Active = name
+		return true
+	})
+}
+
+// forgetSession removes a session that has ended from the named sessions
+// and the current session, returning the names it was removed from
+func forgetSession(id string) []string {
+	clearCurrentSessionIf(id)
+
+	var removed []string
+	_ = config.UpdateSessionSlots(func(slots *config.SessionSlots) bool {
+		removed = slots.Forget(id)
+		return len(removed) > 0
+	})
+	return removed
+}
+
+func runSessionsUse(cmd *cobra.Command, args []string) error {
+	ref := args[0]
+
+	slots, err := config.LoadSessionSlots()
+	if err != nil {
+		return err
+	}
+	name, id := "", ref
+	if slotID, ok := slots.Lookup(ref); ok {
+		name, id = ref, slotID
+	}
+
+	if err := useSession(name, id); err != nil {
+		return fmt.Errorf("failed to save current session: %w", err)
+	}
+
+	msg := fmt.Sprintf("Current session is now %s.", id)
+	if name != "" {
+		msg = fmt.Sprintf("Current session is now %q (%s).", name, id)
+	}
+	return PrintResult(msg, map[string]any{
+		"id":   id,
+		"name": name,
+	})
+}
+
+func runSessionsCurrent(cmd *cobra.Command, args []string) error {
+	cur, err := resolveCurrentSession()
+	if err != nil {
+		return err
+	}
+	if cur.ID == "" {
+		return PrintResult("No current session.", map[string]any{"id": "", "alive": false})
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
+	status := ""
+	resp, err := client.Client().SessionStatusWithResponse(ctx, cur.ID, &api.SessionStatusParams{})
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		var apiErr *notteErrors.APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return err
+		}
+		status = "not found"
+	} else if resp.JSON200 != nil {
+		status = string(resp.JSON200.Status)
+	}
+
+	alive := strings.EqualFold(status, string(api.SessionResponseStatusActive))
+	var removed []string
+	if !alive {
+		removed = forgetSession(cur.ID)
+	}
+
+	label := cur.ID
+	if cur.Name != "" {
+		label = fmt.Sprintf("%s (%s)", cur.Name, cur.ID)
+	}
+	msg := fmt.Sprintf("%s from %s: %s", label, cur.Source, status)
+	if !alive {
+		msg += "\nThe session has ended; it was removed from the current and named sessions."
+	}
+
+	return PrintResult(msg, map[string]any{
+		"id":      cur.ID,
+		"name":    cur.Name,
+		"source":  cur.Source,
+		"status":  status,
+		"alive":   alive,
+		"removed": removed,
+	})
+}
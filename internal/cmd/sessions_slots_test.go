@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setupSessionSlotsTest(t *testing.T) (*testutil.MockServer, *testutil.TestEnv) {
+	t.Helper()
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+	setupSessionFileTest(t)
+
+	server := testutil.NewMockServer()
+	t.Cleanup(server.Close)
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	origID, origName, origStartName, origFormat := sessionID, sessionName, sessionsStartName, outputFormat
+	t.Cleanup(func() {
+		sessionID, sessionName, sessionsStartName, outputFormat = origID, origName, origStartName, origFormat
+	})
+	sessionID, sessionName, sessionsStartName, outputFormat = "", "", "", "text"
+
+	return server, env
+}
+
+func saveTestSlots(t *testing.T, active string, slots map[string]string) {
+	t.Helper()
+	s := &config.SessionSlots{Active: active, Slots: slots}
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunSessionsStart_Name(t *testing.T) {
+	server, _ := setupSessionSlotsTest(t)
+	server.AddResponse("/sessions/start", 200, `{"session_id": "sess_named", "status": "active"}`)
+	sessionsStartName = "checkout"
+
+	cmd := &cobra.Command{}
+	addSessionStartFlags(cmd)
+	cmd.SetContext(context.Background())
+	testutil.CaptureOutput(func() {
+		if err := runSessionsStart(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	slots, err := config.LoadSessionSlots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, _ := slots.Lookup("checkout"); id != "sess_named" || slots.Active != "checkout" {
+		t.Errorf("expected active slot checkout=sess_named, got %+v", slots)
+	}
+	if got := readCurrentSessionFile(); got != "sess_named" {
+		t.Errorf("expected current session sess_named, got %q", got)
+	}
+
+	sessionsStartName = "bad name"
+	if err := runSessionsStart(cmd, nil); err == nil || !strings.Contains(err.Error(), "invalid session name") {
+		t.Errorf("expected invalid name error, got %v", err)
+	}
+}
+
+func TestResolveCurrentSession_Names(t *testing.T) {
+	_, env := setupSessionSlotsTest(t)
+	saveTestSlots(t, "", map[string]string{"checkout": "sess_1", "search": "sess_2"})
+	if err := setCurrentSession("sess_2"); err != nil {
+		t.Fatal(err)
+	}
+
+	cur, err := resolveCurrentSession()
+	if err != nil || cur.ID != "sess_2" || cur.Name != "search" || cur.Source != "current session" {
+		t.Errorf("expected named current session from file, got %+v (%v)", cur, err)
+	}
+
+	env.SetEnv(config.EnvSession, "checkout")
+	if cur, _ = resolveCurrentSession(); cur.ID != "sess_1" || cur.Source != config.EnvSession {
+		t.Errorf("expected NOTTE_SESSION to select checkout, got %+v", cur)
+	}
+
+	env.SetEnv(config.EnvSessionID, "sess_env")
+	if cur, _ = resolveCurrentSession(); cur.ID != "sess_env" {
+		t.Errorf("expected NOTTE_SESSION_ID to win over NOTTE_SESSION, got %+v", cur)
+	}
+
+	sessionName = "search"
+	if cur, _ = resolveCurrentSession(); cur.ID != "sess_2" || cur.Source != "--session" {
+		t.Errorf("expected --session to win over env vars, got %+v", cur)
+	}
+
+	sessionName = "nope"
+	if err := requireSessionID(); err == nil || !strings.Contains(err.Error(), `no session named "nope" (known: checkout, search)`) {
+		t.Errorf("expected unknown name error, got %v", err)
+	}
+	if got := resolveSessionRef("checkout"); got != "sess_1" {
+		t.Errorf("expected name to resolve, got %q", got)
+	}
+	if got := resolveSessionRef("sess_raw"); got != "sess_raw" {
+		t.Errorf("expected IDs to pass through, got %q", got)
+	}
+}
+
+func TestRunSessionsUse(t *testing.T) {
+	setupSessionSlotsTest(t)
+	saveTestSlots(t, "", map[string]string{"checkout": "sess_1"})
+
+	testutil.CaptureOutput(func() {
+		if err := runSessionsUse(&cobra.Command{}, []string{"checkout"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	slots, _ := config.LoadSessionSlots()
+	if readCurrentSessionFile() != "sess_1" || slots.Active != "checkout" {
+		t.Errorf("expected checkout to be current, got file %q, slots %+v", readCurrentSessionFile(), slots)
+	}
+
+	testutil.CaptureOutput(func() {
+		if err := runSessionsUse(&cobra.Command{}, []string{"sess_other"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	slots, _ = config.LoadSessionSlots()
+	if readCurrentSessionFile() != "sess_other" || slots.Active != "" {
+		t.Errorf("expected raw ID to be current, got file %q, slots %+v", readCurrentSessionFile(), slots)
+	}
+}
+
+func TestRunSessionsCurrent(t *testing.T) {
+	server, _ := setupSessionSlotsTest(t)
+	saveTestSlots(t, "checkout", map[string]string{"checkout": "sess_1", "search": "sess_2"})
+	if err := setCurrentSession("sess_1"); err != nil {
+		t.Fatal(err)
+	}
+	server.AddResponse("/sessions/sess_1", 200, `{"session_id": "sess_1", "status": "active"}`)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionsCurrent(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "checkout (sess_1) from current session: active") {
+		t.Errorf("expected alive named session, got %q", stdout)
+	}
+
+	server.AddResponse("/sessions/sess_1", 200, `{"session_id": "sess_1", "status": "closed"}`)
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runSessionsCurrent(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "has ended") {
+		t.Errorf("expected ended session, got %q", stdout)
+	}
+	slots, _ := config.LoadSessionSlots()
+	if _, ok := slots.Lookup("checkout"); ok || slots.Active != "" || readCurrentSessionFile() != "" {
+		t.Errorf("expected stale session to be cleaned up, got file %q, slots %+v", readCurrentSessionFile(), slots)
+	}
+	if _, ok := slots.Lookup("search"); !ok {
+		t.Error("expected other named sessions to be kept")
+	}
+
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runSessionsCurrent(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "No current session.") {
+		t.Errorf("expected no current session, got %q", stdout)
+	}
+}
+
+func TestRunSessionsCurrent_NotFound(t *testing.T) {
+	server, _ := setupSessionSlotsTest(t)
+	saveTestSlots(t, "", map[string]string{"gone": "sess_gone"})
+	sessionName = "gone"
+	server.AddResponse("/sessions/sess_gone", 404, `{"detail": "Session not found"}`)
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionsCurrent(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "not found") {
+		t.Errorf("expected not found status, got %q", stdout)
+	}
+	if slots, _ := config.LoadSessionSlots(); len(slots.Names()) != 0 {
+		t.Errorf("expected the missing session to be forgotten, got %+v", slots)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -368,6 +369,76 @@ func TestRunSessionObserve_NoURL(t *testing.T) {
 	}
 }
 
+func newObserveTestCmd(t *testing.T) *cobra.Command {
+	t.Helper()
+	origURL, origInstruct, origMin, origMax, origPerception := sessionObserveURL, sessionObserveInstruct, sessionObserveMinActions, sessionObserveMaxActions, sessionObservePerception
+	t.Cleanup(func() {
+		sessionObserveURL, sessionObserveInstruct, sessionObserveMinActions, sessionObserveMaxActions, sessionObservePerception = origURL, origInstruct, origMin, origMax, origPerception
+	})
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&sessionObserveURL, "url", "", "")
+	cmd.Flags().StringVar(&sessionObserveInstruct, "instructions", "", "")
+	cmd.Flags().IntVar(&sessionObserveMinActions, "min-actions", 0, "")
+	cmd.Flags().IntVar(&sessionObserveMaxActions, "max-actions", 0, "")
+	cmd.Flags().StringVar(&sessionObservePerception, "perception", "", "")
+	cmd.SetContext(context.Background())
+	return cmd
+}
+
+func TestRunSessionObserve_RequestOptions(t *testing.T) {
+	server := setupSessionTest(t)
+	observeResp := fmt.Sprintf(`{"metadata":{"tabs":[],"title":"Tab","url":"https://example.com"},"screenshot":{"raw":null},"session":%s,"space":{"category":"page","description":"desc","interaction_actions":[]}}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/observe", 200, observeResp)
+
+	cmd := newObserveTestCmd(t)
+	if err := cmd.ParseFlags([]string{"--instructions", "find the login form", "--max-actions", "5", "--perception", "deep"}); err != nil {
+		t.Fatal(err)
+	}
+
+	testutil.CaptureOutput(func() {
+		if err := runSessionObserve(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/page/observe")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	var body map[string]any
+	if err := json.Unmarshal([]byte(reqs[0].Body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["instructions"] != "find the login form" || body["max_nb_actions"] != float64(5) || body["perception_type"] != "deep" {
+		t.Errorf("unexpected request body: %v", body)
+	}
+	if body["min_nb_actions"] != nil || body["url"] != nil {
+		t.Errorf("expected unset options to be null, got %v", body)
+	}
+}
+
+func TestBuildObserveRequest_Invalid(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--perception", "slow"}, "expected fast|deep"},
+		{[]string{"--max-actions", "0"}, "--max-actions must be at least 1"},
+		{[]string{"--min-actions", "-1"}, "--min-actions must be at least 1"},
+		{[]string{"--min-actions", "6", "--max-actions", "5"}, "cannot exceed"},
+	}
+	for _, tt := range tests {
+		cmd := newObserveTestCmd(t)
+		if err := cmd.ParseFlags(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := buildObserveRequest(cmd); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: expected error containing %q, got %v", tt.args, tt.want, err)
+		}
+	}
+}
+
 func TestRunSessionExecute(t *testing.T) {
 	server := setupSessionTest(t)
 	execResp := fmt.Sprintf(`{"action":{"type":"noop"},"data":{},"message":"ok","session":%s,"success":true}`, sessionJSON())
@@ -1114,3 +1185,93 @@ func TestSessionStatus_UsesCurrentSession(t *testing.T) {
 		t.Error("expected output, got empty string")
 	}
 }
+
+func TestRunSessionsList_Table(t *testing.T) {
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	defer server.Close()
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	server.AddResponse("/sessions", 200, `{"items": [
+		{"session_id": "sess_old", "status": "ACTIVE", "browser_type": "chromium", "created_at": "2024-01-15T09:15:00Z"},
+		{"session_id": "sess_new", "status": "CLOSED", "browser_type": "chrome", "created_at": "2024-01-15T10:30:00Z"}
+	]}`)
+
+	origFormat, origColumns, origSort := outputFormat, listColumns, listSortBy
+	t.Cleanup(func() { outputFormat, listColumns, listSortBy = origFormat, origColumns, origSort })
+	outputFormat = "text"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionsList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ID") || !strings.Contains(lines[0], "BROWSER") || !strings.Contains(lines[1], "2024-01-15 09:15:00") {
+		t.Errorf("expected default table, got %q", stdout)
+	}
+
+	listColumns = []string{"id", "status"}
+	listSortBy = "-created_at"
+	stdout, _ = testutil.CaptureOutput(func() {
+		if err := runSessionsList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	lines = strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 || strings.Contains(lines[0], "BROWSER") || !strings.HasPrefix(lines[1], "sess_new") {
+		t.Errorf("expected selected columns sorted newest first, got %q", stdout)
+	}
+
+	listColumns = []string{"bogus"}
+	var err error
+	testutil.CaptureOutput(func() {
+		err = runSessionsList(cmd, nil)
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown column "bogus"`) {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+}
+
+func TestRunSessionsList_Filters(t *testing.T) {
+	server := setupSessionTest(t)
+	resetListFilterFlags(t)
+	server.AddResponse("/sessions", 200, `{"items": [
+		{"session_id": "sess_ff", "status": "active", "browser_type": "firefox", "created_at": "2999-01-01T00:00:00Z"},
+		{"session_id": "sess_cr", "status": "active", "browser_type": "chromium", "created_at": "2999-01-01T00:00:00Z"},
+		{"session_id": "sess_old", "status": "active", "browser_type": "firefox", "created_at": "2000-01-01T00:00:00Z"}
+	]}`)
+
+	origFormat := outputFormat
+	t.Cleanup(func() { outputFormat = origFormat })
+	outputFormat = "json"
+
+	listStatus = []string{"active"}
+	listCurrentToken = true
+	listBrowser = []string{"firefox"}
+	listSince = "2h"
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionsList(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+	if !strings.Contains(stdout, "sess_ff") || strings.Contains(stdout, "sess_cr") || strings.Contains(stdout, "sess_old") {
+		t.Errorf("expected only the recent firefox session, got %q", stdout)
+	}
+
+	reqs := server.Requests("/sessions")
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if reqs[0].Query.Get("only_active") != "true" || reqs[0].Query.Get("only_current_token") != "true" {
+		t.Errorf("expected server-side filters, got %v", reqs[0].Query)
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
+)
+
+var sessionsWithCmd = &cobra.Command{
+	Use:   "with [start flags] -- <command> [args...]",
+	Short: "Run a local command with a session that is stopped afterwards",
+	Long: `Start a session, run a local command with NOTTE_SESSION_ID set to it, and
+stop the session when the command exits, whether it succeeds or fails.
+
+Interrupt and termination signals are forwarded to the command. The exit
+code of the command becomes the exit code of notte. The session does not
+replace the current session.`,
+	Example: `  notte sessions with -- ./scrape.sh
+  notte sessions with --browser firefox --proxies -- python checkout.py --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSessionsWith,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsWithCmd)
+
+	addSessionStartFlags(sessionsWithCmd)
+	// Everything after the command name belongs to the command
+	sessionsWithCmd.Flags().SetInterspersed(false)
+}
+
+func runSessionsWith(cmd *cobra.Command, args []string) error {
+	path, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("command not found: %w", err)
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	session, err := startSession(cmd)
+	if err != nil {
+		return err
+	}
+	if session == nil {
+		return errors.New("empty response from API")
+	}
+	id := session.SessionId
+	_, _ = fmt.Fprintf(os.Stderr, "Started session %s\n", id)
+
+	runErr := runWithSession(cmd, path, args, id)
+
+	// Stop the session even if the command was interrupted
+	_, stopErr := stopSession(context.WithoutCancel(cmd.Context()), client, id)
+	if stopErr != nil {
+		stopErr = fmt.Errorf("failed to stop session %s: %w", id, stopErr)
+		if runErr != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Warning: %v\n", stopErr)
+			return runErr
+		}
+		return stopErr
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Stopped session %s\n", id)
+	return runErr
+}
+
+// runWithSession runs the command with the session in its environment,
+// forwarding signals until it exits. A non-zero exit is returned as an
+// ExitError with the same code.
+func runWithSession(cmd *cobra.Command, path string, args []string, id string) error {
+	child := exec.Command(path, args[1:]...)
+	child.Stdin = cmd.InOrStdin()
+	child.Stdout = cmd.OutOrStdout()
+	child.Stderr = cmd.ErrOrStderr()
+	child.Env = append(os.Environ(), config.EnvSessionID+"="+id)
+	// Nested notte commands should use the same context as this one
+	if contextName != "" {
+		child.Env = append(child.Env, config.EnvContext+"="+contextName)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	if err := child.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", args[0], err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- child.Wait() }()
+
+	for {
+		select {
+		case sig := <-signals:
+			_ = child.Process.Signal(sig)
+		case err := <-done:
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code := exitErr.ExitCode()
+				if code < 0 {
+					// Killed by a signal
+					code = 1
+				}
+				return &notteErrors.ExitError{
+					Code: code,
+					Err:  fmt.Errorf("%s exited with status %d", args[0], code),
+				}
+			}
+			return err
+		}
+	}
+}
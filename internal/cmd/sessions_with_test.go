@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/config"
+	notteErrors "github.com/salmonumbrella/notte-cli/internal/errors"
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func setupSessionsWithTest(t *testing.T) *testutil.MockServer {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+	config.SetTestConfigDir(t.TempDir())
+	t.Cleanup(func() { config.SetTestConfigDir("") })
+
+	server := testutil.NewMockServer()
+	t.Cleanup(server.Close)
+	env.SetEnv("NOTTE_API_URL", server.URL())
+
+	server.AddResponse("/sessions/start", 200, `{"session_id": "sess_with", "status": "active"}`)
+	server.AddResponse("/sessions/sess_with/stop", 200, `{"session_id": "sess_with", "status": "closed"}`)
+	return server
+}
+
+func runSessionsWithArgs(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	addSessionStartFlags(cmd)
+	cmd.SetContext(context.Background())
+
+	var err error
+	stdout, _ := testutil.CaptureOutput(func() {
+		err = runSessionsWith(cmd, args)
+	})
+	return stdout, err
+}
+
+func TestRunSessionsWith_SetsSessionAndStops(t *testing.T) {
+	server := setupSessionsWithTest(t)
+	out := filepath.Join(t.TempDir(), "out")
+
+	_, err := runSessionsWithArgs(t, "sh", "-c", `echo "$NOTTE_SESSION_ID" > "$0"`, out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(string(data)) != "sess_with" {
+		t.Errorf("expected NOTTE_SESSION_ID=sess_with in the child, got %q", data)
+	}
+	if n := len(server.Requests("/sessions/sess_with/stop")); n != 1 {
+		t.Errorf("expected the session to be stopped once, got %d", n)
+	}
+	if got := getCurrentSessionID(); got != "" {
+		t.Errorf("expected the current session to be left alone, got %q", got)
+	}
+}
+
+func TestRunSessionsWith_PropagatesExitCode(t *testing.T) {
+	server := setupSessionsWithTest(t)
+
+	_, err := runSessionsWithArgs(t, "sh", "-c", "exit 3")
+	if code := notteErrors.ExitCode(err); code != 3 {
+		t.Errorf("expected exit code 3, got %d (%v)", code, err)
+	}
+	if n := len(server.Requests("/sessions/sess_with/stop")); n != 1 {
+		t.Errorf("expected the session to be stopped after a failure, got %d stop requests", n)
+	}
+}
+
+func TestRunSessionsWith_UnknownCommand(t *testing.T) {
+	server := setupSessionsWithTest(t)
+
+	if _, err := runSessionsWithArgs(t, "notte-no-such-command"); err == nil || !strings.Contains(err.Error(), "command not found") {
+		t.Errorf("expected command not found, got %v", err)
+	}
+	if n := len(server.Requests("/sessions/start")); n != 0 {
+		t.Errorf("expected no session to be started, got %d", n)
+	}
+}
@@ -0,0 +1,609 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
+)
+
+var sessionsShellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Interactive shell for driving a session",
+	Long: `Attach to a session and drive it with short commands instead of full
+action JSON. Type "help" inside the shell for the list of commands.
+
+Action IDs from the last "observe" are offered for tab completion, and
+":save <file>" writes the successful actions as a file for "notte run".`,
+	Example: `  notte sessions shell
+  notte> goto https://example.com
+  notte> observe
+  notte> click B3
+  notte> fill I2 "hello world"
+  notte> :save flow.yaml`,
+	Args: cobra.NoArgs,
+	RunE: runSessionShell,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsShellCmd)
+
+	addSessionIDFlags(sessionsShellCmd)
+}
+
+// shellPrompt is shown before each command
+const shellPrompt = "notte> "
+
+// shellAction describes a shell command that maps to a single page action
+type shellAction struct {
+	usage   string
+	minArgs int
+	maxArgs int
+	// completeIDs enables tab completion of action IDs for the first argument
+	completeIDs bool
+	build       func(args []string) (map[string]any, error)
+}
+
+var shellActions = map[string]shellAction{
+	"goto": {
+		usage: "goto <url>", minArgs: 1, maxArgs: 1,
+		build: func(a []string) (map[string]any, error) {
+			return map[string]any{"type": "goto", "url": a[0]}, nil
+		},
+	},
+	"new-tab": {
+		usage: "new-tab <url>", minArgs: 1, maxArgs: 1,
+		build: func(a []string) (map[string]any, error) {
+			return map[string]any{"type": "goto_new_tab", "url": a[0]}, nil
+		},
+	},
+	"click": {
+		usage: "click <id>", minArgs: 1, maxArgs: 1, completeIDs: true,
+		build: func(a []string) (map[string]any, error) {
+			return map[string]any{"type": "click", "id": a[0]}, nil
+		},
+	},
+	"fill": {
+		usage: "fill <id> <value>", minArgs: 2, maxArgs: 2, completeIDs: true,
+		build: func(a []string) (map[string]any, error) {
+			return map[string]any{"type": "fill", "id": a[0], "value": a[1]}, nil
+		},
+	},
+	"check": {
+		usage: "check <id> [true|false]", minArgs: 1, maxArgs: 2, completeIDs: true,
+		build: func(a []string) (map[string]any, error) {
+			value := true
+			if len(a) == 2 {
+				v, err := strconv.ParseBool(a[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid check value %q: expected true or false", a[1])
+				}
+				value = v
+			}
+			return map[string]any{"type": "check", "id": a[0], "value": value}, nil
+		},
+	},
+	"select": {
+		usage: "select <id> <option>", minArgs: 2, maxArgs: 2, completeIDs: true,
+		build: func(a []string) (map[string]any, error) {
+			return map[string]any{"type": "select_dropdown_option", "id": a[0], "value": a[1]}, nil
+		},
+	},
+	"press": {
+		usage: "press <key>", minArgs: 1, maxArgs: 1,
+		build: func(a []string) (map[string]any, error) {
+			return map[string]any{"type": "press_key", "key": a[0]}, nil
+		},
+	},
+	"scroll": {
+		usage: "scroll [up|down] [amount]", minArgs: 0, maxArgs: 2,
+		build: func(a []string) (map[string]any, error) {
+			action := map[string]any{"type": "scroll_down"}
+			if len(a) > 0 {
+				switch a[0] {
+				case "up":
+					action["type"] = "scroll_up"
+				case "down":
+				default:
+					return nil, fmt.Errorf("invalid scroll direction %q: expected up or down", a[0])
+				}
+			}
+			if len(a) == 2 {
+				amount, err := strconv.Atoi(a[1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid scroll amount %q", a[1])
+				}
+				action["amount"] = amount
+			}
+			return action, nil
+		},
+	},
+	"wait": {
+		usage: "wait <ms>", minArgs: 1, maxArgs: 1,
+		build: func(a []string) (map[string]any, error) {
+			ms, err := strconv.Atoi(a[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid wait time %q: expected milliseconds", a[0])
+			}
+			return map[string]any{"type": "wait", "time_ms": ms}, nil
+		},
+	},
+	"back": {
+		usage: "back", maxArgs: 0,
+		build: func([]string) (map[string]any, error) { return map[string]any{"type": "go_back"}, nil },
+	},
+	"forward": {
+		usage: "forward", maxArgs: 0,
+		build: func([]string) (map[string]any, error) { return map[string]any{"type": "go_forward"}, nil },
+	},
+	"reload": {
+		usage: "reload", maxArgs: 0,
+		build: func([]string) (map[string]any, error) { return map[string]any{"type": "reload"}, nil },
+	},
+	"scrape": {
+		usage: "scrape [instructions]", minArgs: 0, maxArgs: 1,
+		build: func(a []string) (map[string]any, error) {
+			action := map[string]any{"type": "scrape"}
+			if len(a) == 1 {
+				action["instructions"] = a[0]
+			}
+			return action, nil
+		},
+	},
+}
+
+// shellBuiltins are commands handled by the shell itself
+var shellBuiltins = []string{"observe", "help", ":save", ":quit", ":exit"}
+
+// sessionShell holds the state of an interactive session shell
+type sessionShell struct {
+	ctx       context.Context
+	client    *api.NotteClient
+	renderer  *templateRenderer
+	sessionID string
+	out       io.Writer
+
+	// transcript holds successfully executed actions, in order
+	transcript []map[string]any
+	// actionIDs holds interaction action IDs from the last observe
+	actionIDs []string
+}
+
+func runSessionShell(cmd *cobra.Command, args []string) error {
+	if err := requireSessionID(); err != nil {
+		return err
+	}
+
+	client, err := GetClient()
+	if err != nil {
+		return err
+	}
+
+	sh := &sessionShell{
+		ctx:       cmd.Context(),
+		client:    client,
+		renderer:  newTemplateRenderer(cmd.Context(), client),
+		sessionID: sessionID,
+	}
+
+	if err := sh.attach(); err != nil {
+		return err
+	}
+
+	if f, ok := cmd.InOrStdin().(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		return sh.runInteractive(f)
+	}
+
+	sh.out = os.Stdout
+	return sh.runScript(cmd.InOrStdin())
+}
+
+// attach verifies that the session exists and is usable
+func (sh *sessionShell) attach() error {
+	ctx, cancel := GetContextWithTimeout(sh.ctx)
+	defer cancel()
+
+	params := &api.SessionStatusParams{}
+	resp, err := sh.client.Client().SessionStatusWithResponse(ctx, sh.sessionID, params)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return err
+	}
+
+	status := "unknown"
+	if resp.JSON200 != nil {
+		status = string(resp.JSON200.Status)
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Attached to session %s (%s). Type \"help\" for commands.\n", sh.sessionID, status)
+	return nil
+}
+
+// runInteractive reads commands from a terminal with history and completion
+func (sh *sessionShell) runInteractive(f *os.File) error {
+	fd := int(f.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw terminal mode: %w", err)
+	}
+	defer func() { _ = term.Restore(fd, state) }()
+
+	screen := struct {
+		io.Reader
+		io.Writer
+	}{f, os.Stdout}
+	t := term.NewTerminal(screen, shellPrompt)
+	t.AutoCompleteCallback = sh.complete
+	sh.out = t
+
+	for {
+		line, err := t.ReadLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if quit := sh.handleLine(line); quit {
+			return nil
+		}
+	}
+}
+
+// runScript reads commands line by line from non-interactive input
+func (sh *sessionShell) runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if quit := sh.handleLine(scanner.Text()); quit {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// handleLine runs a single shell command. It returns true when the shell
+// should exit. Command errors are reported and do not end the shell.
+func (sh *sessionShell) handleLine(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return false
+	}
+
+	if err := sh.dispatch(line); err != nil {
+		if errors.Is(err, errShellQuit) {
+			return true
+		}
+		_, _ = fmt.Fprintf(sh.out, "Error: %s\n", sh.renderer.redact(err.Error()))
+	}
+	return false
+}
+
+var errShellQuit = errors.New("quit")
+
+func (sh *sessionShell) dispatch(line string) error {
+	// Raw action JSON is passed through untouched
+	if strings.HasPrefix(line, "{") {
+		var action map[string]any
+		if err := json.Unmarshal([]byte(line), &action); err != nil {
+			return fmt.Errorf("invalid action JSON: %w", err)
+		}
+		return sh.execute(action)
+	}
+
+	words, err := splitShellWords(line)
+	if err != nil {
+		return err
+	}
+	name, args := words[0], words[1:]
+
+	switch name {
+	case ":quit", ":exit", "exit", "quit":
+		return errShellQuit
+	case "help", ":help":
+		sh.printHelp()
+		return nil
+	case ":save":
+		if len(args) != 1 {
+			return errors.New("usage: :save <file>")
+		}
+		return sh.save(args[0])
+	case "observe":
+		if len(args) > 1 {
+			return errors.New("usage: observe [url]")
+		}
+		return sh.observe(args)
+	}
+
+	spec, ok := shellActions[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q (type \"help\" for commands)", name)
+	}
+	if len(args) < spec.minArgs || len(args) > spec.maxArgs {
+		return fmt.Errorf("usage: %s", spec.usage)
+	}
+	action, err := spec.build(args)
+	if err != nil {
+		return err
+	}
+	return sh.execute(action)
+}
+
+// execute sends an action to the session and records it on success
+func (sh *sessionShell) execute(action map[string]any) error {
+	rendered, err := sh.renderer.renderValue(action)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(rendered)
+	if err != nil {
+		return fmt.Errorf("invalid action: %w", err)
+	}
+
+	ctx, cancel := GetContextWithTimeout(sh.ctx)
+	defer cancel()
+
+	params := &api.PageExecuteParams{}
+	resp, err := sh.client.Client().PageExecuteWithBodyWithResponse(ctx, sh.sessionID, params, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return errors.New("empty response from API")
+	}
+
+	result := resp.JSON200
+	if !result.Success {
+		msg := result.Message
+		if result.Exception != nil {
+			msg = *result.Exception
+		}
+		return fmt.Errorf("action failed: %s", msg)
+	}
+
+	// Record the unrendered action so secrets never reach the transcript
+	sh.transcript = append(sh.transcript, action)
+
+	_, _ = fmt.Fprintf(sh.out, "ok: %s\n", sh.renderer.redact(result.Message))
+	if result.Data != nil {
+		return sh.print(sh.renderer.redactValue(result.Data))
+	}
+	return nil
+}
+
+// observe prints the page's action space and remembers its action IDs
+func (sh *sessionShell) observe(args []string) error {
+	body := api.PageObserveJSONRequestBody{}
+	if len(args) == 1 {
+		body.Url = &args[0]
+	}
+
+	ctx, cancel := GetContextWithTimeout(sh.ctx)
+	defer cancel()
+
+	params := &api.PageObserveParams{}
+	resp, err := sh.client.Client().PageObserveWithResponse(ctx, sh.sessionID, params, body)
+	if err != nil {
+		return fmt.Errorf("API request failed: %w", err)
+	}
+	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+		return err
+	}
+	if resp.JSON200 == nil {
+		return errors.New("empty response from API")
+	}
+
+	obs := resp.JSON200
+	_, _ = fmt.Fprintf(sh.out, "%s\n%s\n\n", obs.Metadata.Title, obs.Metadata.Url)
+
+	sh.actionIDs = sh.actionIDs[:0]
+	for _, a := range interactionActions(obs.Space) {
+		sh.actionIDs = append(sh.actionIDs, a.ID)
+		_, _ = fmt.Fprintf(sh.out, "  %-6s %s\n", a.ID, a.Description)
+	}
+	if len(sh.actionIDs) == 0 {
+		_, _ = fmt.Fprintln(sh.out, "  (no interaction actions)")
+	}
+	return nil
+}
+
+// save writes the transcript as an action file for `notte run`
+func (sh *sessionShell) save(path string) error {
+	if len(sh.transcript) == 0 {
+		return errors.New("nothing to save: no actions have succeeded yet")
+	}
+
+	doc := map[string]any{"steps": sh.transcript}
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(doc)
+	default:
+		data, err = json.MarshalIndent(doc, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %q: %w", path, err)
+	}
+	_, _ = fmt.Fprintf(sh.out, "Saved %d actions to %s\n", len(sh.transcript), path)
+	return nil
+}
+
+// print writes a value using the configured output format
+func (sh *sessionShell) print(v any) error {
+	f := output.NewFormatter(output.Format(outputFormat), sh.out)
+	if tf, ok := f.(*output.TextFormatter); ok {
+		tf.NoColor = noColor
+	}
+	return f.Print(v)
+}
+
+func (sh *sessionShell) printHelp() {
+	names := make([]string, 0, len(shellActions))
+	for name := range shellActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	_, _ = fmt.Fprintln(sh.out, "Actions:")
+	for _, name := range names {
+		_, _ = fmt.Fprintf(sh.out, "  %s\n", shellActions[name].usage)
+	}
+	_, _ = fmt.Fprintln(sh.out, "  {...}                 raw action JSON")
+	_, _ = fmt.Fprintln(sh.out, "Shell:")
+	_, _ = fmt.Fprintln(sh.out, "  observe [url]         list interaction actions on the page")
+	_, _ = fmt.Fprintln(sh.out, "  :save <file>          save successful actions as a run file")
+	_, _ = fmt.Fprintln(sh.out, "  :quit                 leave the shell")
+}
+
+// complete implements tab completion for commands and action IDs
+func (sh *sessionShell) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	prefix := line[:pos]
+	words := strings.Fields(prefix)
+	completingNew := strings.HasSuffix(prefix, " ")
+
+	var candidates []string
+	var partial string
+
+	switch {
+	case len(words) == 0 || (len(words) == 1 && !completingNew):
+		if len(words) == 1 {
+			partial = words[0]
+		}
+		for name := range shellActions {
+			candidates = append(candidates, name)
+		}
+		candidates = append(candidates, shellBuiltins...)
+	case shellActions[words[0]].completeIDs && (len(words) == 1 || (len(words) == 2 && !completingNew)):
+		if len(words) == 2 {
+			partial = words[1]
+		}
+		candidates = sh.actionIDs
+	default:
+		return "", 0, false
+	}
+
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, partial) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += " "
+	}
+	if len(completion) <= len(partial) {
+		return "", 0, false
+	}
+
+	newPrefix := prefix[:len(prefix)-len(partial)] + completion
+	return newPrefix + line[pos:], len(newPrefix), true
+}
+
+// commonPrefix returns the longest prefix shared by all strings
+func commonPrefix(items []string) string {
+	if len(items) == 0 {
+		return ""
+	}
+	prefix := items[0]
+	for _, s := range items[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// splitShellWords splits a command line into words, honoring single and
+// double quotes and backslash escapes inside double quotes.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote rune
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' && i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, errors.New("unterminated quote")
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	if len(words) == 0 {
+		return nil, errors.New("empty command")
+	}
+	return words, nil
+}
+
+// unionFields decodes a generated union type into its JSON fields
+func unionFields(v json.Marshaler) map[string]any {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestShell(t *testing.T) (*sessionShell, *bytes.Buffer) {
+	t.Helper()
+	client, err := GetClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	return &sessionShell{
+		ctx:       context.Background(),
+		client:    client,
+		renderer:  newTemplateRenderer(context.Background(), client),
+		sessionID: sessionIDTest,
+		out:       &out,
+	}, &out
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"click B3", []string{"click", "B3"}},
+		{`fill I2 "hello world"`, []string{"fill", "I2", "hello world"}},
+		{`fill I2 'it''s'`, []string{"fill", "I2", "its"}},
+		{`fill I2 "say \"hi\""`, []string{"fill", "I2", `say "hi"`}},
+		{`fill I2 ""`, []string{"fill", "I2", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitShellWords(tt.line)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.line, err)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) || len(got) != len(tt.want) {
+			t.Errorf("%q: got %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := splitShellWords(`fill I2 "oops`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+}
+
+func TestSessionShell_Complete(t *testing.T) {
+	sh := &sessionShell{actionIDs: []string{"B1", "B2", "I1"}}
+
+	line, pos, ok := sh.complete("cl", 2, '\t')
+	if !ok || line != "click " || pos != 6 {
+		t.Errorf("command completion: got %q %d %v", line, pos, ok)
+	}
+
+	line, _, ok = sh.complete("click I", 7, '\t')
+	if !ok || line != "click I1 " {
+		t.Errorf("id completion: got %q %v", line, ok)
+	}
+
+	line, _, ok = sh.complete("click ", 6, '\t')
+	if ok {
+		t.Errorf("expected no completion without a common prefix, got %q", line)
+	}
+
+	if _, _, ok := sh.complete("goto ex", 7, '\t'); ok {
+		t.Error("goto arguments should not complete action IDs")
+	}
+}
+
+func TestSessionShell_ObserveExecuteAndSave(t *testing.T) {
+	server := setupSessionTest(t)
+	observeResp := fmt.Sprintf(`{"metadata":{"tabs":[],"title":"Example","url":"https://example.com"},"screenshot":{"raw":""},"session":%s,"space":{"category":"page","description":"desc","interaction_actions":[{"type":"click","id":"B1","description":"Submit"},{"type":"fill","id":"I1","description":"Email"}]}}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/observe", 200, observeResp)
+	execResp := fmt.Sprintf(`{"action":{"type":"click"},"data":null,"message":"clicked","session":%s,"success":true}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200, execResp)
+
+	sh, out := newTestShell(t)
+	path := filepath.Join(t.TempDir(), "flow.yaml")
+
+	script := strings.Join([]string{
+		"observe",
+		"click B1",
+		`fill I1 "a b"`,
+		"bogus",
+		":save " + path,
+		":quit",
+		"click B1",
+	}, "\n")
+	if err := sh.runScript(strings.NewReader(script)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if fmt.Sprint(sh.actionIDs) != "[B1 I1]" {
+		t.Errorf("expected observed IDs to be stored, got %v", sh.actionIDs)
+	}
+	if !strings.Contains(out.String(), "Submit") || !strings.Contains(out.String(), `unknown command "bogus"`) {
+		t.Errorf("unexpected shell output: %s", out.String())
+	}
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/page/execute")
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 execute requests (quit stops the shell), got %d", len(reqs))
+	}
+	if !strings.Contains(reqs[1].Body, `"value":"a b"`) {
+		t.Errorf("unexpected fill body: %s", reqs[1].Body)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected transcript to be saved: %v", err)
+	}
+	file, err := parseActionFile(data)
+	if err != nil {
+		t.Fatalf("saved transcript is not a valid action file: %v", err)
+	}
+	if len(file.Steps) != 2 || file.Steps[1].actionType() != "fill" {
+		t.Errorf("unexpected saved steps: %+v", file.Steps)
+	}
+}
+
+func TestSessionShell_FailedActionNotRecorded(t *testing.T) {
+	server := setupSessionTest(t)
+	execResp := fmt.Sprintf(`{"action":{"type":"click"},"data":null,"exception":"element not found","message":"failed","session":%s,"success":false}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200, execResp)
+
+	sh, out := newTestShell(t)
+	sh.handleLine("click B9")
+
+	if len(sh.transcript) != 0 {
+		t.Errorf("failed actions should not be recorded, got %v", sh.transcript)
+	}
+	if !strings.Contains(out.String(), "element not found") {
+		t.Errorf("expected failure to be reported, got %q", out.String())
+	}
+	if err := sh.save(filepath.Join(t.TempDir(), "x.json")); err == nil {
+		t.Error("expected error saving an empty transcript")
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+// redactedValue replaces resolved secrets in any user-visible output
+const redactedValue = "********"
+
+// vaultFields maps template field names to credential getters
+var vaultFields = map[string]func(c api.CredentialsDictOutput) *string{
+	"password":   func(c api.CredentialsDictOutput) *string { return &c.Password },
+	"username":   func(c api.CredentialsDictOutput) *string { return c.Username },
+	"email":      func(c api.CredentialsDictOutput) *string { return c.Email },
+	"mfa_secret": func(c api.CredentialsDictOutput) *string { return c.MfaSecret },
+}
+
+// templateRenderer resolves {{ ... }} expressions in action payloads.
+//
+// Supported expressions:
+//
+//	{{ env.NAME }}                         environment variable
+//	{{ vault "vault_id" "url" "password" }} vault credential field (secret)
+//	{{ steps.login.result.data }}          output of a prior step in a run
+//	{{ steps.login.result.data | json }}   same, JSON-encoded
+//
+// Every value resolved from a vault is remembered so it can be redacted
+// from verbose output and reports.
+type templateRenderer struct {
+	ctx     context.Context
+	client  *api.NotteClient
+	steps   map[string]any
+	secrets []string
+	vaults  map[string]api.CredentialsDictOutput
+}
+
+// newTemplateRenderer creates a renderer that uses client for vault lookups
+func newTemplateRenderer(ctx context.Context, client *api.NotteClient) *templateRenderer {
+	return &templateRenderer{
+		ctx:    ctx,
+		client: client,
+		steps:  make(map[string]any),
+		vaults: make(map[string]api.CredentialsDictOutput),
+	}
+}
+
+// recordStep makes a step's outcome available as {{ steps.<name>... }}
+func (r *templateRenderer) recordStep(name string, result runStepResult) {
+	if name == "" {
+		return
+	}
+	r.steps[name] = map[string]any{
+		"status": result.Status,
+		"result": map[string]any{
+			"success": result.Status == stepStatusOK,
+			"message": result.Message,
+			"data":    result.Data,
+		},
+	}
+}
+
+// render evaluates template expressions in a single string
+func (r *templateRenderer) render(s string) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+
+	tmpl, err := template.New("action").
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"env":   envMap,
+			"steps": func() map[string]any { return r.steps },
+			"vault": r.vaultValue,
+			"json":  toJSONString,
+		}).
+		Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", s, err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, nil); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", s, err)
+	}
+	return b.String(), nil
+}
+
+// renderValue walks a decoded JSON value and renders every string in it
+func (r *templateRenderer) renderValue(v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		return r.render(val)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			rendered, err := r.renderValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			rendered, err := r.renderValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// renderJSON renders template expressions inside a raw JSON payload
+func (r *templateRenderer) renderJSON(data []byte) ([]byte, error) {
+	if !strings.Contains(string(data), "{{") {
+		return data, nil
+	}
+
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	rendered, err := r.renderValue(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rendered)
+}
+
+// vaultValue resolves a credential field for a URL from a vault
+func (r *templateRenderer) vaultValue(vaultID, url, field string) (string, error) {
+	getter, ok := vaultFields[field]
+	if !ok {
+		return "", fmt.Errorf("unknown vault field %q (expected password, username, email or mfa_secret)", field)
+	}
+
+	key := vaultID + "\x00" + url
+	creds, ok := r.vaults[key]
+	if !ok {
+		if r.client == nil {
+			return "", errors.New("vault lookups require an API client")
+		}
+
+		ctx, cancel := GetContextWithTimeout(r.ctx)
+		defer cancel()
+
+		params := &api.VaultCredentialsGetParams{Url: url}
+		resp, err := r.client.Client().VaultCredentialsGetWithResponse(ctx, vaultID, params)
+		if err != nil {
+			return "", fmt.Errorf("API request failed: %w", err)
+		}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return "", err
+		}
+		if resp.JSON200 == nil {
+			return "", fmt.Errorf("no credentials in vault %s for %s", vaultID, url)
+		}
+		creds = resp.JSON200.Credentials
+		r.vaults[key] = creds
+	}
+
+	value := getter(creds)
+	if value == nil || *value == "" {
+		return "", fmt.Errorf("vault %s has no %s for %s", vaultID, field, url)
+	}
+	if !slices.Contains(r.secrets, *value) {
+		r.secrets = append(r.secrets, *value)
+	}
+	return *value, nil
+}
+
+// hasSecrets reports whether any vault values have been resolved
+func (r *templateRenderer) hasSecrets() bool {
+	return len(r.secrets) > 0
+}
+
+// redact replaces every resolved secret in s
+func (r *templateRenderer) redact(s string) string {
+	for _, secret := range r.secrets {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+		}
+	}
+	return s
+}
+
+// redactValue returns a copy of a decoded JSON value with secrets replaced
+func (r *templateRenderer) redactValue(v any) any {
+	if !r.hasSecrets() {
+		return v
+	}
+	switch val := v.(type) {
+	case string:
+		return r.redact(val)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = r.redactValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// redactAny redacts secrets from an arbitrary value by round-tripping it
+// through JSON. Values that cannot be encoded are returned unchanged.
+func (r *templateRenderer) redactAny(v any) any {
+	if !r.hasSecrets() {
+		return v
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return v
+	}
+	return r.redactValue(generic)
+}
+
+// envMap exposes the process environment to templates
+func envMap() map[string]string {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return env
+}
+
+// toJSONString encodes a template value as compact JSON
+func toJSONString(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
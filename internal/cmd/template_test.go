@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+func TestTemplateRenderer_Env(t *testing.T) {
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_TEST_SEARCH", "golang")
+
+	r := newTemplateRenderer(context.Background(), nil)
+	got, err := r.render("https://example.com/?q={{ env.NOTTE_TEST_SEARCH }}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "https://example.com/?q=golang" {
+		t.Errorf("got %q", got)
+	}
+
+	if _, err := r.render("{{ env.NOTTE_TEST_DOES_NOT_EXIST }}"); err == nil {
+		t.Error("expected error for missing env var")
+	}
+}
+
+func TestTemplateRenderer_Steps(t *testing.T) {
+	r := newTemplateRenderer(context.Background(), nil)
+	r.recordStep("login", runStepResult{
+		Status: stepStatusOK,
+		Data:   map[string]any{"token": "abc"},
+	})
+
+	got, err := r.render("{{ steps.login.result.data.token }}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "abc" {
+		t.Errorf("got %q, want abc", got)
+	}
+
+	got, err = r.render("{{ steps.login.result.data | json }}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != `{"token":"abc"}` {
+		t.Errorf("got %q", got)
+	}
+
+	if _, err := r.render("{{ steps.missing.result.data }}"); err == nil {
+		t.Error("expected error for unknown step")
+	}
+}
+
+func TestTemplateRenderer_RenderValue(t *testing.T) {
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_TEST_USER", "alice")
+
+	r := newTemplateRenderer(context.Background(), nil)
+	got, err := r.renderValue(map[string]any{
+		"type":  "fill",
+		"value": "{{ env.NOTTE_TEST_USER }}",
+		"list":  []any{"{{ env.NOTTE_TEST_USER }}", 3.0},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	m := got.(map[string]any)
+	if m["value"] != "alice" || m["list"].([]any)[0] != "alice" || m["list"].([]any)[1] != 3.0 {
+		t.Errorf("unexpected render result: %#v", m)
+	}
+}
+
+func TestTemplateRenderer_VaultIsRedacted(t *testing.T) {
+	env := testutil.SetupTestEnv(t)
+	env.SetEnv("NOTTE_API_KEY", "test-key")
+
+	server := testutil.NewMockServer()
+	t.Cleanup(func() { server.Close() })
+	env.SetEnv("NOTTE_API_URL", server.URL())
+	server.AddResponse("/vaults/vault_1/credentials", 200, `{"credentials":{"password":"hunter2","username":"alice"}}`)
+
+	client, err := GetClient()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	r := newTemplateRenderer(context.Background(), client)
+	got, err := r.render(`{{ vault "vault_1" "https://example.com" "password" }}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "hunter2" {
+		t.Errorf("got %q, want hunter2", got)
+	}
+
+	// Second lookup for the same URL is served from cache
+	if _, err := r.render(`{{ vault "vault_1" "https://example.com" "username" }}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(server.Requests("/vaults/vault_1/credentials")); n != 1 {
+		t.Errorf("expected 1 vault request, got %d", n)
+	}
+
+	// Repeated lookups remember each secret once
+	for range 3 {
+		if _, err := r.render(`{{ vault "vault_1" "https://example.com" "password" }}`); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(r.secrets) != 2 {
+		t.Errorf("expected 2 remembered secrets, got %d", len(r.secrets))
+	}
+
+	if redacted := r.redact("typed hunter2 into the form"); strings.Contains(redacted, "hunter2") {
+		t.Errorf("expected secret to be redacted, got %q", redacted)
+	}
+	data := r.redactValue(map[string]any{"echo": []any{"hunter2"}})
+	if encoded, _ := json.Marshal(data); strings.Contains(string(encoded), "hunter2") {
+		t.Errorf("expected secret to be redacted, got %s", encoded)
+	}
+
+	if _, err := r.render(`{{ vault "vault_1" "https://example.com" "pin" }}`); err == nil {
+		t.Error("expected error for unknown vault field")
+	}
+}
+
+func TestRunActionFile_TemplatesAndRedaction(t *testing.T) {
+	server := setupSessionTest(t)
+	resetRunFlags(t)
+	server.AddResponse("/vaults/vault_1/credentials", 200, `{"credentials":{"password":"hunter2"}}`)
+	execResp := fmt.Sprintf(`{"action":{"type":"fill"},"data":{"echo":"hunter2"},"message":"filled hunter2","session":%s,"success":true}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200, execResp)
+
+	path := writeActionFile(t, "flow.yaml", `
+steps:
+  - name: login
+    type: fill
+    id: I1
+    value: '{{ vault "vault_1" "https://example.com" "password" }}'
+  - type: fill
+    id: I2
+    value: '{{ steps.login.status }}'
+`)
+
+	origFormat := outputFormat
+	origVerbose := verbose
+	outputFormat = "json"
+	verbose = true
+	t.Cleanup(func() {
+		outputFormat = origFormat
+		verbose = origVerbose
+	})
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, stderr := testutil.CaptureOutput(func() {
+		if err := runActionFile(cmd, []string{path}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/page/execute")
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 execute requests, got %d", len(reqs))
+	}
+	if !strings.Contains(reqs[0].Body, `"value":"hunter2"`) {
+		t.Errorf("expected secret to be sent to the API, got %s", reqs[0].Body)
+	}
+	if !strings.Contains(reqs[1].Body, `"value":"ok"`) {
+		t.Errorf("expected step output to be interpolated, got %s", reqs[1].Body)
+	}
+
+	if strings.Contains(stdout, "hunter2") {
+		t.Errorf("secret leaked into report: %s", stdout)
+	}
+	if strings.Contains(stderr, "hunter2") {
+		t.Errorf("secret leaked into verbose output: %s", stderr)
+	}
+	if !strings.Contains(stderr, redactedValue) {
+		t.Errorf("expected redacted payload in verbose output, got %q", stderr)
+	}
+}
+
+func TestRunSessionExecute_RedactsVaultSecrets(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/vaults/vault_1/credentials", 200, `{"credentials":{"password":"hunter2"}}`)
+	execResp := fmt.Sprintf(`{"action":{"type":"fill","value":"hunter2"},"data":{},"message":"ok","session":%s,"success":true}`, sessionJSON())
+	server.AddResponse("/sessions/"+sessionIDTest+"/page/execute", 200, execResp)
+
+	origAction := sessionExecuteAction
+	sessionExecuteAction = `{"type":"fill","id":"I1","value":"{{ vault \"vault_1\" \"https://example.com\" \"password\" }}"}`
+	t.Cleanup(func() { sessionExecuteAction = origAction })
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionExecute(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	reqs := server.Requests("/sessions/" + sessionIDTest + "/page/execute")
+	if len(reqs) != 1 || !strings.Contains(reqs[0].Body, `"value":"hunter2"`) {
+		t.Fatalf("expected rendered action to be sent, got %+v", reqs)
+	}
+	if strings.Contains(stdout, "hunter2") {
+		t.Errorf("secret leaked into output: %s", stdout)
+	}
+}
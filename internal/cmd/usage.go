@@ -1,18 +1,22 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
+// usageLogsPageSize is the page size of `usage logs` unless --page-size,
+// --limit or --all is given
+const usageLogsPageSize = 20
+
 var (
 	usageShowPeriod     string
 	usageLogsEndpoint   string
-	usageLogsPage       int
-	usageLogsPageSize   int
 	usageLogsOnlyActive bool
 )
 
@@ -38,9 +42,9 @@ func init() {
 	usageCmd.Flags().StringVar(&usageShowPeriod, "period", "", "Monthly period to get usage for (e.g., 'May 2025')")
 
 	// Flags for usage logs command
+	addListFlags(usageLogsCmd)
+	usageLogsCmd.Flags().Lookup("page-size").Usage = fmt.Sprintf("Number of items per page (default: %d)", usageLogsPageSize)
 	usageLogsCmd.Flags().StringVar(&usageLogsEndpoint, "endpoint", "", "Filter logs by endpoint")
-	usageLogsCmd.Flags().IntVar(&usageLogsPage, "page", 1, "Page number")
-	usageLogsCmd.Flags().IntVar(&usageLogsPageSize, "page-size", 20, "Number of items per page")
 	usageLogsCmd.Flags().BoolVar(&usageLogsOnlyActive, "only-active", false, "Only return active sessions")
 }
 
@@ -71,52 +75,46 @@ func runUsageShow(cmd *cobra.Command, args []string) error {
 	return formatter.Print(resp.JSON200)
 }
 
+// usageLogTable lists the columns available to `usage logs`
+var usageLogTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "created_at", Header: "TIME", Path: "created_at"},
+		{Name: "endpoint", Header: "ENDPOINT", Path: "endpoint"},
+		{Name: "duration", Header: "DURATION (MS)", Path: "duration_ms"},
+	},
+	Defaults: []string{"created_at", "endpoint", "duration"},
+}
+
 func runUsageLogs(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.GetUsageLogsParams{}
-
-	if usageLogsEndpoint != "" {
-		params.Endpoint = &usageLogsEndpoint
-	}
-
-	if usageLogsPage > 0 {
-		params.Page = &usageLogsPage
-	}
-
-	if usageLogsPageSize > 0 {
-		params.PageSize = &usageLogsPageSize
-	}
-
-	if cmd.Flags().Changed("only-active") {
-		params.OnlyActive = &usageLogsOnlyActive
-	}
-
-	resp, err := client.Client().GetUsageLogsWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
-
-	formatter := GetFormatter()
-	var items []api.UsageLog
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
-	}
-	if printed, err := PrintListOrEmpty(items, "No usage logs found."); err != nil {
-		return err
-	} else if printed {
-		return nil
-	}
-
-	return formatter.Print(items)
+	return runPagedList(cmd, usageLogTable, "No usage logs found.", func(ctx context.Context, page pageRequest) ([]api.UsageLog, bool, error) {
+		params := &api.GetUsageLogsParams{Page: page.Page, PageSize: page.PageSize}
+		if params.PageSize == nil && !listAll && listLimit == 0 {
+			size := usageLogsPageSize
+			params.PageSize = &size
+		}
+		if usageLogsEndpoint != "" {
+			params.Endpoint = &usageLogsEndpoint
+		}
+		if cmd.Flags().Changed("only-active") {
+			params.OnlyActive = &usageLogsOnlyActive
+		}
+		resp, err := client.Client().GetUsageLogsWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
+
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
+
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
+	})
 }
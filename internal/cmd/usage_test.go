@@ -54,19 +54,19 @@ func TestRunUsageLogs(t *testing.T) {
 	server.AddResponse("/usage/logs", 200, `{"has_next":false,"items":[{"created_at":"2020-01-01T00:00:00Z","duration_ms":10,"endpoint":"/v1/test"}],"page":1,"page_size":20}`)
 
 	origEndpoint := usageLogsEndpoint
-	origPage := usageLogsPage
-	origPageSize := usageLogsPageSize
+	origPage := listPage
+	origPageSize := listPageSize
 	origOnlyActive := usageLogsOnlyActive
 	t.Cleanup(func() {
 		usageLogsEndpoint = origEndpoint
-		usageLogsPage = origPage
-		usageLogsPageSize = origPageSize
+		listPage = origPage
+		listPageSize = origPageSize
 		usageLogsOnlyActive = origOnlyActive
 	})
 
 	usageLogsEndpoint = "/v1/test"
-	usageLogsPage = 1
-	usageLogsPageSize = 20
+	listPage = 1
+	listPageSize = 20
 	usageLogsOnlyActive = true
 
 	origFormat := outputFormat
@@ -101,17 +101,17 @@ func TestRunUsageLogs_Empty(t *testing.T) {
 	server.AddResponse("/usage/logs", 200, `{"has_next":false,"items":[],"page":1,"page_size":20}`)
 
 	origEndpoint := usageLogsEndpoint
-	origPage := usageLogsPage
-	origPageSize := usageLogsPageSize
+	origPage := listPage
+	origPageSize := listPageSize
 	t.Cleanup(func() {
 		usageLogsEndpoint = origEndpoint
-		usageLogsPage = origPage
-		usageLogsPageSize = origPageSize
+		listPage = origPage
+		listPageSize = origPageSize
 	})
 
 	usageLogsEndpoint = ""
-	usageLogsPage = 1
-	usageLogsPageSize = 20
+	listPage = 1
+	listPageSize = 20
 
 	origFormat := outputFormat
 	outputFormat = "text"
@@ -131,3 +131,45 @@ func TestRunUsageLogs_Empty(t *testing.T) {
 		t.Errorf("expected empty message, got %q", stdout)
 	}
 }
+
+func TestRunUsageLogs_DefaultPageSize(t *testing.T) {
+	server := setupPaginateTest(t)
+	server.AddResponse("/usage/logs", 200, `{"has_next":false,"items":[],"page":1,"page_size":20}`)
+
+	origEndpoint := usageLogsEndpoint
+	t.Cleanup(func() { usageLogsEndpoint = origEndpoint })
+	usageLogsEndpoint = ""
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+	run := func() string {
+		t.Helper()
+		testutil.CaptureOutput(func() {
+			if err := runUsageLogs(cmd, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+		reqs := server.Requests("/usage/logs")
+		return reqs[len(reqs)-1].Query.Get("page_size")
+	}
+
+	if got := run(); got != "20" {
+		t.Errorf("expected page_size=20 by default, got %q", got)
+	}
+	listPage = 2
+	if got := run(); got != "20" {
+		t.Errorf("expected page_size=20 with --page, got %q", got)
+	}
+	listPage, listPageSize = 0, 50
+	if got := run(); got != "50" {
+		t.Errorf("expected --page-size to win, got %q", got)
+	}
+	listPageSize, listAll = 0, true
+	if got := run(); got != "" {
+		t.Errorf("expected the server default with --all, got %q", got)
+	}
+	listAll, listLimit = false, 5
+	if got := run(); got != "" {
+		t.Errorf("expected the server default with --limit, got %q", got)
+	}
+}
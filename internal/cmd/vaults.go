@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/mail"
 	"net/url"
@@ -9,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/salmonumbrella/notte-cli/internal/api"
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
 var vaultsCreateName string
@@ -132,6 +134,9 @@ func init() {
 	vaultsCredentialsCmd.AddCommand(vaultsCredentialsGetCmd)
 	vaultsCredentialsCmd.AddCommand(vaultsCredentialsDeleteCmd)
 
+	// List output flags
+	addListFlags(vaultsListCmd)
+
 	// Create command flags
 	vaultsCreateCmd.Flags().StringVar(&vaultsCreateName, "name", "", "Name of the vault")
 
@@ -187,38 +192,39 @@ func init() {
 	_ = vaultsCardDeleteCmd.MarkFlagRequired("id")
 }
 
+// vaultTable lists the columns available to `vaults list`
+var vaultTable = output.TableSpec{
+	Columns: []output.Column{
+		{Name: "id", Header: "ID", Path: "vault_id"},
+		{Name: "name", Header: "NAME", Path: "name"},
+		{Name: "persona", Header: "PERSONA", Path: "for_persona"},
+		{Name: "created_at", Header: "CREATED", Path: "created_at"},
+	},
+	Defaults: []string{"id", "name", "created_at"},
+}
+
 func runVaultsList(cmd *cobra.Command, args []string) error {
 	client, err := GetClient()
 	if err != nil {
 		return err
 	}
 
-	ctx, cancel := GetContextWithTimeout(cmd.Context())
-	defer cancel()
-
-	params := &api.ListVaultsParams{}
-	resp, err := client.Client().ListVaultsWithResponse(ctx, params)
-	if err != nil {
-		return fmt.Errorf("API request failed: %w", err)
-	}
-
-	if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
-		return err
-	}
-
-	formatter := GetFormatter()
+	return runPagedList(cmd, vaultTable, "No vaults found.", func(ctx context.Context, page pageRequest) ([]api.Vault, bool, error) {
+		params := &api.ListVaultsParams{Page: page.Page, PageSize: page.PageSize}
+		resp, err := client.Client().ListVaultsWithResponse(ctx, params)
+		if err != nil {
+			return nil, false, fmt.Errorf("API request failed: %w", err)
+		}
 
-	var items []api.Vault
-	if resp.JSON200 != nil {
-		items = resp.JSON200.Items
-	}
-	if printed, err := PrintListOrEmpty(items, "No vaults found."); err != nil {
-		return err
-	} else if printed {
-		return nil
-	}
+		if err := HandleAPIResponse(resp.HTTPResponse); err != nil {
+			return nil, false, err
+		}
 
-	return formatter.Print(items)
+		if resp.JSON200 == nil {
+			return nil, false, nil
+		}
+		return resp.JSON200.Items, resp.JSON200.HasNext, nil
+	})
 }
 
 func runVaultsCreate(cmd *cobra.Command, args []string) error {
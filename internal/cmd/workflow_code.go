@@ -0,0 +1,507 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+// Targets accepted by `workflow-code --target`
+const (
+	workflowTargetNotteActions = "notte-actions"
+	workflowTargetPythonSDK    = "python-sdk"
+	workflowTargetPlaywrightTS = "playwright-ts"
+	workflowTargetGo           = "go"
+)
+
+var (
+	workflowCodeTarget string
+	workflowCodeOut    string
+)
+
+// addWorkflowCodeFlags registers the flags that choose and save the code
+// `workflow-code` generates
+func addWorkflowCodeFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&workflowCodeTarget, "target", "", "Code to generate: notte-actions|python-sdk|playwright-ts|go (default: the API response)")
+	cmd.Flags().StringVar(&workflowCodeOut, "out", "", "Write the code to this file (python-sdk unless --target is set)")
+}
+
+// workflowCodeRequested reports whether a target or output file was asked
+// for, rather than the API response as is
+func workflowCodeRequested() bool {
+	return workflowCodeTarget != "" || workflowCodeOut != ""
+}
+
+// resolveWorkflowTarget validates --target, defaulting to the server's
+// Python script
+func resolveWorkflowTarget() (string, error) {
+	switch workflowCodeTarget {
+	case "":
+		return workflowTargetPythonSDK, nil
+	case workflowTargetNotteActions, workflowTargetPythonSDK, workflowTargetPlaywrightTS, workflowTargetGo:
+		return workflowCodeTarget, nil
+	}
+	return "", fmt.Errorf("invalid target: expected notte-actions|python-sdk|playwright-ts|go, got %q", workflowCodeTarget)
+}
+
+// workflowSource is what `workflow-code` exports: a session or an agent
+type workflowSource struct {
+	// Kind is "session" or "agent"
+	Kind string
+	ID   string
+	// Script fetches the code the API generates
+	Script func(ctx context.Context) (*api.AgentFunctionCodeResponse, error)
+	// Steps fetches the recorded steps, for targets translated locally
+	Steps func(ctx context.Context) ([]map[string]any, error)
+}
+
+// runWorkflowCodeTarget generates the code for --target and prints it or
+// writes it to --out
+func runWorkflowCodeTarget(cmd *cobra.Command, src workflowSource) error {
+	target, err := resolveWorkflowTarget()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := GetContextWithTimeout(cmd.Context())
+	defer cancel()
+
+	var code string
+	actions := 0
+	if target == workflowTargetPythonSDK {
+		script, err := src.Script(ctx)
+		if err != nil {
+			return err
+		}
+		code = script.PythonScript
+		actions = len(script.JsonActions)
+	} else {
+		steps, err := src.Steps(ctx)
+		if err != nil {
+			return err
+		}
+		recorded, skipped := replayableActions(steps)
+		if len(recorded) == 0 {
+			return fmt.Errorf("no replayable actions in the %s's %d step(s)", src.Kind, len(steps))
+		}
+		if skipped > 0 {
+			PrintInfo(fmt.Sprintf("Skipped %d failed step(s)", skipped))
+		}
+		actions = len(recorded)
+		switch target {
+		case workflowTargetNotteActions:
+			code, err = renderNotteActions(src, recorded)
+		case workflowTargetPlaywrightTS:
+			code = renderPlaywrightTS(src, recorded)
+		case workflowTargetGo:
+			code, err = renderGoProgram(src, recorded)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if workflowCodeOut != "" {
+		if err := os.WriteFile(workflowCodeOut, []byte(code), 0o644); err != nil {
+			return fmt.Errorf("failed to write code: %w", err)
+		}
+		return PrintResult(fmt.Sprintf("Wrote %s code for %d action(s) to %s", target, actions, workflowCodeOut), map[string]any{
+			"path":    workflowCodeOut,
+			"target":  target,
+			"actions": actions,
+		})
+	}
+	if IsStructuredOutput() {
+		return GetFormatter().Print(map[string]any{
+			"target":  target,
+			"actions": actions,
+			"code":    code,
+		})
+	}
+	_, _ = fmt.Fprint(os.Stdout, code)
+	if !strings.HasSuffix(code, "\n") {
+		_, _ = fmt.Fprintln(os.Stdout)
+	}
+	return nil
+}
+
+// skippedActionTypes perform nothing on the page, so replays leave them out
+var skippedActionTypes = map[string]bool{
+	"completion": true,
+	"help":       true,
+}
+
+// replayableActions returns the actions performed by steps, in order, and
+// the number of steps left out because they failed
+func replayableActions(steps []map[string]any) ([]map[string]any, int) {
+	var actions []map[string]any
+	skipped := 0
+	for _, step := range steps {
+		action, failed := stepAction(step)
+		if failed {
+			skipped++
+		}
+		if action == nil || skippedActionTypes[actionString(action, "type")] {
+			continue
+		}
+		actions = append(actions, cleanAction(action))
+	}
+	return actions, skipped
+}
+
+// stepAction finds the action a step performed. Depending on the kind of
+// step, the action is the step itself or is nested under "action" or
+// "value". failed is set when a step around the action reports it did not
+// succeed, in which case no action is returned.
+func stepAction(step map[string]any) (action map[string]any, failed bool) {
+	if t, _ := step["type"].(string); t != "" && isActionType(t) {
+		return step, false
+	}
+	if ok, isBool := step["success"].(bool); isBool && !ok {
+		return nil, true
+	}
+	for _, key := range []string{"action", "value"} {
+		if nested, ok := step[key].(map[string]any); ok {
+			if action, failed := stepAction(nested); action != nil || failed {
+				return action, failed
+			}
+		}
+	}
+	return nil, false
+}
+
+// isActionType reports whether t names an action of the execute endpoint
+func isActionType(t string) bool {
+	switch t {
+	case "captcha_solve", "check", "click", "close_tab", "completion", "download_file",
+		"email_read", "fallback_fill", "fill", "form_fill", "go_back", "go_forward",
+		"goto", "goto_new_tab", "help", "multi_factor_fill", "press_key", "reload",
+		"scrape", "scroll_down", "scroll_up", "select_dropdown_option", "sms_read",
+		"switch_tab", "upload_file", "wait":
+		return true
+	}
+	return false
+}
+
+// cleanAction drops null fields and the action's category, which the
+// execute endpoint does not need
+func cleanAction(action map[string]any) map[string]any {
+	out := make(map[string]any, len(action))
+	for k, v := range action {
+		if v == nil || k == "category" {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// actionKeys returns the keys of action with "type" first and the rest
+// sorted, so generated files read naturally and diff cleanly
+func actionKeys(action map[string]any) []string {
+	keys := make([]string, 0, len(action))
+	for k := range action {
+		if k != "type" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return append([]string{"type"}, keys...)
+}
+
+// generatedHeader describes where generated code came from
+func generatedHeader(src workflowSource, target string) string {
+	return fmt.Sprintf("Generated by `notte %ss workflow-code --target %s` from %s %s", src.Kind, target, src.Kind, src.ID)
+}
+
+// renderNotteActions writes the actions as an action file for `notte run`
+func renderNotteActions(src workflowSource, actions []map[string]any) (string, error) {
+	steps := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, action := range actions {
+		step := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range actionKeys(action) {
+			value := &yaml.Node{}
+			if err := value.Encode(action[k]); err != nil {
+				return "", fmt.Errorf("failed to encode action: %w", err)
+			}
+			step.Content = append(step.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, value)
+		}
+		steps.Content = append(steps.Content, step)
+	}
+	doc := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "steps"}, steps,
+	}}
+
+	var buf bytes.Buffer
+	buf.WriteString("# " + generatedHeader(src, workflowTargetNotteActions) + "\n")
+	buf.WriteString("# Run with: notte run <file>\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return "", fmt.Errorf("failed to encode actions: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// jsString quotes s as a JavaScript string literal
+func jsString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// actionString returns the string field key of action
+func actionString(action map[string]any, key string) string {
+	s, _ := action[key].(string)
+	return s
+}
+
+// playwrightLocator builds a Playwright locator expression for the element
+// an action targets, or "" when the action recorded no selector
+func playwrightLocator(action map[string]any) string {
+	switch sel := action["selector"].(type) {
+	case string:
+		if sel != "" {
+			return "page.locator(" + jsString(sel) + ")"
+		}
+	case map[string]any:
+		target := actionString(sel, "playwright_selector")
+		if target == "" {
+			target = actionString(sel, "css_selector")
+		}
+		if target == "" {
+			if xpath := actionString(sel, "xpath_selector"); xpath != "" {
+				target = "xpath=" + xpath
+			}
+		}
+		if target == "" {
+			return ""
+		}
+		expr := "page"
+		if frames, ok := sel["iframe_parent_css_selectors"].([]any); ok {
+			for _, f := range frames {
+				if css, _ := f.(string); css != "" {
+					expr += ".frameLocator(" + jsString(css) + ")"
+				}
+			}
+		}
+		return expr + ".locator(" + jsString(target) + ")"
+	}
+	return ""
+}
+
+// playwrightStatements translates one action into Playwright statements
+func playwrightStatements(action map[string]any) []string {
+	kind := actionString(action, "type")
+	switch kind {
+	case "goto":
+		return []string{"await page.goto(" + jsString(actionString(action, "url")) + ");"}
+	case "goto_new_tab":
+		return []string{
+			"page = await context.newPage();",
+			"await page.goto(" + jsString(actionString(action, "url")) + ");",
+		}
+	case "go_back":
+		return []string{"await page.goBack();"}
+	case "go_forward":
+		return []string{"await page.goForward();"}
+	case "reload":
+		return []string{"await page.reload();"}
+	case "wait":
+		ms, _ := action["time_ms"].(float64)
+		return []string{"await page.waitForTimeout(" + strconv.FormatFloat(ms, 'f', -1, 64) + ");"}
+	case "press_key":
+		return []string{"await page.keyboard.press(" + jsString(actionString(action, "key")) + ");"}
+	case "scroll_down", "scroll_up":
+		sign := ""
+		if kind == "scroll_up" {
+			sign = "-"
+		}
+		if amount, ok := action["amount"].(float64); ok {
+			return []string{"await page.mouse.wheel(0, " + sign + strconv.FormatFloat(amount, 'f', -1, 64) + ");"}
+		}
+		return []string{"await page.evaluate(() => window.scrollBy(0, " + sign + "window.innerHeight));"}
+	case "switch_tab":
+		index, _ := action["tab_index"].(float64)
+		return []string{
+			"page = context.pages()[" + strconv.FormatFloat(index, 'f', -1, 64) + "];",
+			"await page.bringToFront();",
+		}
+	case "close_tab":
+		return []string{
+			"await page.close();",
+			"page = context.pages()[context.pages().length - 1];",
+		}
+	case "click", "fill", "check", "select_dropdown_option":
+		locator := playwrightLocator(action)
+		if locator == "" {
+			return []string{fmt.Sprintf("// TODO: %s %s recorded no selector", kind, actionString(action, "id"))}
+		}
+		var stmts []string
+		switch kind {
+		case "click":
+			stmts = []string{"await " + locator + ".click();"}
+		case "fill":
+			stmts = []string{"await " + locator + ".fill(" + jsString(fmt.Sprint(action["value"])) + ");"}
+		case "check":
+			checked, _ := action["value"].(bool)
+			stmts = []string{"await " + locator + ".setChecked(" + strconv.FormatBool(checked) + ");"}
+		case "select_dropdown_option":
+			stmts = []string{"await " + locator + ".selectOption(" + jsString(fmt.Sprint(action["value"])) + ");"}
+		}
+		if enter, _ := action["press_enter"].(bool); enter {
+			stmts = append(stmts, "await "+locator+".press(\"Enter\");")
+		}
+		return stmts
+	}
+	return []string{"// TODO: " + kind + " has no Playwright equivalent"}
+}
+
+// renderPlaywrightTS writes the actions as a standalone Playwright script
+func renderPlaywrightTS(src workflowSource, actions []map[string]any) string {
+	var b strings.Builder
+	b.WriteString("// " + generatedHeader(src, workflowTargetPlaywrightTS) + "\n")
+	b.WriteString("import { chromium } from \"playwright\";\n\n")
+	b.WriteString("(async () => {\n")
+	b.WriteString("  const browser = await chromium.launch({ headless: false });\n")
+	b.WriteString("  const context = await browser.newContext();\n")
+	b.WriteString("  let page = await context.newPage();\n")
+	for _, action := range actions {
+		b.WriteString("\n")
+		if desc := actionString(action, "description"); desc != "" {
+			b.WriteString("  // " + strings.Join(strings.Fields(desc), " ") + "\n")
+		}
+		for _, stmt := range playwrightStatements(action) {
+			b.WriteString("  " + stmt + "\n")
+		}
+	}
+	b.WriteString("\n  await browser.close();\n")
+	b.WriteString("})();\n")
+	return b.String()
+}
+
+// goString quotes s as a Go string literal, raw where possible
+func goString(s string) string {
+	if !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// goProgramTemplate replays actions through the Notte API. Its verbs are
+// the header, the action literals and the default API URL.
+const goProgramTemplate = `// %s
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+)
+
+// actions are executed in order, as with ` + "`notte run`" + `
+var actions = []string{
+%s}
+
+func main() {
+	apiKey := os.Getenv("NOTTE_API_KEY")
+	if apiKey == "" {
+		log.Fatal("NOTTE_API_KEY is not set")
+	}
+	baseURL := os.Getenv("NOTTE_API_URL")
+	if baseURL == "" {
+		baseURL = %s
+	}
+
+	var session struct {
+		SessionID string ` + "`json:\"session_id\"`" + `
+	}
+	if err := call(apiKey, http.MethodPost, baseURL+"/sessions/start", "{}", &session); err != nil {
+		log.Fatalf("failed to start session: %%v", err)
+	}
+	defer func() {
+		if err := call(apiKey, http.MethodDelete, baseURL+"/sessions/"+session.SessionID+"/stop", "", nil); err != nil {
+			log.Printf("failed to stop session: %%v", err)
+		}
+	}()
+
+	for i, action := range actions {
+		if err := call(apiKey, http.MethodPost, baseURL+"/sessions/"+session.SessionID+"/page/execute", action, nil); err != nil {
+			log.Printf("step %%d failed: %%v", i+1, err)
+			return
+		}
+		fmt.Printf("step %%d ok\n", i+1)
+	}
+}
+
+// call sends body to url and decodes the JSON response into out
+func call(apiKey, method, url, body string, out any) error {
+	var reader io.Reader
+	if body != "" {
+		reader = bytes.NewReader([]byte(body))
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%%s: %%s", resp.Status, data)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
+`
+
+// renderGoProgram writes the actions as a standalone Go program that
+// replays them in a new session through the Notte API
+func renderGoProgram(src workflowSource, actions []map[string]any) (string, error) {
+	var literals strings.Builder
+	for _, action := range actions {
+		data, err := json.Marshal(action)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode action: %w", err)
+		}
+		literals.WriteString("\t" + goString(string(data)) + ",\n")
+	}
+	code := fmt.Sprintf(goProgramTemplate, generatedHeader(src, workflowTargetGo), literals.String(), strconv.Quote(api.DefaultBaseURL))
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/salmonumbrella/notte-cli/internal/testutil"
+)
+
+const workflowStepsJSON = `[
+	{"type":"execution_result","value":{"action":{"type":"goto","url":"https://example.com","category":"Browser Actions","description":null},"success":true}},
+	{"type":"execution_result","value":{"action":{"type":"click","id":"B1","selector":{"css_selector":"#go","xpath_selector":"//a","playwright_selector":"a:has-text(\"Go\")","in_iframe":false,"in_shadow_root":false,"iframe_parent_css_selectors":[]},"press_enter":null},"success":false}},
+	{"action":{"type":"fill","id":"I1","value":"hello","press_enter":true,"selector":{"css_selector":"input#q","xpath_selector":"","in_iframe":true,"in_shadow_root":false,"iframe_parent_css_selectors":["iframe#search"]}}},
+	{"type":"click","id":"B2","description":"Submit the form"},
+	{"type":"completion","success":true,"answer":"done"}
+]`
+
+func workflowTestSteps(t *testing.T) []map[string]any {
+	t.Helper()
+	var steps []map[string]any
+	if err := json.Unmarshal([]byte(workflowStepsJSON), &steps); err != nil {
+		t.Fatalf("invalid test steps: %v", err)
+	}
+	return steps
+}
+
+func setWorkflowCodeFlags(t *testing.T, target, out string) {
+	t.Helper()
+	origTarget, origOut := workflowCodeTarget, workflowCodeOut
+	workflowCodeTarget, workflowCodeOut = target, out
+	t.Cleanup(func() { workflowCodeTarget, workflowCodeOut = origTarget, origOut })
+}
+
+var testWorkflowSource = workflowSource{Kind: "session", ID: "sess_1"}
+
+func TestReplayableActions(t *testing.T) {
+	actions, skipped := replayableActions(workflowTestSteps(t))
+
+	if skipped != 1 {
+		t.Errorf("expected 1 failed step, got %d", skipped)
+	}
+	var types []string
+	for _, a := range actions {
+		types = append(types, actionString(a, "type"))
+	}
+	if got := strings.Join(types, ","); got != "goto,fill,click" {
+		t.Fatalf("expected goto,fill,click, got %s", got)
+	}
+	if _, ok := actions[0]["category"]; ok {
+		t.Error("expected category to be dropped")
+	}
+	if _, ok := actions[0]["description"]; ok {
+		t.Error("expected null fields to be dropped")
+	}
+}
+
+func TestRenderNotteActions_RoundTrips(t *testing.T) {
+	actions, _ := replayableActions(workflowTestSteps(t))
+
+	code, err := renderNotteActions(testWorkflowSource, actions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(code, "# Generated by `notte sessions workflow-code --target notte-actions` from session sess_1\n") {
+		t.Errorf("unexpected header: %q", code)
+	}
+	if !strings.Contains(code, "  - type: goto\n    url: https://example.com\n") {
+		t.Errorf("expected type first in each step, got:\n%s", code)
+	}
+
+	file, err := parseActionFile([]byte(code))
+	if err != nil {
+		t.Fatalf("generated file does not parse: %v", err)
+	}
+	if len(file.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(file.Steps))
+	}
+	if file.Steps[1].actionType() != "fill" || file.Steps[1].Action["value"] != "hello" {
+		t.Errorf("unexpected fill step: %+v", file.Steps[1])
+	}
+}
+
+func TestRenderPlaywrightTS(t *testing.T) {
+	actions, _ := replayableActions(workflowTestSteps(t))
+	actions = append(actions,
+		map[string]any{"type": "scroll_down", "amount": float64(300)},
+		map[string]any{"type": "scrape"},
+	)
+
+	code := renderPlaywrightTS(testWorkflowSource, actions)
+
+	for _, want := range []string{
+		`import { chromium } from "playwright";`,
+		`  await page.goto("https://example.com");`,
+		`  await page.frameLocator("iframe#search").locator("input#q").fill("hello");`,
+		`  await page.frameLocator("iframe#search").locator("input#q").press("Enter");`,
+		"  // Submit the form\n  // TODO: click B2 recorded no selector",
+		`  await page.mouse.wheel(0, 300);`,
+		`  // TODO: scrape has no Playwright equivalent`,
+		`  await browser.close();`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected %q in:\n%s", want, code)
+		}
+	}
+}
+
+func TestPlaywrightLocator(t *testing.T) {
+	tests := []struct {
+		name     string
+		selector any
+		want     string
+	}{
+		{"string", "#id", `page.locator("#id")`},
+		{"playwright first", map[string]any{"playwright_selector": "text=Go", "css_selector": "#go"}, `page.locator("text=Go")`},
+		{"xpath fallback", map[string]any{"css_selector": "", "xpath_selector": "//a"}, `page.locator("xpath=//a")`},
+		{"none", map[string]any{"css_selector": ""}, ""},
+		{"missing", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := playwrightLocator(map[string]any{"selector": tt.selector}); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderGoProgram(t *testing.T) {
+	actions := []map[string]any{
+		{"type": "goto", "url": "https://example.com"},
+		{"type": "fill", "id": "I1", "value": "back`tick"},
+	}
+
+	code, err := renderGoProgram(testWorkflowSource, actions)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"package main",
+		"\t`{\"type\":\"goto\",\"url\":\"https://example.com\"}`,\n",
+		`"{\"id\":\"I1\",\"type\":\"fill\",\"value\":\"back` + "`" + `tick\"}",`,
+		`baseURL = "https://api.notte.cc"`,
+		`/page/execute`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("expected %q in:\n%s", want, code)
+		}
+	}
+}
+
+func TestResolveWorkflowTarget(t *testing.T) {
+	setWorkflowCodeFlags(t, "", "")
+	if target, err := resolveWorkflowTarget(); err != nil || target != workflowTargetPythonSDK {
+		t.Errorf("expected python-sdk by default, got %q, %v", target, err)
+	}
+
+	workflowCodeTarget = "ruby"
+	if _, err := resolveWorkflowTarget(); err == nil || !strings.Contains(err.Error(), "invalid target") {
+		t.Errorf("expected invalid target error, got %v", err)
+	}
+}
+
+func TestRunSessionWorkflowCode_NotteActionsOut(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest, 200, strings.TrimSuffix(sessionJSON(), "}")+`,"steps":`+workflowStepsJSON+`}`)
+
+	path := filepath.Join(t.TempDir(), "flow.yaml")
+	setWorkflowCodeFlags(t, workflowTargetNotteActions, path)
+
+	origFormat := outputFormat
+	outputFormat = "json"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runSessionWorkflowCode(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if !strings.Contains(stdout, `"actions":3`) {
+		t.Errorf("expected action count in output, got %q", stdout)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read code: %v", err)
+	}
+	if _, err := parseActionFile(data); err != nil {
+		t.Errorf("written file does not parse: %v", err)
+	}
+	if len(server.Requests("/sessions/"+sessionIDTest+"/workflow/code")) != 0 {
+		t.Error("expected no script request for a local target")
+	}
+}
+
+func TestRunSessionWorkflowCode_NoSteps(t *testing.T) {
+	server := setupSessionTest(t)
+	server.AddResponse("/sessions/"+sessionIDTest, 200, sessionJSON())
+	setWorkflowCodeFlags(t, workflowTargetPlaywrightTS, "")
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	err := runSessionWorkflowCode(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "no replayable actions") {
+		t.Fatalf("expected no actions error, got %v", err)
+	}
+}
+
+func TestRunAgentWorkflowCode_PythonSDK(t *testing.T) {
+	server := setupAgentTest(t)
+	server.AddResponse("/agents/"+agentIDTest+"/workflow/code", 200, `{"json_actions":[{"type":"goto"}],"python_script":"print('hi')"}`)
+	setWorkflowCodeFlags(t, workflowTargetPythonSDK, "")
+
+	origFormat := outputFormat
+	outputFormat = "text"
+	t.Cleanup(func() { outputFormat = origFormat })
+
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	stdout, _ := testutil.CaptureOutput(func() {
+		if err := runAgentWorkflowCode(cmd, nil); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	if stdout != "print('hi')\n" {
+		t.Errorf("expected the raw script, got %q", stdout)
+	}
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
 const (
@@ -16,6 +18,7 @@ const (
 	EnvAPIURL          = "NOTTE_API_URL"
 	EnvConsoleURL      = "NOTTE_CONSOLE_URL"
 	EnvSessionID       = "NOTTE_SESSION_ID"
+	EnvContext         = "NOTTE_CONTEXT"
 )
 
 // testConfigDir allows overriding the config directory for testing.
@@ -32,6 +35,98 @@ func SetTestConfigDir(dir string) {
 type Config struct {
 	APIKey string `json:"api_key,omitempty"`
 	APIURL string `json:"api_url,omitempty"`
+
+	// SessionDefaults apply to `sessions start` in every context; a context's
+	// own defaults take precedence field by field
+	SessionDefaults *SessionDefaults `json:"session_defaults,omitempty"`
+
+	// CurrentContext names the context used when none is selected by flag or env
+	CurrentContext string              `json:"current_context,omitempty"`
+	Contexts       map[string]*Context `json:"contexts,omitempty"`
+}
+
+// Context is a named set of settings for one account or environment
+type Context struct {
+	APIURL          string           `json:"api_url,omitempty"`
+	APIKey          string           `json:"api_key,omitempty"`
+	OutputFormat    string           `json:"output_format,omitempty"`
+	SessionDefaults *SessionDefaults `json:"session_defaults,omitempty"`
+}
+
+// SessionDefaults holds default options for `sessions start`.
+// Unset fields fall back to the command's own defaults.
+type SessionDefaults struct {
+	Headless       *bool  `json:"headless,omitempty"`
+	Browser        string `json:"browser,omitempty"`
+	IdleTimeout    int    `json:"idle_timeout,omitempty"`
+	MaxDuration    int    `json:"max_duration,omitempty"`
+	Proxies        *bool  `json:"proxies,omitempty"`
+	SolveCaptchas  *bool  `json:"solve_captchas,omitempty"`
+	ViewportWidth  int    `json:"viewport_width,omitempty"`
+	ViewportHeight int    `json:"viewport_height,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	CdpURL         string `json:"cdp_url,omitempty"`
+}
+
+// ResolveContextName picks the active context name: the explicit name (from
+// a flag) wins, then NOTTE_CONTEXT, then the config's current context. An
+// empty result means the top-level settings are used.
+func (c *Config) ResolveContextName(explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if env := os.Getenv(EnvContext); env != "" {
+		return env
+	}
+	return c.CurrentContext
+}
+
+// GetContext returns the named context, or an error if it does not exist.
+// An empty name returns nil with no error.
+func (c *Config) GetContext(name string) (*Context, error) {
+	if name == "" {
+		return nil, nil
+	}
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		return nil, fmt.Errorf("context %q not found (see 'notte config get-contexts')", name)
+	}
+	return ctx, nil
+}
+
+// EnsureContext returns the named context, creating it if needed
+func (c *Config) EnsureContext(name string) *Context {
+	if c.Contexts == nil {
+		c.Contexts = make(map[string]*Context)
+	}
+	ctx, ok := c.Contexts[name]
+	if !ok {
+		ctx = &Context{}
+		c.Contexts[name] = ctx
+	}
+	return ctx
+}
+
+// ContextNames returns all context names in sorted order
+func (c *Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// APIURLFor returns the API URL for a context, falling back to the
+// top-level URL and then the default
+func (c *Config) APIURLFor(name string) string {
+	if ctx := c.Contexts[name]; ctx != nil && ctx.APIURL != "" {
+		return ctx.APIURL
+	}
+	if c.APIURL != "" {
+		return c.APIURL
+	}
+	return DefaultAPIURL
 }
 
 // Dir returns the notte config directory path (~/.config/notte or ~/Library/Application Support/notte on macOS)
@@ -63,3 +63,79 @@ func TestSaveConfig(t *testing.T) {
 		t.Errorf("API key mismatch: got %q, want %q", loaded.APIKey, cfg.APIKey)
 	}
 }
+
+func TestConfigContexts(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfgPath := filepath.Join(tmpDir, "config.json")
+
+	content := `{
+		"api_url": "https://prod.api.com",
+		"current_context": "staging",
+		"contexts": {
+			"staging": {"api_url": "https://staging.api.com", "output_format": "json"},
+			"prod": {"session_defaults": {"browser": "chrome", "viewport_width": 1920}}
+		}
+	}`
+	if err := os.WriteFile(cfgPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	cfg, err := LoadFromPath(cfgPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := cfg.ResolveContextName(""); got != "staging" {
+		t.Errorf("expected current context 'staging', got %q", got)
+	}
+	t.Setenv(EnvContext, "prod")
+	if got := cfg.ResolveContextName(""); got != "prod" {
+		t.Errorf("expected env context 'prod', got %q", got)
+	}
+	if got := cfg.ResolveContextName("other"); got != "other" {
+		t.Errorf("expected explicit context to win, got %q", got)
+	}
+
+	if got := cfg.APIURLFor("staging"); got != "https://staging.api.com" {
+		t.Errorf("expected staging URL, got %q", got)
+	}
+	if got := cfg.APIURLFor("prod"); got != "https://prod.api.com" {
+		t.Errorf("expected context without URL to fall back to top-level, got %q", got)
+	}
+	if names := cfg.ContextNames(); len(names) != 2 || names[0] != "prod" || names[1] != "staging" {
+		t.Errorf("expected sorted context names, got %v", names)
+	}
+
+	prod, err := cfg.GetContext("prod")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prod.SessionDefaults == nil || prod.SessionDefaults.Browser != "chrome" || prod.SessionDefaults.ViewportWidth != 1920 {
+		t.Errorf("unexpected session defaults: %+v", prod.SessionDefaults)
+	}
+	if ctx, err := cfg.GetContext(""); ctx != nil || err != nil {
+		t.Errorf("expected no context for empty name, got %v, %v", ctx, err)
+	}
+	if _, err := cfg.GetContext("missing"); err == nil {
+		t.Error("expected error for unknown context")
+	}
+}
+
+func TestSaveConfig_Contexts(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+
+	cfg := &Config{}
+	cfg.EnsureContext("dev").APIURL = "https://dev.api.com"
+	cfg.CurrentContext = "dev"
+	if err := cfg.SaveToPath(cfgPath); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadFromPath(cfgPath)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if loaded.CurrentContext != "dev" || loaded.APIURLFor("dev") != "https://dev.api.com" {
+		t.Errorf("contexts not round-tripped: %+v", loaded)
+	}
+}
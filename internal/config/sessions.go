@@ -0,0 +1,209 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+const (
+	// SessionSlotsFile stores named sessions, next to CurrentSessionFile
+	SessionSlotsFile = "sessions.json"
+	// EnvSession selects a named session, so each terminal can work on its own
+	EnvSession = "NOTTE_SESSION"
+)
+
+var slotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// SessionSlots maps session names to session IDs. Active names the slot
+// that CurrentSessionFile was last set from, if any.
+type SessionSlots struct {
+	Active string            `json:"active,omitempty"`
+	Slots  map[string]string `json:"slots,omitempty"`
+}
+
+// ValidateSlotName checks that a session name is usable on the command line
+func ValidateSlotName(name string) error {
+	if !slotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid session name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// SessionSlotsPath returns the path of the session slots file
+func SessionSlotsPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, SessionSlotsFile), nil
+}
+
+// LoadSessionSlots reads the session slots file. A missing file has no slots.
+func LoadSessionSlots() (*SessionSlots, error) {
+	slots := &SessionSlots{}
+
+	path, err := SessionSlotsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return slots, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, slots); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return slots, nil
+}
+
+// Save writes the session slots file with owner-only permissions. The file
+// is replaced in one step, so readers never see it half written.
+func (s *SessionSlots) Save() error {
+	path, err := SessionSlotsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sessions-*.json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// UpdateSessionSlots loads the session slots file, applies fn and saves the
+// result if fn reports a change. A lock file serializes updates from parallel terminals, so one
+// terminal's change is not lost to another's.
+func UpdateSessionSlots(fn func(*SessionSlots) bool) error {
+	path, err := SessionSlotsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	slots, err := LoadSessionSlots()
+	if err != nil {
+		return err
+	}
+	if !fn(slots) {
+		return nil
+	}
+	return slots.Save()
+}
+
+const (
+	// lockTimeout bounds how long an update waits for another terminal
+	lockTimeout = 5 * time.Second
+	// lockStale is the age after which a lock left by a crashed process is
+	// taken over
+	lockStale = 30 * time.Second
+)
+
+// lockFile creates path exclusively, waiting while another process holds it,
+// and returns a function that releases it
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockStale {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// Set points a named slot at a session ID
+func (s *SessionSlots) Set(name, id string) {
+	if s.Slots == nil {
+		s.Slots = map[string]string{}
+	}
+	s.Slots[name] = id
+}
+
+// Lookup returns the session ID of a named slot
+func (s *SessionSlots) Lookup(name string) (string, bool) {
+	id, ok := s.Slots[name]
+	return id, ok
+}
+
+// NameOf returns the name of a slot holding id, preferring the active slot,
+// or "" if the session is not named
+func (s *SessionSlots) NameOf(id string) string {
+	if s.Active != "" && s.Slots[s.Active] == id {
+		return s.Active
+	}
+	for _, name := range s.Names() {
+		if s.Slots[name] == id {
+			return name
+		}
+	}
+	return ""
+}
+
+// Names returns the slot names in sorted order
+func (s *SessionSlots) Names() []string {
+	names := make([]string, 0, len(s.Slots))
+	for name := range s.Slots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Forget removes every slot holding id and returns the removed names
+func (s *SessionSlots) Forget(id string) []string {
+	var removed []string
+	for _, name := range s.Names() {
+		if s.Slots[name] == id {
+			delete(s.Slots, name)
+			removed = append(removed, name)
+		}
+	}
+	if _, ok := s.Slots[s.Active]; !ok {
+		s.Active = ""
+	}
+	return removed
+}
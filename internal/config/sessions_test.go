@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSessionSlots_SaveLoad(t *testing.T) {
+	SetTestConfigDir(t.TempDir())
+	t.Cleanup(func() { SetTestConfigDir("") })
+
+	slots, err := LoadSessionSlots()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(slots.Names()) != 0 || slots.Active != "" {
+		t.Fatalf("expected no slots without a file, got %+v", slots)
+	}
+
+	slots.Set("checkout", "sess_1")
+	slots.Set("search", "sess_2")
+	slots.Active = "checkout"
+	if err := slots.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path, _ := SessionSlotsPath()
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected %s with mode 0600, got %v (%v)", filepath.Base(path), info, err)
+	}
+
+	loaded, err := LoadSessionSlots()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, slots) {
+		t.Errorf("expected %+v, got %+v", slots, loaded)
+	}
+}
+
+func TestUpdateSessionSlots_Parallel(t *testing.T) {
+	SetTestConfigDir(t.TempDir())
+	t.Cleanup(func() { SetTestConfigDir("") })
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := UpdateSessionSlots(func(s *SessionSlots) bool {
+				s.Set(fmt.Sprintf("slot%d", i), fmt.Sprintf("sess_%d", i))
+				return true
+			})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	slots, err := LoadSessionSlots()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(slots.Names()); n != 20 {
+		t.Errorf("expected every update to be kept, got %d slots", n)
+	}
+	path, _ := SessionSlotsPath()
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the slots file to remain, got %v", entries)
+	}
+
+	if err := UpdateSessionSlots(func(s *SessionSlots) bool { return false }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSessionSlots_NameOfAndForget(t *testing.T) {
+	slots := &SessionSlots{}
+	slots.Set("b", "sess_1")
+	slots.Set("a", "sess_1")
+	slots.Set("c", "sess_2")
+
+	if got := slots.NameOf("sess_1"); got != "a" {
+		t.Errorf("expected first name in order, got %q", got)
+	}
+	slots.Active = "b"
+	if got := slots.NameOf("sess_1"); got != "b" {
+		t.Errorf("expected active name to win, got %q", got)
+	}
+	if got := slots.NameOf("sess_9"); got != "" {
+		t.Errorf("expected no name, got %q", got)
+	}
+
+	removed := slots.Forget("sess_1")
+	if !reflect.DeepEqual(removed, []string{"a", "b"}) {
+		t.Errorf("expected a and b removed, got %v", removed)
+	}
+	if slots.Active != "" || !reflect.DeepEqual(slots.Names(), []string{"c"}) {
+		t.Errorf("expected only c left and no active slot, got %+v", slots)
+	}
+}
+
+func TestValidateSlotName(t *testing.T) {
+	for _, name := range []string{"checkout", "job-1", "a.b_c"} {
+		if err := ValidateSlotName(name); err != nil {
+			t.Errorf("ValidateSlotName(%q): unexpected error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-x", "has space", "a/b"} {
+		if err := ValidateSlotName(name); err == nil {
+			t.Errorf("ValidateSlotName(%q): expected error", name)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SessionDefaultKeys lists the session default fields that can be read and
+// written by key, in display order
+var SessionDefaultKeys = []string{
+	"browser",
+	"headless",
+	"viewport_width",
+	"viewport_height",
+	"user_agent",
+	"proxies",
+	"solve_captchas",
+	"idle_timeout",
+	"max_duration",
+	"cdp_url",
+}
+
+// Get returns the value of a session default field as a string. The second
+// result is false when the field is unset.
+func (d *SessionDefaults) Get(key string) (string, bool, error) {
+	if d == nil {
+		if !isSessionDefaultKey(key) {
+			return "", false, unknownSessionDefaultKey(key)
+		}
+		return "", false, nil
+	}
+
+	switch key {
+	case "browser":
+		return d.Browser, d.Browser != "", nil
+	case "headless":
+		return formatBoolPtr(d.Headless)
+	case "viewport_width":
+		return formatInt(d.ViewportWidth)
+	case "viewport_height":
+		return formatInt(d.ViewportHeight)
+	case "user_agent":
+		return d.UserAgent, d.UserAgent != "", nil
+	case "proxies":
+		return formatBoolPtr(d.Proxies)
+	case "solve_captchas":
+		return formatBoolPtr(d.SolveCaptchas)
+	case "idle_timeout":
+		return formatInt(d.IdleTimeout)
+	case "max_duration":
+		return formatInt(d.MaxDuration)
+	case "cdp_url":
+		return d.CdpURL, d.CdpURL != "", nil
+	}
+	return "", false, unknownSessionDefaultKey(key)
+}
+
+// Set parses value and stores it in a session default field. An empty value
+// clears the field.
+func (d *SessionDefaults) Set(key, value string) error {
+	var err error
+	switch key {
+	case "browser":
+		d.Browser = value
+	case "headless":
+		d.Headless, err = parseBoolPtr(value)
+	case "viewport_width":
+		d.ViewportWidth, err = parsePositiveInt(value)
+	case "viewport_height":
+		d.ViewportHeight, err = parsePositiveInt(value)
+	case "user_agent":
+		d.UserAgent = value
+	case "proxies":
+		d.Proxies, err = parseBoolPtr(value)
+	case "solve_captchas":
+		d.SolveCaptchas, err = parseBoolPtr(value)
+	case "idle_timeout":
+		d.IdleTimeout, err = parsePositiveInt(value)
+	case "max_duration":
+		d.MaxDuration, err = parsePositiveInt(value)
+	case "cdp_url":
+		d.CdpURL = value
+	default:
+		return unknownSessionDefaultKey(key)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// IsEmpty reports whether no field is set
+func (d *SessionDefaults) IsEmpty() bool {
+	return d == nil || *d == SessionDefaults{}
+}
+
+// Merge returns a copy of d with every field set in over taking precedence.
+// Either side may be nil.
+func (d *SessionDefaults) Merge(over *SessionDefaults) *SessionDefaults {
+	merged := SessionDefaults{}
+	if d != nil {
+		merged = *d
+	}
+	if over == nil {
+		return &merged
+	}
+
+	if over.Headless != nil {
+		merged.Headless = over.Headless
+	}
+	if over.Browser != "" {
+		merged.Browser = over.Browser
+	}
+	if over.IdleTimeout > 0 {
+		merged.IdleTimeout = over.IdleTimeout
+	}
+	if over.MaxDuration > 0 {
+		merged.MaxDuration = over.MaxDuration
+	}
+	if over.Proxies != nil {
+		merged.Proxies = over.Proxies
+	}
+	if over.SolveCaptchas != nil {
+		merged.SolveCaptchas = over.SolveCaptchas
+	}
+	if over.ViewportWidth > 0 {
+		merged.ViewportWidth = over.ViewportWidth
+	}
+	if over.ViewportHeight > 0 {
+		merged.ViewportHeight = over.ViewportHeight
+	}
+	if over.UserAgent != "" {
+		merged.UserAgent = over.UserAgent
+	}
+	if over.CdpURL != "" {
+		merged.CdpURL = over.CdpURL
+	}
+	return &merged
+}
+
+// SessionDefaultsFor returns the session defaults in effect for a context:
+// the top-level defaults overlaid with the context's own
+func (c *Config) SessionDefaultsFor(name string) *SessionDefaults {
+	var over *SessionDefaults
+	if ctx := c.Contexts[name]; ctx != nil {
+		over = ctx.SessionDefaults
+	}
+	return c.SessionDefaults.Merge(over)
+}
+
+func isSessionDefaultKey(key string) bool {
+	for _, k := range SessionDefaultKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func unknownSessionDefaultKey(key string) error {
+	return fmt.Errorf("unknown session default %q (valid: %s)", key, strings.Join(SessionDefaultKeys, ", "))
+}
+
+func formatBoolPtr(b *bool) (string, bool, error) {
+	if b == nil {
+		return "", false, nil
+	}
+	return strconv.FormatBool(*b), true, nil
+}
+
+func formatInt(n int) (string, bool, error) {
+	if n <= 0 {
+		return "", false, nil
+	}
+	return strconv.Itoa(n), true, nil
+}
+
+func parseBoolPtr(value string) (*bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("expected true or false, got %q", value)
+	}
+	return &b, nil
+}
+
+func parsePositiveInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("expected a positive integer, got %q", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import "testing"
+
+func TestSessionDefaults_SetGet(t *testing.T) {
+	d := &SessionDefaults{}
+
+	if err := d.Set("viewport_width", "1920"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.Set("solve_captchas", "true"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.ViewportWidth != 1920 || d.SolveCaptchas == nil || !*d.SolveCaptchas {
+		t.Errorf("fields not set: %+v", d)
+	}
+
+	if v, ok, _ := d.Get("viewport_width"); !ok || v != "1920" {
+		t.Errorf("expected 1920, got %q (set=%v)", v, ok)
+	}
+	if _, ok, _ := d.Get("browser"); ok {
+		t.Error("expected browser to be unset")
+	}
+
+	if err := d.Set("solve_captchas", ""); err != nil || d.SolveCaptchas != nil {
+		t.Errorf("expected empty value to clear field, got %v, %v", d.SolveCaptchas, err)
+	}
+
+	for key, value := range map[string]string{"viewport_width": "wide", "idle_timeout": "-1", "proxies": "maybe"} {
+		if err := d.Set(key, value); err == nil {
+			t.Errorf("expected error for %s=%q", key, value)
+		}
+	}
+	if err := d.Set("nope", "x"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+	if _, _, err := (*SessionDefaults)(nil).Get("nope"); err == nil {
+		t.Error("expected error for unknown key on nil defaults")
+	}
+}
+
+func TestSessionDefaultsFor(t *testing.T) {
+	yes, no := true, false
+	cfg := &Config{
+		SessionDefaults: &SessionDefaults{Browser: "chrome", ViewportWidth: 1280, Proxies: &yes},
+		Contexts: map[string]*Context{
+			"prod": {SessionDefaults: &SessionDefaults{ViewportWidth: 1920, Proxies: &no}},
+		},
+	}
+
+	got := cfg.SessionDefaultsFor("prod")
+	if got.Browser != "chrome" || got.ViewportWidth != 1920 || got.Proxies == nil || *got.Proxies {
+		t.Errorf("expected context to override top-level field by field, got %+v", got)
+	}
+
+	got = cfg.SessionDefaultsFor("")
+	if got.ViewportWidth != 1280 || got.Proxies == nil || !*got.Proxies {
+		t.Errorf("expected top-level defaults without a context, got %+v", got)
+	}
+	if cfg.SessionDefaults.ViewportWidth != 1280 {
+		t.Error("merge must not modify the top-level defaults")
+	}
+
+	if !(&Config{}).SessionDefaultsFor("").IsEmpty() {
+		t.Error("expected empty defaults when none are configured")
+	}
+}
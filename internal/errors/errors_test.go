@@ -2,6 +2,7 @@ package errors
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -109,3 +110,21 @@ func TestIsRetryable(t *testing.T) {
 		})
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	if got := ExitCode(nil); got != 0 {
+		t.Errorf("nil: got %d, want 0", got)
+	}
+	if got := ExitCode(errors.New("boom")); got != 1 {
+		t.Errorf("plain error: got %d, want 1", got)
+	}
+
+	exitErr := &ExitError{Code: 3, Err: errors.New("timed out")}
+	wrapped := fmt.Errorf("wait: %w", exitErr)
+	if got := ExitCode(wrapped); got != 3 {
+		t.Errorf("wrapped ExitError: got %d, want 3", got)
+	}
+	if exitErr.Error() != "timed out" {
+		t.Errorf("got %q, want %q", exitErr.Error(), "timed out")
+	}
+}
@@ -0,0 +1,99 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// CSVFormatter outputs data as CSV with a header row. Nested objects are
+// flattened into dotted column names (e.g. "viewport.width"); arrays are
+// written as JSON.
+type CSVFormatter struct {
+	Writer io.Writer
+}
+
+func (f *CSVFormatter) Print(data any) error {
+	v, err := toGeneric(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode CSV: %w", err)
+	}
+
+	items, ok := v.([]any)
+	if !ok {
+		items = []any{v}
+	}
+
+	rows := make([]map[string]string, len(items))
+	seen := map[string]bool{}
+	var headers []string
+	for i, item := range items {
+		row := map[string]string{}
+		if obj, ok := item.(map[string]any); ok {
+			flatten("", obj, row)
+		} else {
+			row["value"] = FormatValue(item)
+		}
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				headers = append(headers, k)
+			}
+		}
+		rows[i] = row
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	sort.Strings(headers)
+
+	w := csv.NewWriter(f.Writer)
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(headers))
+		for i, h := range headers {
+			record[i] = row[h]
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (f *CSVFormatter) PrintError(err error) {
+	printPlainError(err)
+}
+
+// flatten writes the leaves of obj into row, keyed by dotted path
+func flatten(prefix string, obj map[string]any, row map[string]string) {
+	for k, v := range obj {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if v == nil {
+			// Leave the cell empty; a null object must not add a column
+			// next to its flattened fields
+			continue
+		}
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flatten(key, nested, row)
+			continue
+		}
+		row[key] = plainValue(v)
+	}
+}
+
+// plainValue renders a leaf value for machine-readable text output. Unlike
+// table cells, strings such as timestamps are kept as-is.
+func plainValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return FormatValue(v)
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPathFormatter prints the values selected by a JSONPath expression.
+// Lists are evaluated once per item, one line each; multiple matches within
+// an item are separated by spaces.
+//
+// Supported syntax: $ (optional), .field, ['field'], [n], [-n], [*] and .*,
+// optionally wrapped in braces as in {.items[*].name}.
+type JSONPathFormatter struct {
+	Writer     io.Writer
+	Expression string
+}
+
+// jsonPathStep is one parsed element of a JSONPath expression
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// JSONPath is a parsed JSONPath expression
+type JSONPath struct {
+	steps []jsonPathStep
+}
+
+// ParseJSONPath parses an expression given with -o jsonpath=...
+func ParseJSONPath(expr string) (*JSONPath, error) {
+	orig := expr
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("jsonpath format requires an expression, e.g. -o jsonpath='{.name}'")
+	}
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+	expr = strings.TrimPrefix(expr, "$")
+
+	var steps []jsonPathStep
+	for expr != "" {
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			return nil, fmt.Errorf("invalid jsonpath %q: recursive descent is not supported", orig)
+
+		case expr[0] == '.':
+			expr = expr[1:]
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			name := expr[:end]
+			expr = expr[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid jsonpath %q: empty field name", orig)
+			case "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			default:
+				steps = append(steps, jsonPathStep{field: name})
+			}
+
+		case expr[0] == '[':
+			end := strings.IndexByte(expr, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid jsonpath %q: missing ]", orig)
+			}
+			inner := strings.TrimSpace(expr[1:end])
+			expr = expr[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonPathStep{field: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid jsonpath %q: unsupported subscript [%s]", orig, inner)
+				}
+				steps = append(steps, jsonPathStep{index: n, isIndex: true})
+			}
+
+		default:
+			return nil, fmt.Errorf("invalid jsonpath %q: unexpected %q", orig, expr)
+		}
+	}
+	return &JSONPath{steps: steps}, nil
+}
+
+// Eval returns every value the path reaches from root, which must be built
+// from plain maps and slices. Paths that do not exist yield no values.
+func (p *JSONPath) Eval(root any) []any {
+	current := []any{root}
+	for _, step := range p.steps {
+		var next []any
+		for _, v := range current {
+			switch node := v.(type) {
+			case map[string]any:
+				if step.wildcard {
+					for _, k := range sortedKeys(node) {
+						next = append(next, node[k])
+					}
+				} else if child, ok := node[step.field]; ok && !step.isIndex {
+					next = append(next, child)
+				}
+			case []any:
+				switch {
+				case step.wildcard:
+					next = append(next, node...)
+				case step.isIndex:
+					i := step.index
+					if i < 0 {
+						i += len(node)
+					}
+					if i >= 0 && i < len(node) {
+						next = append(next, node[i])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+func (f *JSONPathFormatter) Print(data any) error {
+	path, err := ParseJSONPath(f.Expression)
+	if err != nil {
+		return err
+	}
+	v, err := toGeneric(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode data: %w", err)
+	}
+
+	items, ok := v.([]any)
+	if !ok {
+		items = []any{v}
+	}
+	for _, item := range items {
+		values := path.Eval(item)
+		parts := make([]string, len(values))
+		for i, value := range values {
+			parts[i] = plainValue(value)
+		}
+		if _, err := fmt.Fprintln(f.Writer, strings.Join(parts, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *JSONPathFormatter) PrintError(err error) {
+	printPlainError(err)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
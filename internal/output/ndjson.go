@@ -0,0 +1,36 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// NDJSONFormatter outputs one compact JSON document per line. Lists are
+// written one item per line so they can be processed as a stream.
+type NDJSONFormatter struct {
+	Writer io.Writer
+}
+
+func (f *NDJSONFormatter) Print(data any) error {
+	enc := json.NewEncoder(f.Writer)
+
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return enc.Encode(data)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.Encode(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *NDJSONFormatter) PrintError(err error) {
+	(&JSONFormatter{}).PrintError(err)
+}
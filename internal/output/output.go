@@ -1,18 +1,47 @@
 package output
 
 import (
+	"encoding/json"
+	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 // Format represents output format type
 type Format string
 
 const (
-	FormatText Format = "text"
-	FormatJSON Format = "json"
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatCSV    Format = "csv"
+	FormatNDJSON Format = "ndjson"
+
+	// FormatTemplate and FormatJSONPath take an argument, written as
+	// template=<go template> and jsonpath=<expression>
+	FormatTemplate Format = "template"
+	FormatJSONPath Format = "jsonpath"
 )
 
+// Split separates a format such as "template={{.Name}}" into its kind and
+// argument. Formats without an argument return an empty argument.
+func (f Format) Split() (Format, string) {
+	kind, arg, _ := strings.Cut(string(f), "=")
+	return Format(kind), arg
+}
+
+// Structured reports whether the format is meant for machines rather than
+// people. Informational messages go to stderr for structured formats.
+func (f Format) Structured() bool {
+	kind, _ := f.Split()
+	switch kind {
+	case FormatJSON, FormatYAML, FormatCSV, FormatNDJSON, FormatTemplate, FormatJSONPath:
+		return true
+	}
+	return false
+}
+
 // Formatter interface for output formatting
 type Formatter interface {
 	Print(data any) error
@@ -25,10 +54,41 @@ func NewFormatter(format Format, w io.Writer) Formatter {
 		w = os.Stdout
 	}
 
-	switch format {
+	kind, arg := format.Split()
+	switch kind {
 	case FormatJSON:
 		return &JSONFormatter{Writer: w}
+	case FormatYAML:
+		return &YAMLFormatter{Writer: w}
+	case FormatCSV:
+		return &CSVFormatter{Writer: w}
+	case FormatNDJSON:
+		return &NDJSONFormatter{Writer: w}
+	case FormatTemplate:
+		return &TemplateFormatter{Writer: w, Template: arg}
+	case FormatJSONPath:
+		return &JSONPathFormatter{Writer: w, Expression: arg}
 	default:
 		return &TextFormatter{Writer: w}
 	}
 }
+
+// toGeneric converts data to plain maps, slices and scalars via JSON, so
+// that field names and omitted fields match the JSON output
+func toGeneric(data any) (any, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// printPlainError writes an error to stderr without color, for formats that
+// have no structured error representation
+func printPlainError(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+}
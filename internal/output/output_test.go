@@ -319,6 +319,11 @@ func TestNewFormatter(t *testing.T) {
 	}{
 		{FormatJSON, "*output.JSONFormatter"},
 		{FormatText, "*output.TextFormatter"},
+		{FormatYAML, "*output.YAMLFormatter"},
+		{FormatCSV, "*output.CSVFormatter"},
+		{FormatNDJSON, "*output.NDJSONFormatter"},
+		{Format("template={{.Name}}"), "*output.TemplateFormatter"},
+		{Format("jsonpath={.name}"), "*output.JSONPathFormatter"},
 		{Format("unknown"), "*output.TextFormatter"},
 	}
 
@@ -332,3 +337,265 @@ func TestNewFormatter(t *testing.T) {
 		})
 	}
 }
+
+func TestTableSpec_Resolve(t *testing.T) {
+	spec := TableSpec{
+		Columns: []Column{
+			{Name: "id", Header: "ID", Path: "item_id"},
+			{Name: "created_at", Header: "CREATED", Path: "created_at"},
+		},
+		Defaults: []string{"id"},
+	}
+	records := []map[string]any{{"item_id": "a", "meta": map[string]any{"region": "eu"}}}
+
+	cols, err := spec.Resolve(nil, records)
+	if err != nil || len(cols) != 1 || cols[0].Path != "item_id" {
+		t.Fatalf("expected default columns, got %v, %v", cols, err)
+	}
+
+	cols, err = spec.Resolve([]string{"created_at", "meta.region"}, records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cols[0].Header != "CREATED" || cols[1].Header != "META REGION" || cols[1].Path != "meta.region" {
+		t.Errorf("unexpected columns: %+v", cols)
+	}
+
+	if _, err := spec.Resolve([]string{"nope"}, records); err == nil || !strings.Contains(err.Error(), "available: id, created_at") {
+		t.Errorf("expected unknown column error, got %v", err)
+	}
+}
+
+func TestSortSlice(t *testing.T) {
+	items := []testData{{Name: "b", Count: 10}, {Name: "a", Count: 2}, {Name: "c", Count: 2}}
+
+	sorted, err := SortSlice(items, "count")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := sorted.([]testData)
+	if got[0].Name != "a" || got[1].Name != "c" || got[2].Name != "b" {
+		t.Errorf("expected stable numeric sort, got %v", got)
+	}
+
+	sorted, _ = SortSlice(items, "-name")
+	got = sorted.([]testData)
+	if got[0].Name != "c" || got[2].Name != "a" {
+		t.Errorf("expected descending sort, got %v", got)
+	}
+	if items[0].Name != "b" {
+		t.Error("SortSlice must not modify its input")
+	}
+}
+
+func TestTextFormatter_PrintRecords(t *testing.T) {
+	var buf bytes.Buffer
+	f := &TextFormatter{Writer: &buf, NoColor: true}
+
+	records := []map[string]any{
+		{"id": "x1", "created_at": "2024-01-15T10:30:00Z", "count": float64(3), "tags": []any{"a"}},
+		{"id": "x2"},
+	}
+	cols := []Column{
+		{Header: "ID", Path: "id"},
+		{Header: "CREATED", Path: "created_at"},
+		{Header: "COUNT", Path: "count"},
+		{Header: "TAGS", Path: "tags"},
+	}
+	if err := f.PrintRecords(cols, records); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header and 2 rows, got %q", buf.String())
+	}
+	for _, want := range []string{"ID", "CREATED", "x1", "2024-01-15 10:30:00", "3", `["a"]`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("expected %q in table, got %q", want, buf.String())
+		}
+	}
+	if strings.Index(lines[0], "CREATED") != strings.Index(lines[1], "2024") {
+		t.Errorf("expected aligned columns, got %q", buf.String())
+	}
+}
+
+type nestedData struct {
+	ID       string         `json:"id"`
+	Viewport map[string]int `json:"viewport"`
+	Tags     []string       `json:"tags,omitempty"`
+	Note     *string        `json:"note"`
+}
+
+func TestFormat_Structured(t *testing.T) {
+	for _, f := range []Format{FormatJSON, FormatYAML, FormatCSV, FormatNDJSON, "template={{.}}", "jsonpath={.a}"} {
+		if !f.Structured() {
+			t.Errorf("expected %q to be structured", f)
+		}
+	}
+	if FormatText.Structured() {
+		t.Error("text must not be structured")
+	}
+	if kind, arg := Format("template={{.A}}={{.B}}").Split(); kind != FormatTemplate || arg != "{{.A}}={{.B}}" {
+		t.Errorf("unexpected split: %q %q", kind, arg)
+	}
+}
+
+func TestYAMLFormatter_Print(t *testing.T) {
+	var buf bytes.Buffer
+	f := &YAMLFormatter{Writer: &buf}
+
+	if err := f.Print([]nestedData{{ID: "a", Viewport: map[string]int{"width": 1920}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "- id: a\n  note: null\n  viewport:\n    width: 1920\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestCSVFormatter_Print(t *testing.T) {
+	var buf bytes.Buffer
+	f := &CSVFormatter{Writer: &buf}
+
+	note := "hello, world"
+	items := []nestedData{
+		{ID: "a", Viewport: map[string]int{"width": 1920, "height": 1080}, Tags: []string{"x", "y"}, Note: &note},
+		{ID: "b"},
+	}
+	if err := f.Print(items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "id,note,tags,viewport.height,viewport.width\n" +
+		"a,\"hello, world\",\"[\"\"x\"\",\"\"y\"\"]\",1080,1920\n" +
+		"b,,,,\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := f.Print(testData{Name: "single", Count: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "count,name\n1,single\n" {
+		t.Errorf("expected single object as one row, got %q", buf.String())
+	}
+}
+
+func TestNDJSONFormatter_Print(t *testing.T) {
+	var buf bytes.Buffer
+	f := &NDJSONFormatter{Writer: &buf}
+
+	if err := f.Print([]testData{{Name: "a", Count: 1}, {Name: "b", Count: 2}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"name":"a","count":1}` + "\n" + `{"name":"b","count":2}` + "\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTemplateFormatter_Print(t *testing.T) {
+	var buf bytes.Buffer
+	note := "n"
+	f := &TemplateFormatter{Writer: &buf, Template: "{{.ID}} {{.Note}} {{json .Tags}}"}
+
+	if err := f.Print([]nestedData{{ID: "a", Note: &note, Tags: []string{"x"}}, {ID: "b"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if buf.String() != "a n [\"x\"]\nb <nil> null\n" {
+		t.Errorf("unexpected template output: %q", buf.String())
+	}
+
+	f.Template = "{{.Missing}}"
+	if err := f.Print(nestedData{}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
+
+func TestJSONPathFormatter_Print(t *testing.T) {
+	items := []nestedData{
+		{ID: "a", Viewport: map[string]int{"width": 1920}, Tags: []string{"x", "y"}},
+		{ID: "b"},
+	}
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"{.id}", "a\nb\n"},
+		{"$.viewport.width", "1920\n\n"},
+		{"{.tags[*]}", "x y\n\n"},
+		{"{.tags[-1]}", "y\n\n"},
+		{"{['id']}", "a\nb\n"},
+		{"{.viewport}", "{\"width\":1920}\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			var buf bytes.Buffer
+			f := &JSONPathFormatter{Writer: &buf, Expression: tt.expr}
+			if err := f.Print(items); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	for _, expr := range []string{"", "$..id", "{.tags[x]}", "{.tags[0}", "{.}"} {
+		if _, err := ParseJSONPath(expr); err == nil {
+			t.Errorf("expected parse error for %q", expr)
+		}
+	}
+}
+
+func TestListWriter_JSONMatchesPrint(t *testing.T) {
+	items := []testData{{Name: "a", Count: 1}, {Name: "b", Count: 2}, {Name: "c", Count: 3}}
+
+	var want bytes.Buffer
+	if err := (&JSONFormatter{Writer: &want}).Print(items); err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	lw := NewListWriter(&JSONFormatter{Writer: &got})
+	for _, chunk := range [][]testData{items[:2], {}, items[2:]} {
+		if err := lw.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("expected %q, got %q", want.String(), got.String())
+	}
+
+	got.Reset()
+	lw = NewListWriter(&JSONFormatter{Writer: &got})
+	if err := lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "[]\n" {
+		t.Errorf("expected empty array, got %q", got.String())
+	}
+}
+
+func TestListWriter_Chunks(t *testing.T) {
+	var buf bytes.Buffer
+	lw := NewListWriter(&YAMLFormatter{Writer: &buf})
+	_ = lw.Write([]testData{{Name: "a", Count: 1}})
+	_ = lw.Write([]testData{{Name: "b", Count: 2}})
+	if err := lw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "- count: 1\n  name: a\n- count: 2\n  name: b\n" {
+		t.Errorf("unexpected YAML stream: %q", buf.String())
+	}
+
+	if NewListWriter(&CSVFormatter{Writer: &buf}) != nil || NewListWriter(&TextFormatter{Writer: &buf}) != nil {
+		t.Error("expected CSV and text to have no list writer")
+	}
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// ListWriter prints a list in chunks as they arrive, so long paginated
+// lists do not have to be buffered
+type ListWriter interface {
+	// Write prints the next chunk of items, which must be a slice
+	Write(items any) error
+	// Close finishes the list. It must be called once after the last chunk.
+	Close() error
+}
+
+// NewListWriter returns a ListWriter for formatters whose list output can
+// be produced incrementally, or nil for formats that need every item up
+// front (text tables and CSV, whose layout depends on all rows)
+func NewListWriter(f Formatter) ListWriter {
+	switch f := f.(type) {
+	case *JSONFormatter:
+		return &jsonListWriter{w: f.Writer}
+	case *YAMLFormatter, *NDJSONFormatter, *TemplateFormatter, *JSONPathFormatter:
+		return &chunkListWriter{f: f}
+	}
+	return nil
+}
+
+// jsonListWriter writes a single JSON array, byte-for-byte the same as
+// encoding the whole list at once
+type jsonListWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (lw *jsonListWriter) Write(items any) error {
+	v := reflect.ValueOf(items)
+	for i := 0; i < v.Len(); i++ {
+		data, err := json.Marshal(v.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		sep := ","
+		if lw.count == 0 {
+			sep = "["
+		}
+		if _, err := io.WriteString(lw.w, sep); err != nil {
+			return err
+		}
+		if _, err := lw.w.Write(data); err != nil {
+			return err
+		}
+		lw.count++
+	}
+	return nil
+}
+
+func (lw *jsonListWriter) Close() error {
+	end := "]\n"
+	if lw.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(lw.w, end)
+	return err
+}
+
+// chunkListWriter prints each chunk with the formatter. This works for
+// formats where consecutive lists concatenate into one: line-based formats,
+// and YAML block sequences.
+type chunkListWriter struct {
+	f     Formatter
+	wrote bool
+}
+
+func (lw *chunkListWriter) Write(items any) error {
+	if reflect.ValueOf(items).Len() == 0 {
+		return nil
+	}
+	lw.wrote = true
+	return lw.f.Print(items)
+}
+
+func (lw *chunkListWriter) Close() error {
+	if lw.wrote {
+		return nil
+	}
+	return lw.f.Print([]any{})
+}
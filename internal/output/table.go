@@ -0,0 +1,220 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Column is one column of a list table
+type Column struct {
+	// Name selects the column with --columns and --sort-by
+	Name string
+	// Header is the column title
+	Header string
+	// Path is the dotted JSON field path within each item
+	Path string
+}
+
+// TableSpec describes how a list of one resource type is shown as a table
+type TableSpec struct {
+	// Columns lists the named columns for the resource
+	Columns []Column
+	// Defaults names the columns shown when none are selected
+	Defaults []string
+}
+
+// Column returns the column with the given name. Names that are not part of
+// the spec are treated as JSON field paths.
+func (s TableSpec) Column(name string) Column {
+	for _, c := range s.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return Column{
+		Name:   name,
+		Header: strings.ToUpper(strings.NewReplacer(".", " ", "_", " ").Replace(name)),
+		Path:   name,
+	}
+}
+
+// Resolve returns the columns for the given names, or the default columns
+// when names is empty. Names that neither belong to the spec nor match a
+// field in any record are rejected.
+func (s TableSpec) Resolve(names []string, records []map[string]any) ([]Column, error) {
+	if len(names) == 0 {
+		names = s.Defaults
+	}
+
+	columns := make([]Column, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		col := s.Column(name)
+		if col.Path == name && !s.hasColumn(name) && !anyRecordHas(records, name) {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(s.names(), ", "))
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+func (s TableSpec) hasColumn(name string) bool {
+	for _, c := range s.Columns {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s TableSpec) names() []string {
+	names := make([]string, len(s.Columns))
+	for i, c := range s.Columns {
+		names[i] = c.Name
+	}
+	return names
+}
+
+func anyRecordHas(records []map[string]any, path string) bool {
+	for _, r := range records {
+		if _, ok := lookup(r, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ToRecords converts a slice of API items to generic JSON objects, so that
+// columns can address fields by their JSON names
+func ToRecords(items any) ([]map[string]any, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode items: %w", err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode items: %w", err)
+	}
+	return records, nil
+}
+
+// Lookup returns the value at a dotted path in a record, or nil
+func Lookup(record map[string]any, path string) any {
+	v, _ := lookup(record, path)
+	return v
+}
+
+func lookup(record map[string]any, path string) (any, bool) {
+	var cur any = record
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// SortSlice returns a copy of items (a slice) stably sorted by the value at
+// path in each item. A leading "-" on path sorts in descending order. Items
+// missing the field sort last.
+func SortSlice(items any, path string) (any, error) {
+	desc := strings.HasPrefix(path, "-")
+	path = strings.TrimPrefix(path, "-")
+
+	records, err := ToRecords(items)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := Lookup(records[order[i]], path), Lookup(records[order[j]], path)
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if desc {
+			return compareValues(b, a) < 0
+		}
+		return compareValues(a, b) < 0
+	})
+
+	v := reflect.ValueOf(items)
+	sorted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	for i, idx := range order {
+		sorted.Index(i).Set(v.Index(idx))
+	}
+	return sorted.Interface(), nil
+}
+
+// compareValues orders numbers numerically and everything else by its
+// formatted string, which also orders RFC 3339 timestamps correctly
+func compareValues(a, b any) int {
+	fa, aok := a.(float64)
+	fb, bok := b.(float64)
+	if aok && bok {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// FormatValue renders a JSON value for a table cell
+func FormatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, val); err == nil {
+			return t.Format(time.DateTime)
+		}
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case map[string]any, []any:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+// PrintRecords prints records as a table with the given columns
+func (f *TextFormatter) PrintRecords(columns []Column, records []map[string]any) error {
+	headers := make([]string, len(columns))
+	for i, c := range columns {
+		headers[i] = c.Header
+	}
+
+	rows := make([]map[string]any, len(records))
+	for i, r := range records {
+		row := make(map[string]any, len(columns))
+		for _, c := range columns {
+			row[c.Header] = FormatValue(Lookup(r, c.Path))
+		}
+		rows[i] = row
+	}
+	return f.PrintTable(headers, rows)
+}
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"text/template"
+)
+
+// TemplateFormatter renders data with a Go text/template. Lists are
+// rendered once per item. Fields are addressed by their Go names, e.g.
+// {{.SessionId}}.
+type TemplateFormatter struct {
+	Writer   io.Writer
+	Template string
+}
+
+// ParseTemplate parses a template given with -o template=...
+func ParseTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, fmt.Errorf("template format requires a template, e.g. -o template='{{.Name}}'")
+	}
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			return string(b), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+func (f *TemplateFormatter) Print(data any) error {
+	tmpl, err := ParseTemplate(f.Template)
+	if err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return f.execute(tmpl, data)
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := f.execute(tmpl, v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// execute renders one value, ending it with a newline if the template
+// did not
+func (f *TemplateFormatter) execute(tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("template failed: %w", err)
+	}
+	if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	_, err := f.Writer.Write(buf.Bytes())
+	return err
+}
+
+func (f *TemplateFormatter) PrintError(err error) {
+	printPlainError(err)
+}
@@ -0,0 +1,30 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLFormatter outputs data as YAML
+type YAMLFormatter struct {
+	Writer io.Writer
+}
+
+func (f *YAMLFormatter) Print(data any) error {
+	v, err := toGeneric(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	enc := yaml.NewEncoder(f.Writer)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+func (f *YAMLFormatter) PrintError(err error) {
+	printPlainError(err)
+}
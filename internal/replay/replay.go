@@ -0,0 +1,123 @@
+// Package replay inspects and trims the session replays returned by the API.
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Ext returns the file extension matching the replay's format, or "" if the
+// format is not recognised
+func Ext(data []byte) string {
+	switch {
+	case isWebP(data):
+		return ".webp"
+	case bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		return ".webm"
+	case len(data) >= 8 && string(data[4:8]) == "ftyp":
+		return ".mp4"
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		return ".gif"
+	}
+	return ""
+}
+
+func isWebP(data []byte) bool {
+	return len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+}
+
+// chunk is a RIFF chunk of a WebP file, including its header and padding
+type chunk struct {
+	fourCC string
+	data   []byte
+}
+
+// parseWebP splits a WebP file into its chunks
+func parseWebP(data []byte) ([]chunk, error) {
+	if !isWebP(data) {
+		return nil, errors.New("not a WebP file")
+	}
+	var chunks []chunk
+	for rest := data[12:]; len(rest) > 0; {
+		if len(rest) < 8 {
+			return nil, errors.New("truncated WebP chunk header")
+		}
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		end := 8 + size + size%2
+		if end > len(rest) {
+			// The last chunk may drop its padding byte
+			if 8+size == len(rest) {
+				end = len(rest)
+			} else {
+				return nil, fmt.Errorf("truncated WebP %q chunk", rest[0:4])
+			}
+		}
+		chunks = append(chunks, chunk{fourCC: string(rest[0:4]), data: rest[:end]})
+		rest = rest[end:]
+	}
+	return chunks, nil
+}
+
+// Frames returns the number of frames in an animated WebP replay
+func Frames(data []byte) (int, error) {
+	chunks, err := parseWebP(data)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, c := range chunks {
+		if c.fourCC == "ANMF" {
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, errors.New("WebP file is not animated")
+	}
+	return n, nil
+}
+
+// Trim keeps frames start (inclusive) to stop (exclusive) of an animated
+// WebP replay. A stop of 0, or past the last frame, keeps every frame from
+// start on. Replay frames are full screenshots, so the kept frames do not
+// depend on the dropped ones.
+func Trim(data []byte, start, stop int) ([]byte, error) {
+	if start < 0 || stop < 0 {
+		return nil, fmt.Errorf("invalid frame range %d-%d", start, stop)
+	}
+	chunks, err := parseWebP(data)
+	if err != nil {
+		return nil, err
+	}
+	total, err := Frames(data)
+	if err != nil {
+		return nil, err
+	}
+	if stop == 0 || stop > total {
+		stop = total
+	}
+	if start >= stop {
+		return nil, fmt.Errorf("frame range %d-%d is outside the replay's %d frame(s)", start, stop, total)
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.WriteString("RIFF\x00\x00\x00\x00WEBP")
+	frame := 0
+	for _, c := range chunks {
+		if c.fourCC == "ANMF" {
+			keep := frame >= start && frame < stop
+			frame++
+			if !keep {
+				continue
+			}
+		}
+		out.Write(c.data)
+		if len(c.data)%2 == 1 {
+			out.WriteByte(0)
+		}
+	}
+	trimmed := out.Bytes()
+	binary.LittleEndian.PutUint32(trimmed[4:8], uint32(len(trimmed)-8))
+	return trimmed, nil
+}
@@ -0,0 +1,100 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func riffChunk(fourCC string, payload []byte) []byte {
+	b := make([]byte, 8, 8+len(payload)+1)
+	copy(b, fourCC)
+	binary.LittleEndian.PutUint32(b[4:8], uint32(len(payload)))
+	b = append(b, payload...)
+	if len(payload)%2 == 1 {
+		b = append(b, 0)
+	}
+	return b
+}
+
+// animatedWebP builds an animated WebP whose frames carry the given payloads
+func animatedWebP(frames ...string) []byte {
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", make([]byte, 10))...)
+	body = append(body, riffChunk("ANIM", make([]byte, 6))...)
+	for _, f := range frames {
+		body = append(body, riffChunk("ANMF", []byte(f))...)
+	}
+	out := []byte("RIFF\x00\x00\x00\x00")
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(body)))
+	return append(out, body...)
+}
+
+func TestExt(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{animatedWebP("a"), ".webp"},
+		{[]byte{0x1A, 0x45, 0xDF, 0xA3, 0x01}, ".webm"},
+		{[]byte("\x00\x00\x00\x18ftypisom"), ".mp4"},
+		{[]byte("GIF89a"), ".gif"},
+		{[]byte("replay-data"), ""},
+	}
+	for _, tt := range tests {
+		if got := Ext(tt.data); got != tt.want {
+			t.Errorf("Ext(%q) = %q, want %q", tt.data[:4], got, tt.want)
+		}
+	}
+}
+
+func TestFrames(t *testing.T) {
+	n, err := Frames(animatedWebP("one", "two", "three"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Errorf("expected 3 frames, got %d", n)
+	}
+
+	if _, err := Frames([]byte("replay-data")); err == nil {
+		t.Error("expected error for non-WebP data")
+	}
+}
+
+func TestTrim(t *testing.T) {
+	data := animatedWebP("f0", "f1", "f2", "f3x", "f4")
+
+	got, err := Trim(data, 1, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, animatedWebP("f1", "f2", "f3x")) {
+		t.Errorf("unexpected trimmed replay: %q", got)
+	}
+
+	got, err = Trim(data, 3, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, animatedWebP("f3x", "f4")) {
+		t.Errorf("expected frames 3 to the end, got %q", got)
+	}
+}
+
+func TestTrim_InvalidRange(t *testing.T) {
+	data := animatedWebP("f0", "f1")
+	for _, r := range [][2]int{{2, 0}, {1, 1}, {-1, 1}} {
+		if _, err := Trim(data, r[0], r[1]); err == nil {
+			t.Errorf("expected error for range %v", r)
+		}
+	}
+}
+
+func TestTrim_NotAnimated(t *testing.T) {
+	still := []byte("RIFF\x0c\x00\x00\x00WEBP")
+	still = append(still, riffChunk("VP8 ", []byte("data"))...)
+	if _, err := Trim(still, 0, 1); err == nil {
+		t.Error("expected error for a still image")
+	}
+}
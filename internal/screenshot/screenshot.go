@@ -0,0 +1,205 @@
+// Package screenshot saves page screenshots returned by the API, optionally
+// annotated with the bounding boxes and IDs of the page's actions.
+package screenshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+const (
+	// scale is the size of a font pixel in image pixels
+	scale = 2
+	// border is the width of a box outline
+	border = 2
+	// padding surrounds a label's text
+	padding = 2
+)
+
+// palette cycles through colors so that neighbouring boxes are told apart
+var palette = []color.RGBA{
+	{R: 0xE6, G: 0x19, B: 0x4B, A: 0xFF},
+	{R: 0x3C, G: 0xB4, B: 0x4B, A: 0xFF},
+	{R: 0x43, G: 0x63, B: 0xD8, A: 0xFF},
+	{R: 0xF5, G: 0x82, B: 0x31, A: 0xFF},
+	{R: 0x91, G: 0x1E, B: 0xB4, A: 0xFF},
+	{R: 0x00, G: 0x80, B: 0x80, A: 0xFF},
+}
+
+// Save writes the screenshot to path, as JPEG for .jpg and .jpeg paths and as
+// PNG otherwise. With annotate set, the bounding boxes and action IDs are drawn
+// onto the image. Without it, the screenshot is written unchanged when it is
+// already in the path's format and re-encoded when it is not.
+func Save(path string, shot api.Screenshot, annotate bool) error {
+	raw, err := shot.Raw.Bytes()
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		return errors.New("the response has no screenshot")
+	}
+
+	data := raw
+	format := formatFor(path)
+	if annotate {
+		var boxes []api.BoundingBox
+		if shot.Bboxes != nil {
+			boxes = *shot.Bboxes
+		}
+		if data, err = Annotate(raw, boxes, format); err != nil {
+			return err
+		}
+	} else if sniffed := sniffFormat(raw); sniffed != "" && sniffed != format {
+		if data, err = Annotate(raw, nil, format); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write screenshot: %w", err)
+	}
+	return nil
+}
+
+// sniffFormat returns "png" or "jpeg" from the image's signature, or "" for
+// any other data
+func sniffFormat(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return "png"
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return "jpeg"
+	default:
+		return ""
+	}
+}
+
+func formatFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jpg", ".jpeg":
+		return "jpeg"
+	default:
+		return "png"
+	}
+}
+
+// Annotate decodes a PNG or JPEG screenshot, outlines each bounding box and
+// labels it with its action ID, and encodes the result as format ("png" or
+// "jpeg").
+//
+// Boxes are in CSS pixels relative to their frame's viewport. They are
+// offset by their iframe and scaled to the image, which may be larger than
+// the viewport on high density displays.
+func Annotate(raw []byte, boxes []api.BoundingBox, format string) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+
+	img := image.NewRGBA(src.Bounds())
+	draw.Draw(img, img.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	for i, box := range boxes {
+		c := palette[i%len(palette)]
+		r := boxRect(box, img.Bounds())
+		if r.Empty() {
+			continue
+		}
+		drawOutline(img, r, c)
+		if box.NotteId != nil && *box.NotteId != "" {
+			drawLabel(img, r, *box.NotteId, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	case "png":
+		err = png.Encode(&buf, img)
+	default:
+		return nil, fmt.Errorf("unsupported image format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode screenshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// boxRect converts a bounding box to image pixels, clipped to bounds
+func boxRect(box api.BoundingBox, bounds image.Rectangle) image.Rectangle {
+	sx, sy := 1.0, 1.0
+	if box.ViewportWidth > 0 {
+		sx = float64(bounds.Dx()) / float64(box.ViewportWidth)
+	}
+	if box.ViewportHeight > 0 {
+		sy = float64(bounds.Dy()) / float64(box.ViewportHeight)
+	}
+
+	x, y := float64(box.X), float64(box.Y)
+	if box.IframeOffsetX != nil {
+		x += float64(*box.IframeOffsetX)
+	}
+	if box.IframeOffsetY != nil {
+		y += float64(*box.IframeOffsetY)
+	}
+
+	r := image.Rect(
+		int(x*sx), int(y*sy),
+		int((x+float64(box.Width))*sx), int((y+float64(box.Height))*sy),
+	)
+	return r.Add(bounds.Min).Intersect(bounds)
+}
+
+func drawOutline(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	u := image.NewUniform(c)
+	b := min(border, r.Dx(), r.Dy())
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+b), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Max.Y-b, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Min.X, r.Min.Y, r.Min.X+b, r.Max.Y), u, image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(r.Max.X-b, r.Min.Y, r.Max.X, r.Max.Y), u, image.Point{}, draw.Src)
+}
+
+// drawLabel draws text on a filled tag above the box's top-left corner, or
+// inside the box when there is no room above it
+func drawLabel(img *image.RGBA, r image.Rectangle, text string, c color.RGBA) {
+	runes := []rune(text)
+	w := len(runes)*(glyphWidth+1)*scale - scale + 2*padding
+	h := glyphHeight*scale + 2*padding
+
+	tag := image.Rect(r.Min.X, r.Min.Y-h, r.Min.X+w, r.Min.Y)
+	if tag.Min.Y < img.Bounds().Min.Y {
+		tag = tag.Add(image.Pt(0, h))
+	}
+	if over := tag.Max.X - img.Bounds().Max.X; over > 0 {
+		tag = tag.Sub(image.Pt(over, 0))
+	}
+	draw.Draw(img, tag, image.NewUniform(c), image.Point{}, draw.Src)
+
+	white := color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	x := tag.Min.X + padding
+	for _, ch := range runes {
+		g := glyph(ch)
+		for row := 0; row < glyphHeight; row++ {
+			for col := 0; col < glyphWidth; col++ {
+				if g[row]&(1<<(glyphWidth-1-col)) == 0 {
+					continue
+				}
+				px := image.Rect(0, 0, scale, scale).Add(image.Pt(x+col*scale, tag.Min.Y+padding+row*scale))
+				draw.Draw(img, px.Intersect(img.Bounds()), image.NewUniform(white), image.Point{}, draw.Src)
+			}
+		}
+		x += (glyphWidth + 1) * scale
+	}
+}
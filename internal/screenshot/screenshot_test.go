@@ -0,0 +1,126 @@
+package screenshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/salmonumbrella/notte-cli/internal/api"
+)
+
+func whitePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testShot(t *testing.T, raw []byte, boxes []api.BoundingBox) api.Screenshot {
+	t.Helper()
+	data, err := json.Marshal(map[string]any{"raw": raw, "bboxes": boxes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shot api.Screenshot
+	if err := json.Unmarshal(data, &shot); err != nil {
+		t.Fatal(err)
+	}
+	return shot
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestAnnotate_ScalesBoxes(t *testing.T) {
+	raw := whitePNG(t, 200, 100)
+	// The viewport is half the image size, as on a 2x display
+	boxes := []api.BoundingBox{{
+		X: 20, Y: 20, Width: 30, Height: 10,
+		ViewportWidth: 100, ViewportHeight: 50,
+		NotteId: strPtr("B1"),
+	}}
+
+	out, err := Annotate(raw, boxes, "png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := palette[0]
+	for _, p := range []image.Point{{40, 40}, {99, 59}, {70, 40}, {40, 50}} {
+		if got := color.RGBAModel.Convert(img.At(p.X, p.Y)).(color.RGBA); got != want {
+			t.Errorf("expected outline at %v, got %v", p, got)
+		}
+	}
+	if got := color.RGBAModel.Convert(img.At(70, 50)).(color.RGBA); got != (color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}) {
+		t.Errorf("expected the inside of the box untouched, got %v", got)
+	}
+	// The label sits above the box
+	if got := color.RGBAModel.Convert(img.At(40, 39)).(color.RGBA); got != want {
+		t.Errorf("expected label above the box, got %v", got)
+	}
+}
+
+func TestAnnotate_InvalidImage(t *testing.T) {
+	if _, err := Annotate([]byte("not an image"), nil, "png"); err == nil {
+		t.Error("expected decode error")
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	raw := whitePNG(t, 40, 40)
+	boxes := []api.BoundingBox{{X: 0, Y: 0, Width: 20, Height: 20, ViewportWidth: 40, ViewportHeight: 40, NotteId: strPtr("L12")}}
+	shot := testShot(t, raw, boxes)
+
+	plain := filepath.Join(dir, "plain.png")
+	if err := Save(plain, shot, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(plain); !bytes.Equal(got, raw) {
+		t.Error("expected the raw screenshot to be written unchanged")
+	}
+
+	converted := filepath.Join(dir, "plain.jpg")
+	if err := Save(converted, shot, false); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(converted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, format, err := image.Decode(bytes.NewReader(data)); err != nil || format != "jpeg" {
+		t.Errorf("expected a PNG screenshot saved as .jpg to be a JPEG, got %q, %v", format, err)
+	}
+
+	annotated := filepath.Join(dir, "annotated.jpg")
+	if err := Save(annotated, shot, true); err != nil {
+		t.Fatal(err)
+	}
+	data, err = os.ReadFile(annotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, format, err := image.Decode(bytes.NewReader(data)); err != nil || format != "jpeg" {
+		t.Errorf("expected a JPEG, got %q, %v", format, err)
+	}
+
+	if err := Save(filepath.Join(dir, "empty.png"), api.Screenshot{}, false); err == nil {
+		t.Error("expected error for a missing screenshot")
+	}
+}
@@ -2,8 +2,11 @@
 package testutil
 
 import (
+	"bytes"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"sync"
 )
 
@@ -18,6 +21,7 @@ type MockResponse struct {
 type RecordedRequest struct {
 	Method  string
 	Path    string
+	Query   url.Values
 	Headers http.Header
 	Body    string
 }
@@ -27,6 +31,7 @@ type MockServer struct {
 	server    *httptest.Server
 	mu        sync.RWMutex
 	responses map[string]MockResponse
+	sequences map[string][]MockResponse
 	requests  map[string][]RecordedRequest
 }
 
@@ -34,16 +39,14 @@ type MockServer struct {
 func NewMockServer() *MockServer {
 	ms := &MockServer{
 		responses: make(map[string]MockResponse),
+		sequences: make(map[string][]MockResponse),
 		requests:  make(map[string][]RecordedRequest),
 	}
 
 	ms.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ms.recordRequest(r)
 
-		ms.mu.RLock()
-		resp, ok := ms.responses[r.URL.Path]
-		ms.mu.RUnlock()
-
+		resp, ok := ms.nextResponse(r.URL.Path)
 		if !ok {
 			w.WriteHeader(http.StatusNotFound)
 			_, _ = w.Write([]byte(`{"error": "not found"}`))
@@ -67,12 +70,36 @@ func (ms *MockServer) recordRequest(r *http.Request) {
 	rec := RecordedRequest{
 		Method:  r.Method,
 		Path:    r.URL.Path,
+		Query:   r.URL.Query(),
 		Headers: r.Header.Clone(),
 	}
 
+	if r.Body != nil {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		rec.Body = string(body)
+	}
+
 	ms.requests[r.URL.Path] = append(ms.requests[r.URL.Path], rec)
 }
 
+// nextResponse returns the response for a path, advancing any sequence
+func (ms *MockServer) nextResponse(path string) (MockResponse, bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if seq := ms.sequences[path]; len(seq) > 0 {
+		resp := seq[0]
+		if len(seq) > 1 {
+			ms.sequences[path] = seq[1:]
+		}
+		return resp, true
+	}
+
+	resp, ok := ms.responses[path]
+	return resp, ok
+}
+
 // AddResponse adds a canned response for a path
 func (ms *MockServer) AddResponse(path string, statusCode int, body string) {
 	ms.mu.Lock()
@@ -85,6 +112,23 @@ func (ms *MockServer) AddResponse(path string, statusCode int, body string) {
 	}
 }
 
+// AddResponseSequence adds responses served in order for a path. The last
+// body is repeated once the sequence is exhausted.
+func (ms *MockServer) AddResponseSequence(path string, statusCode int, bodies ...string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	seq := make([]MockResponse, 0, len(bodies))
+	for _, body := range bodies {
+		seq = append(seq, MockResponse{
+			StatusCode: statusCode,
+			Body:       body,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+		})
+	}
+	ms.sequences[path] = seq
+}
+
 // AddResponseWithHeaders adds a response with custom headers
 func (ms *MockServer) AddResponseWithHeaders(path string, statusCode int, body string, headers map[string]string) {
 	ms.mu.Lock()
@@ -128,6 +172,7 @@ func (ms *MockServer) Reset() {
 	defer ms.mu.Unlock()
 
 	ms.responses = make(map[string]MockResponse)
+	ms.sequences = make(map[string][]MockResponse)
 	ms.requests = make(map[string][]RecordedRequest)
 }
 
@@ -64,3 +64,28 @@ func TestMockServer_Returns404ForUnknownPaths(t *testing.T) {
 		t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusNotFound)
 	}
 }
+
+func TestMockServer_ResponseSequence(t *testing.T) {
+	server := NewMockServer()
+	defer server.Close()
+
+	server.AddResponseSequence("/poll", http.StatusOK, `first`, `second`)
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		resp, err := http.Get(server.URL() + "/poll")
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		got = append(got, string(body))
+	}
+
+	want := []string{"first", "second", "second"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("response %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -26,8 +26,8 @@ func SetupTestEnv(t *testing.T) *TestEnv {
 		MockStore: NewMockKeyring(),
 	}
 
-	// Clear auth-related env vars
-	for _, key := range []string{"NOTTE_API_KEY", "NOTTE_API_URL"} {
+	// Clear auth- and session-related env vars
+	for _, key := range []string{"NOTTE_API_KEY", "NOTTE_API_URL", "NOTTE_CONTEXT", "NOTTE_SESSION_ID", "NOTTE_SESSION"} {
 		env.origEnv[key] = os.Getenv(key)
 		_ = os.Unsetenv(key)
 	}
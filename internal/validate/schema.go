@@ -0,0 +1,343 @@
+// internal/validate/schema.go
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a parsed JSON Schema used to check API responses client-side.
+//
+// It supports the subset of JSON Schema produced by Pydantic and most
+// hand-written schemas: type, properties, required, additionalProperties,
+// items, enum, const, numeric and length bounds, pattern, allOf/anyOf/oneOf
+// and local $ref into $defs or definitions.
+type Schema struct {
+	root map[string]any
+}
+
+// SchemaViolation describes one place where a value does not match a schema
+type SchemaViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v SchemaViolation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ParseSchema parses a JSON Schema document
+func ParseSchema(data []byte) (*Schema, error) {
+	var root any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %w", err)
+	}
+	m, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid JSON schema: expected an object, got %s", jsonType(root))
+	}
+	return &Schema{root: m}, nil
+}
+
+// Raw returns the decoded schema document, suitable for sending to the API
+func (s *Schema) Raw() map[string]any {
+	return s.root
+}
+
+// Validate checks a decoded JSON value against the schema and returns every
+// violation found, sorted by path. A nil result means the value conforms.
+func (s *Schema) Validate(value any) []SchemaViolation {
+	var out []SchemaViolation
+	s.validate(s.root, value, "$", &out, 0)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// maxSchemaDepth guards against recursive $ref cycles
+const maxSchemaDepth = 64
+
+func (s *Schema) validate(schema map[string]any, value any, path string, out *[]SchemaViolation, depth int) {
+	if depth > maxSchemaDepth {
+		*out = append(*out, SchemaViolation{path, "schema nesting too deep"})
+		return
+	}
+
+	add := func(format string, args ...any) {
+		*out = append(*out, SchemaViolation{path, fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		target, err := s.resolveRef(ref)
+		if err != nil {
+			add("%v", err)
+			return
+		}
+		s.validate(target, value, path, out, depth+1)
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		add("expected %s, got %s", describeType(t), jsonType(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			add("value %s is not one of %s", compactJSON(value), compactJSON(enum))
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, value) {
+		add("expected %s, got %s", compactJSON(c), compactJSON(value))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		s.validateObject(schema, v, path, out, depth)
+	case []any:
+		s.validateArray(schema, v, path, out, depth)
+	case string:
+		n := utf8.RuneCountInString(v)
+		if lo, ok := number(schema["minLength"]); ok && float64(n) < lo {
+			add("length %d is shorter than minLength %v", n, lo)
+		}
+		if hi, ok := number(schema["maxLength"]); ok && float64(n) > hi {
+			add("length %d is longer than maxLength %v", n, hi)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				add("invalid pattern %q in schema", pattern)
+			} else if !re.MatchString(v) {
+				add("%q does not match pattern %q", v, pattern)
+			}
+		}
+	case float64:
+		if lo, ok := number(schema["minimum"]); ok && v < lo {
+			add("%v is less than minimum %v", v, lo)
+		}
+		if hi, ok := number(schema["maximum"]); ok && v > hi {
+			add("%v is greater than maximum %v", v, hi)
+		}
+		if lo, ok := number(schema["exclusiveMinimum"]); ok && v <= lo {
+			add("%v is not greater than %v", v, lo)
+		}
+		if hi, ok := number(schema["exclusiveMaximum"]); ok && v >= hi {
+			add("%v is not less than %v", v, hi)
+		}
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			if m, ok := sub.(map[string]any); ok {
+				s.validate(m, value, path, out, depth+1)
+			}
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		if s.countMatches(anyOf, value, depth) == 0 {
+			add("value does not match any of the allowed schemas")
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		if n := s.countMatches(oneOf, value, depth); n != 1 {
+			add("value matches %d of the oneOf schemas, expected exactly 1", n)
+		}
+	}
+}
+
+func (s *Schema) validateObject(schema map[string]any, obj map[string]any, path string, out *[]SchemaViolation, depth int) {
+	props, _ := schema["properties"].(map[string]any)
+
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, present := obj[name]; !present {
+				*out = append(*out, SchemaViolation{childPath(path, name), "required field is missing"})
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if sub, ok := props[k].(map[string]any); ok {
+			s.validate(sub, obj[k], childPath(path, k), out, depth+1)
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				*out = append(*out, SchemaViolation{childPath(path, k), "unexpected field"})
+			}
+		case map[string]any:
+			s.validate(extra, obj[k], childPath(path, k), out, depth+1)
+		}
+	}
+}
+
+func (s *Schema) validateArray(schema map[string]any, arr []any, path string, out *[]SchemaViolation, depth int) {
+	if lo, ok := number(schema["minItems"]); ok && float64(len(arr)) < lo {
+		*out = append(*out, SchemaViolation{path, fmt.Sprintf("has %d items, fewer than minItems %v", len(arr), lo)})
+	}
+	if hi, ok := number(schema["maxItems"]); ok && float64(len(arr)) > hi {
+		*out = append(*out, SchemaViolation{path, fmt.Sprintf("has %d items, more than maxItems %v", len(arr), hi)})
+	}
+
+	items, ok := schema["items"].(map[string]any)
+	if !ok {
+		return
+	}
+	for i, item := range arr {
+		s.validate(items, item, path+"["+strconv.Itoa(i)+"]", out, depth+1)
+	}
+}
+
+// countMatches returns how many of the sub-schemas the value conforms to
+func (s *Schema) countMatches(schemas []any, value any, depth int) int {
+	n := 0
+	for _, sub := range schemas {
+		m, ok := sub.(map[string]any)
+		if !ok {
+			continue
+		}
+		var violations []SchemaViolation
+		s.validate(m, value, "$", &violations, depth+1)
+		if len(violations) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// resolveRef resolves a local JSON pointer such as #/$defs/Item
+func (s *Schema) resolveRef(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+
+	var node any = s.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/") {
+		if part == "" {
+			continue
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+		node, ok = m[part]
+		if !ok {
+			return nil, fmt.Errorf("unresolvable $ref %q", ref)
+		}
+	}
+
+	m, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unresolvable $ref %q", ref)
+	}
+	return m, nil
+}
+
+// matchesType reports whether value has one of the schema's types
+func matchesType(t any, value any) bool {
+	switch tt := t.(type) {
+	case string:
+		return matchesSingleType(tt, value)
+	case []any:
+		for _, item := range tt {
+			if name, ok := item.(string); ok && matchesSingleType(name, value) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
+
+func matchesSingleType(name string, value any) bool {
+	switch name {
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonType(value) == name
+	}
+}
+
+func describeType(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, 0, len(list))
+		for _, item := range list {
+			names = append(names, fmt.Sprint(item))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func jsonEqual(a, b any) bool {
+	return compactJSON(a) == compactJSON(b)
+}
+
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// childPath appends an object key to a JSONPath-style location
+func childPath(path, key string) string {
+	if identifierPattern.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
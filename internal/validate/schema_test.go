@@ -0,0 +1,114 @@
+// internal/validate/schema_test.go
+package validate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const productSchema = `{
+	"type": "object",
+	"required": ["name", "price", "tags"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1},
+		"price": {"type": "number", "minimum": 0},
+		"stock": {"type": "integer"},
+		"status": {"enum": ["new", "used"]},
+		"tags": {"type": "array", "items": {"$ref": "#/$defs/Tag"}},
+		"sku": {"anyOf": [{"type": "string", "pattern": "^[A-Z]{3}-\\d+$"}, {"type": "null"}]}
+	},
+	"$defs": {
+		"Tag": {"type": "object", "required": ["label"], "properties": {"label": {"type": "string"}}}
+	}
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return v
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := ParseSchema([]byte(productSchema))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "valid",
+			input: `{"name":"Lamp","price":12.5,"stock":3,"status":"new","tags":[{"label":"home"}],"sku":"ABC-12"}`,
+		},
+		{
+			name:  "null in anyOf",
+			input: `{"name":"Lamp","price":1,"tags":[],"sku":null}`,
+		},
+		{
+			name:  "missing and wrong types",
+			input: `{"name":"","price":"12","tags":[{"label":1},{}]}`,
+			want: []string{
+				"$.name: length 0 is shorter than minLength 1",
+				"$.price: expected number, got string",
+				"$.tags[0].label: expected string, got number",
+				"$.tags[1].label: required field is missing",
+			},
+		},
+		{
+			name:  "extra field, enum, integer and pattern",
+			input: `{"name":"Lamp","price":-1,"stock":1.5,"status":"broken","tags":[],"sku":"abc","color":"red"}`,
+			want: []string{
+				"$.color: unexpected field",
+				"$.price: -1 is less than minimum 0",
+				"$.sku: value does not match any of the allowed schemas",
+				`$.status: value "broken" is not one of ["new","used"]`,
+				"$.stock: expected integer, got number",
+			},
+		},
+		{
+			name:  "wrong root type",
+			input: `["Lamp"]`,
+			want:  []string{"$: expected object, got array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := schema.Validate(decode(t, tt.input))
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("violations mismatch\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseSchema_Errors(t *testing.T) {
+	for _, input := range []string{`not json`, `[]`, `"string"`} {
+		if _, err := ParseSchema([]byte(input)); err == nil {
+			t.Errorf("ParseSchema(%q) expected error", input)
+		}
+	}
+}
+
+func TestSchema_UnresolvableRef(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"$ref":"#/$defs/Missing"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	violations := schema.Validate(map[string]any{})
+	if len(violations) != 1 || !strings.Contains(violations[0].Message, "unresolvable $ref") {
+		t.Errorf("expected unresolvable ref violation, got %v", violations)
+	}
+}
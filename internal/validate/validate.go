@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strings"
 	"time"
+
+	"github.com/salmonumbrella/notte-cli/internal/output"
 )
 
 // URL validates that a string is a valid HTTP/HTTPS URL
@@ -82,18 +85,24 @@ func Duration(s string) error {
 	return nil
 }
 
-// OutputFormat validates output format flag
+// OutputFormat validates output format flag. Template and JSONPath formats
+// are parsed so that mistakes are reported before any request is made.
 func OutputFormat(s string) error {
-	valid := map[string]bool{
-		"text": true,
-		"json": true,
-	}
-
-	if !valid[s] {
-		return fmt.Errorf("invalid output format: expected text|json, got %q", s)
-	}
-
-	return nil
+	kind, arg := output.Format(s).Split()
+	switch kind {
+	case output.FormatText, output.FormatJSON, output.FormatYAML, output.FormatCSV, output.FormatNDJSON:
+		if arg != "" || strings.Contains(s, "=") {
+			return fmt.Errorf("invalid output format: %q takes no argument", kind)
+		}
+		return nil
+	case output.FormatTemplate:
+		_, err := output.ParseTemplate(arg)
+		return err
+	case output.FormatJSONPath:
+		_, err := output.ParseJSONPath(arg)
+		return err
+	}
+	return fmt.Errorf("invalid output format: expected text|json|yaml|csv|ndjson|template=...|jsonpath=..., got %q", s)
 }
 
 // NonEmpty validates that a string is not empty
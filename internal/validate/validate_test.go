@@ -127,7 +127,16 @@ func TestOutputFormat(t *testing.T) {
 	}{
 		{"text", false},
 		{"json", false},
-		{"yaml", true},
+		{"yaml", false},
+		{"csv", false},
+		{"ndjson", false},
+		{"template={{.Name}}", false},
+		{"template={{.Name", true},
+		{"template=", true},
+		{"jsonpath={.items[*].name}", false},
+		{"jsonpath=$..name", true},
+		{"json=x", true},
+		{"xml", true},
 		{"", true},
 	}
 